@@ -0,0 +1,80 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/logger"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// slowQueryThreshold は遅いクエリとして WARN で記録する処理時間
+const slowQueryThreshold = 200 * time.Millisecond
+
+// gormLogger は GORM のログを pkg/logger に出力する（gormlogger.Interface）
+// 実行したSQLは DEBUG、遅いクエリは WARN、失敗したSQLは ERROR で記録する
+// パラメータの値（タスクのタイトルなど）は伏せ字にできないため記録せず、SQLはプレースホルダーのまま記録する
+type gormLogger struct {
+	log   *logger.Logger
+	level gormlogger.LogLevel
+}
+
+// newGormLogger は log に出力する gormLogger を作成する
+func newGormLogger(log *logger.Logger) *gormLogger {
+	return &gormLogger{log: log, level: gormlogger.Info}
+}
+
+// LogMode は出力するGORMのログレベルを変更したコピーを返す（Silent の場合は何も出力しない）
+func (l *gormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	clone := *l
+	clone.level = level
+	return &clone
+}
+
+// Info は情報レベルのログを出力する
+func (l *gormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Info {
+		l.log.WithContext(ctx).Infof(msg, data...)
+	}
+}
+
+// Warn は警告レベルのログを出力する
+func (l *gormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		l.log.WithContext(ctx).Warnf(msg, data...)
+	}
+}
+
+// Error はエラーレベルのログを出力する
+func (l *gormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Error {
+		l.log.WithContext(ctx).Errorf(msg, data...)
+	}
+}
+
+// Trace は実行したSQLを記録する（レコードが見つからなかった場合はエラーとして扱わない）
+// SQLの組み立てには時間がかかるため、出力しない場合は fc を呼び出さない
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= gormlogger.Error:
+		sql, rows := fc()
+		l.log.WithContext(ctx).Errorw("SQLの実行に失敗しました", "sql", sql, "rows", rows, "duration", elapsed, "error", err)
+	case elapsed > slowQueryThreshold && l.level >= gormlogger.Warn:
+		sql, rows := fc()
+		l.log.WithContext(ctx).Warnw("SQLの実行に時間がかかりました", "sql", sql, "rows", rows, "duration", elapsed)
+	case l.level >= gormlogger.Info && l.log.Enabled(logger.DEBUG):
+		sql, rows := fc()
+		l.log.WithContext(ctx).Debugw("SQLを実行しました", "sql", sql, "rows", rows, "duration", elapsed)
+	}
+}
+
+// ParamsFilter はログに記録するSQLからパラメータの値を除く（gorm.ParamsFilter）
+func (l *gormLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	return sql, nil
+}
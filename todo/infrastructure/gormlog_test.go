@@ -0,0 +1,49 @@
+package infrastructure
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestGormLogger(t *testing.T) {
+	db, err := InitDB("sqlite://file::memory:", WithMaxOpenConns(1))
+	require.NoError(t, err)
+	defer CloseDB(db)
+	require.NoError(t, db.Exec("CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT)").Error)
+
+	var out bytes.Buffer
+	root := logger.New(&out, &out, logger.WithFormat(logger.FormatJSON))
+	session := db.Session(&gorm.Session{Logger: newGormLogger(root.Named(logger.ComponentRepository))})
+
+	// ルートのレベル（INFO）では成功したSQLを記録しない
+	require.NoError(t, session.Table("items").Create(map[string]interface{}{"name": "病院の予約"}).Error)
+	assert.Empty(t, out.String())
+
+	// コンポーネント repository を DEBUG にすると、パラメータを除いたSQLを記録する
+	require.NoError(t, root.SetComponentLevel(logger.ComponentRepository, logger.DEBUG))
+	var names []string
+	require.NoError(t, session.Table("items").Where("name = ?", "病院の予約").Pluck("name", &names).Error)
+	assert.Error(t, session.Table("missing").Pluck("name", &names).Error)
+
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry), line)
+		entries = append(entries, entry)
+	}
+	require.Len(t, entries, 2)
+	assert.Equal(t, "DEBUG", entries[0]["level"])
+	assert.Equal(t, "repository", entries[0]["component"])
+	assert.Equal(t, "SELECT `name` FROM `items` WHERE name = ?", entries[0]["sql"])
+	assert.Equal(t, "ERROR", entries[1]["level"])
+	assert.Equal(t, "SQLの実行に失敗しました", entries[1]["msg"])
+	assert.Contains(t, entries[1]["error"], "no such table: missing")
+	assert.NotContains(t, out.String(), "病院の予約")
+}
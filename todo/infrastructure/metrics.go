@@ -0,0 +1,82 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"gorm.io/gorm"
+)
+
+// metricsNamespace はデータベースのメトリクス名の接頭辞（サーバーのメトリクスと揃える）
+const metricsNamespace = "todo"
+
+// queryStartKey は問い合わせを始めた時刻を GORM のステートメントに保存するキー
+const queryStartKey = "metrics:query_start"
+
+// RegisterMetrics はデータベースのメトリクスを reg に登録する関数
+// 問い合わせの種類（create, query, update, delete, row, raw）とテーブルごとの処理時間・エラー数と、コネクションプールの状態を記録する
+func RegisterMetrics(db *gorm.DB, reg prometheus.Registerer) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("コネクションプールを取得できません: %w", err)
+	}
+
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "データベースへの問い合わせにかかった時間（秒）",
+		Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 14), // 0.5ms〜約4秒
+	}, []string{"operation", "table"})
+	queryErrors := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "db",
+		Name:      "query_errors_total",
+		Help:      "失敗したデータベースへの問い合わせの数（レコードが見つからなかった場合を除く）",
+	}, []string{"operation", "table"})
+	for _, c := range []prometheus.Collector{duration, queryErrors, collectors.NewDBStatsCollector(sqlDB, db.Dialector.Name())} {
+		if err := reg.Register(c); err != nil {
+			return fmt.Errorf("データベースのメトリクスを登録できません: %w", err)
+		}
+	}
+
+	before := func(tx *gorm.DB) {
+		tx.InstanceSet(queryStartKey, time.Now())
+	}
+	after := func(operation string) func(*gorm.DB) {
+		return func(tx *gorm.DB) {
+			v, ok := tx.InstanceGet(queryStartKey)
+			if !ok {
+				return
+			}
+			start, _ := v.(time.Time)
+			duration.WithLabelValues(operation, tx.Statement.Table).Observe(time.Since(start).Seconds())
+			if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+				queryErrors.WithLabelValues(operation, tx.Statement.Table).Inc()
+			}
+		}
+	}
+
+	cb := db.Callback()
+	err = errors.Join(
+		cb.Create().Before("*").Register("metrics:before_create", before),
+		cb.Create().After("*").Register("metrics:after_create", after("create")),
+		cb.Query().Before("*").Register("metrics:before_query", before),
+		cb.Query().After("*").Register("metrics:after_query", after("query")),
+		cb.Update().Before("*").Register("metrics:before_update", before),
+		cb.Update().After("*").Register("metrics:after_update", after("update")),
+		cb.Delete().Before("*").Register("metrics:before_delete", before),
+		cb.Delete().After("*").Register("metrics:after_delete", after("delete")),
+		cb.Row().Before("*").Register("metrics:before_row", before),
+		cb.Row().After("*").Register("metrics:after_row", after("row")),
+		cb.Raw().Before("*").Register("metrics:before_raw", before),
+		cb.Raw().After("*").Register("metrics:after_raw", after("raw")),
+	)
+	if err != nil {
+		return fmt.Errorf("問い合わせの計測を登録できません: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package infrastructure
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterMetrics(t *testing.T) {
+	db, err := InitDB("sqlite://file::memory:", WithMaxOpenConns(1))
+	require.NoError(t, err)
+	defer CloseDB(db)
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, RegisterMetrics(db, reg))
+
+	require.NoError(t, db.Exec("CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT)").Error)
+	require.NoError(t, db.Table("items").Create(map[string]interface{}{"name": "買い物"}).Error)
+	var names []string
+	require.NoError(t, db.Table("items").Pluck("name", &names).Error)
+	assert.Error(t, db.Table("missing").Pluck("name", &names).Error)
+
+	t.Run("問い合わせの種類とテーブルごとに処理時間を記録する", func(t *testing.T) {
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP todo_db_query_errors_total 失敗したデータベースへの問い合わせの数（レコードが見つからなかった場合を除く）
+# TYPE todo_db_query_errors_total counter
+todo_db_query_errors_total{operation="query",table="missing"} 1
+`), "todo_db_query_errors_total"))
+		count, err := testutil.GatherAndCount(reg, "todo_db_query_duration_seconds")
+		require.NoError(t, err)
+		assert.Equal(t, 4, count) // raw（CREATE TABLE）、create、query（items と missing）
+	})
+
+	t.Run("コネクションプールの状態を記録する", func(t *testing.T) {
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP go_sql_max_open_connections Maximum number of open connections to the database.
+# TYPE go_sql_max_open_connections gauge
+go_sql_max_open_connections{db_name="sqlite"} 1
+`), "go_sql_max_open_connections"))
+	})
+
+	t.Run("同じレジストリには登録できない", func(t *testing.T) {
+		assert.Error(t, RegisterMetrics(db, reg))
+	})
+}
@@ -0,0 +1,349 @@
+// Package migration はバージョン管理されたスキーマのマイグレーションを実行する
+//
+// マイグレーションは sql/<データベース>/<バージョン>_<名前>.up.sql（と .down.sql）に記述し、
+// バージョンの順に適用する。適用済みのマイグレーションは schema_migrations テーブルに
+// チェックサムと一緒に記録し、適用後にSQLが変更された場合は検出してエラーにする。
+package migration
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed sql
+var sqlFiles embed.FS
+
+// ErrSchemaBehind は未適用のマイグレーションがあることを表す
+var ErrSchemaBehind = errors.New("未適用のマイグレーションがあります")
+
+// Migration は1つのスキーマ変更
+type Migration struct {
+	Version int64  // 適用順を決めるバージョン
+	Name    string // マイグレーションの名前
+	Up      string // 適用するSQL
+	Down    string // 取り消すSQL（空の場合は取り消せない）
+}
+
+// Checksum は変更を検出するためのSQLのハッシュ値
+func (m Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(m.Up + "\x00" + m.Down))
+	return hex.EncodeToString(sum[:])
+}
+
+// String はログやエラーメッセージで使う表記（例: 0001_create_todos）
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+// schemaMigration は schema_migrations テーブルの1行
+type schemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"size:255;not null"`
+	Checksum  string    `gorm:"size:64;not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName は適用履歴を記録するテーブル名
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Status はマイグレーションの適用状況
+type Status struct {
+	Migration
+	Applied   bool      // 適用済みかどうか
+	AppliedAt time.Time // 適用日時（未適用の場合はゼロ値）
+	Modified  bool      // 適用後にSQLが変更されているか
+	Unknown   bool      // データベースには記録があるが、このアプリケーションに定義がないか
+}
+
+// Migrator はマイグレーションを適用・取り消しする
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// New はデータベースの種類に対応するマイグレーションを読み込んだMigratorを作成する
+func New(db *gorm.DB) (*Migrator, error) {
+	migrations, err := Load(sqlFiles, path.Join("sql", db.Dialector.Name()))
+	if err != nil {
+		return nil, err
+	}
+	return NewWithMigrations(db, migrations), nil
+}
+
+// NewWithMigrations は指定したマイグレーションを適用するMigratorを作成する
+func NewWithMigrations(db *gorm.DB, migrations []Migration) *Migrator {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	return &Migrator{db: db, migrations: sorted}
+}
+
+// WithContext は ctx が終了した時点でデータベースへの問い合わせを中断するMigratorを返す
+func (m *Migrator) WithContext(ctx context.Context) *Migrator {
+	return &Migrator{db: m.db.WithContext(ctx), migrations: m.migrations}
+}
+
+// fileName はマイグレーションのファイル名の形式（<バージョン>_<名前>.up.sql / .down.sql）
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Load は指定したディレクトリのSQLファイルからマイグレーションを読み込む
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("マイグレーションを読み込めません（%s）: %w", dir, err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			return nil, fmt.Errorf("マイグレーションのファイル名が不正です: %s", entry.Name())
+		}
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("バージョン %d のマイグレーションが重複しています: %s, %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if strings.TrimSpace(m.Up) == "" {
+			return nil, fmt.Errorf("マイグレーション %s に .up.sql がありません", m)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Status はすべてのマイグレーションの適用状況をバージョン順に返す
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	known := make(map[int64]bool, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+		s := Status{Migration: migration}
+		if row, ok := applied[migration.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = row.AppliedAt
+			s.Modified = row.Checksum != migration.Checksum()
+		}
+		statuses = append(statuses, s)
+	}
+	for version, row := range applied {
+		if !known[version] {
+			statuses = append(statuses, Status{
+				Migration: Migration{Version: version, Name: row.Name},
+				Applied:   true,
+				AppliedAt: row.AppliedAt,
+				Unknown:   true,
+			})
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// Check はスキーマが最新かどうかを確認する
+// 未適用のマイグレーションがある場合は ErrSchemaBehind を含むエラーを返す
+func (m *Migrator) Check() error {
+	statuses, err := m.Status()
+	if err != nil {
+		return err
+	}
+	if err := verify(statuses); err != nil {
+		return err
+	}
+
+	pending := 0
+	for _, s := range statuses {
+		if !s.Applied {
+			pending++
+		}
+	}
+	if pending > 0 {
+		return fmt.Errorf("%w（%d 件）", ErrSchemaBehind, pending)
+	}
+	return nil
+}
+
+// Up は未適用のマイグレーションをバージョン順にすべて適用し、適用したものを返す
+// 途中で失敗した場合は、それまでに適用したものを返してエラーで終了する
+// 各マイグレーションはトランザクションで適用するが、MySQLはDDLを暗黙にコミットするため、複数の文のうち途中の文で失敗すると
+// それより前の文の変更は残り、適用済みとしては記録されない。この場合はエラーに復旧の手順を含める（recoveryHint）
+func (m *Migrator) Up() ([]Migration, error) {
+	statuses, err := m.Status()
+	if err != nil {
+		return nil, err
+	}
+	if err := verify(statuses); err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, s := range statuses {
+		if s.Applied {
+			continue
+		}
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			if err := execScript(tx, s.Up); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{
+				Version:   s.Version,
+				Name:      s.Name,
+				Checksum:  s.Checksum(),
+				AppliedAt: time.Now().UTC(),
+			}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("マイグレーション %s の適用に失敗しました: %w%s", s.Migration, err, recoveryHint(m.db.Dialector.Name(), s.Migration, true))
+		}
+		done = append(done, s.Migration)
+	}
+	return done, nil
+}
+
+// Down は適用済みのマイグレーションを新しいものから steps 件取り消し、取り消したものを返す
+// Up と同じく、MySQLでは途中の文で失敗するとそれより前の文の取り消しは残り、適用済みの記録も残る
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	statuses, err := m.Status()
+	if err != nil {
+		return nil, err
+	}
+	if err := verify(statuses); err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(statuses) - 1; i >= 0 && len(done) < steps; i-- {
+		s := statuses[i]
+		if !s.Applied {
+			continue
+		}
+		if strings.TrimSpace(s.Down) == "" {
+			return done, fmt.Errorf("マイグレーション %s は取り消せません（.down.sql がありません）", s.Migration)
+		}
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			if err := execScript(tx, s.Down); err != nil {
+				return err
+			}
+			return tx.Delete(&schemaMigration{}, s.Version).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("マイグレーション %s の取り消しに失敗しました: %w%s", s.Migration, err, recoveryHint(m.db.Dialector.Name(), s.Migration, false))
+		}
+		done = append(done, s.Migration)
+	}
+	return done, nil
+}
+
+// recoveryHint はDDLを暗黙にコミットするデータベース（MySQL）で、マイグレーションが途中で失敗した場合の復旧の手順を返す
+// DDLをトランザクションで取り消せるデータベースでは、途中までの変更は残らないため空文字を返す
+func recoveryHint(dialect string, m Migration, up bool) string {
+	if dialect != "mysql" {
+		return ""
+	}
+	if up {
+		return fmt.Sprintf("（MySQLはDDLを暗黙にコミットするため、失敗した文より前の変更は残っています。%s.down.sql の対応する文で手動で取り消してから、もう一度 migrate up を実行してください）", m)
+	}
+	return fmt.Sprintf("（MySQLはDDLを暗黙にコミットするため、失敗した文より前の取り消しは反映されていますが、適用済みの記録は残っています。%s.down.sql の残りの文を手動で実行し、schema_migrations から version = %d の行を削除してください）", m, m.Version)
+}
+
+// applied は schema_migrations テーブル（なければ作成する）から適用済みのマイグレーションを取得する
+func (m *Migrator) applied() (map[int64]schemaMigration, error) {
+	if !m.db.Migrator().HasTable(&schemaMigration{}) {
+		if err := m.db.Migrator().CreateTable(&schemaMigration{}); err != nil {
+			return nil, fmt.Errorf("schema_migrations テーブルを作成できません: %w", err)
+		}
+	}
+
+	var rows []schemaMigration
+	if err := m.db.Order("version").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("マイグレーションの適用履歴を取得できません: %w", err)
+	}
+	applied := make(map[int64]schemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// verify は適用済みのマイグレーションが変更・削除されていないことを確認する
+func verify(statuses []Status) error {
+	for _, s := range statuses {
+		switch {
+		case s.Modified:
+			return fmt.Errorf("マイグレーション %s は適用後に変更されています（チェックサムが一致しません）", s.Migration)
+		case s.Unknown:
+			return fmt.Errorf("マイグレーション %s はこのアプリケーションに定義がありません（アプリケーションが古い可能性があります）", s.Migration)
+		}
+	}
+	return nil
+}
+
+// execScript はSQLを文ごとに実行する
+// ドライバーによっては1回の実行で複数の文を受け付けないため、行末の ; で区切って実行する
+func execScript(tx *gorm.DB, script string) error {
+	for i, stmt := range splitStatements(script) {
+		if err := tx.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("%d 番目の文の実行に失敗しました: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// splitStatements はSQLを行末の ; で文に分割する（-- で始まるコメント行は除く）
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
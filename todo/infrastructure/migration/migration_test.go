@@ -0,0 +1,249 @@
+package migration
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/infrastructure"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// setupSQLiteDB はインメモリのSQLiteデータベースを作成する（接続ごとに別のデータベースになるため接続数は1）
+func setupSQLiteDB(t *testing.T) *gorm.DB {
+	db, err := infrastructure.InitDB("sqlite://file::memory:", infrastructure.WithMaxOpenConns(1))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	})
+	return db
+}
+
+func TestLoadAllDialects(t *testing.T) {
+	sqlite, err := Load(sqlFiles, "sql/sqlite")
+	require.NoError(t, err)
+	require.NotEmpty(t, sqlite)
+
+	// どのデータベースでも同じバージョンのマイグレーションがそろっていること
+	for _, dialect := range []string{"mysql", "postgres"} {
+		migrations, err := Load(sqlFiles, "sql/"+dialect)
+		require.NoError(t, err, dialect)
+		require.Len(t, migrations, len(sqlite), dialect)
+		for i, m := range migrations {
+			assert.Equal(t, sqlite[i].String(), m.String(), dialect)
+			assert.NotEmpty(t, m.Down, "%s %s", dialect, m)
+		}
+	}
+}
+
+func TestLoadInvalidFiles(t *testing.T) {
+	testCases := []struct {
+		name          string
+		files         fstest.MapFS
+		expectedError string
+	}{
+		{
+			name:          "ファイル名の形式が不正",
+			files:         fstest.MapFS{"sql/create_todos.sql": {Data: []byte("SELECT 1;")}},
+			expectedError: "マイグレーションのファイル名が不正です: create_todos.sql",
+		},
+		{
+			name: "バージョンの重複",
+			files: fstest.MapFS{
+				"sql/0001_create_todos.up.sql": {Data: []byte("SELECT 1;")},
+				"sql/0001_create_tags.up.sql":  {Data: []byte("SELECT 1;")},
+			},
+			expectedError: "バージョン 1 のマイグレーションが重複しています: create_tags, create_todos",
+		},
+		{
+			name:          "upのSQLがない",
+			files:         fstest.MapFS{"sql/0001_create_todos.down.sql": {Data: []byte("SELECT 1;")}},
+			expectedError: "マイグレーション 0001_create_todos に .up.sql がありません",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Load(tc.files, "sql")
+			assert.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestUpAndDown(t *testing.T) {
+	db := setupSQLiteDB(t)
+	m, err := New(db)
+	require.NoError(t, err)
+
+	// 未適用の状態では起動できない
+	assert.True(t, errors.Is(m.Check(), ErrSchemaBehind))
+
+	applied, err := m.Up()
+	require.NoError(t, err)
+	assert.Len(t, applied, len(m.migrations))
+	assert.NoError(t, m.Check())
+
+	// ドメインモデルのすべての列がマイグレーションで作成されていること
+	for _, model := range []interface{}{&domain.Todo{}, &domain.Tag{}} {
+		stmt := &gorm.Statement{DB: db}
+		require.NoError(t, stmt.Parse(model))
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" {
+				assert.True(t, db.Migrator().HasColumn(model, field.DBName), "%s.%s", stmt.Schema.Table, field.DBName)
+			}
+		}
+	}
+	assert.True(t, db.Migrator().HasTable("todo_tags"))
+	assert.True(t, db.Migrator().HasTable("audit_logs"))
+
+	// 2回目は何もしない
+	applied, err = m.Up()
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	// 新しいものから取り消す
+	reverted, err := m.Down(1)
+	require.NoError(t, err)
+	require.Len(t, reverted, 1)
+	assert.Equal(t, m.migrations[len(m.migrations)-1].Version, reverted[0].Version)
+	assert.True(t, errors.Is(m.Check(), ErrSchemaBehind))
+
+	statuses, err := m.Status()
+	require.NoError(t, err)
+	assert.True(t, statuses[0].Applied)
+	assert.False(t, statuses[len(statuses)-1].Applied)
+
+	reverted, err = m.Down(100)
+	require.NoError(t, err)
+	assert.Len(t, reverted, len(m.migrations)-1)
+	assert.False(t, db.Migrator().HasTable("todos"))
+}
+
+// legacyTodo はマイグレーションを導入する前のバージョン（AutoMigrate）で作成していたtodosテーブルの定義
+type legacyTodo struct {
+	ID    uint `gorm:"primaryKey"`
+	Title string
+	Done  bool
+}
+
+// TableName はテーブル名
+func (legacyTodo) TableName() string {
+	return "todos"
+}
+
+func TestUpAdoptsAutoMigratedSchema(t *testing.T) {
+	db := setupSQLiteDB(t)
+
+	// 以前のバージョン（AutoMigrate）で作成したデータベースも、データを残したまま管理下に置ける
+	require.NoError(t, db.AutoMigrate(&legacyTodo{}))
+	require.NoError(t, db.Create(&legacyTodo{Title: "既存のタスク", Done: true}).Error)
+
+	m, err := New(db)
+	require.NoError(t, err)
+	_, err = m.Up()
+	require.NoError(t, err)
+	require.NoError(t, m.Check())
+
+	var todos []domain.Todo
+	require.NoError(t, db.Find(&todos).Error)
+	if assert.Len(t, todos, 1) {
+		assert.Equal(t, "既存のタスク", todos[0].Title)
+		assert.True(t, todos[0].Done)
+		assert.Equal(t, uint(1), todos[0].Version)
+		assert.Equal(t, domain.PriorityNone, todos[0].Priority)
+	}
+
+	// 追加した列にも保存できる
+	todos[0].Priority, todos[0].Description = domain.PriorityHigh, "説明"
+	require.NoError(t, db.Save(&todos[0]).Error)
+}
+
+func TestChecksumAndUnknownMigrations(t *testing.T) {
+	migrations := []Migration{
+		{Version: 1, Name: "create_notes", Up: "CREATE TABLE notes (id integer PRIMARY KEY);", Down: "DROP TABLE notes;"},
+		{Version: 2, Name: "add_body", Up: "ALTER TABLE notes ADD COLUMN body text;"},
+	}
+
+	t.Run("適用後に変更されたマイグレーションを検出する", func(t *testing.T) {
+		db := setupSQLiteDB(t)
+		_, err := NewWithMigrations(db, migrations).Up()
+		require.NoError(t, err)
+
+		edited := append([]Migration(nil), migrations...)
+		edited[0].Up = "CREATE TABLE notes (id integer PRIMARY KEY, title text);"
+		m := NewWithMigrations(db, edited)
+
+		assert.EqualError(t, m.Check(), "マイグレーション 0001_create_notes は適用後に変更されています（チェックサムが一致しません）")
+		_, err = m.Up()
+		assert.Error(t, err)
+		_, err = m.Down(1)
+		assert.Error(t, err)
+	})
+
+	t.Run("アプリケーションにないマイグレーションを検出する", func(t *testing.T) {
+		db := setupSQLiteDB(t)
+		_, err := NewWithMigrations(db, migrations).Up()
+		require.NoError(t, err)
+
+		m := NewWithMigrations(db, migrations[:1])
+		assert.EqualError(t, m.Check(), "マイグレーション 0002_add_body はこのアプリケーションに定義がありません（アプリケーションが古い可能性があります）")
+	})
+
+	t.Run("downのないマイグレーションは取り消せない", func(t *testing.T) {
+		db := setupSQLiteDB(t)
+		m := NewWithMigrations(db, migrations)
+		_, err := m.Up()
+		require.NoError(t, err)
+
+		_, err = m.Down(1)
+		assert.EqualError(t, err, "マイグレーション 0002_add_body は取り消せません（.down.sql がありません）")
+	})
+
+	t.Run("失敗したマイグレーションは記録しない", func(t *testing.T) {
+		db := setupSQLiteDB(t)
+		broken := append([]Migration(nil), migrations...)
+		broken[1].Up = "ALTER TABLE missing ADD COLUMN body text;"
+		m := NewWithMigrations(db, broken)
+
+		applied, err := m.Up()
+		assert.Error(t, err)
+		assert.Len(t, applied, 1)
+
+		statuses, err := m.Status()
+		require.NoError(t, err)
+		assert.True(t, statuses[0].Applied)
+		assert.False(t, statuses[1].Applied)
+	})
+}
+
+func TestRecoveryHint(t *testing.T) {
+	m := Migration{Version: 6, Name: "add_todo_details"}
+
+	// DDLをトランザクションで取り消せるデータベースでは、途中までの変更は残らない
+	assert.Empty(t, recoveryHint("sqlite", m, true))
+	assert.Empty(t, recoveryHint("postgres", m, false))
+
+	// MySQLでは残った変更の取り消し方を示す
+	assert.Contains(t, recoveryHint("mysql", m, true), "0006_add_todo_details.down.sql の対応する文で手動で取り消してから、もう一度 migrate up を実行してください")
+	assert.Contains(t, recoveryHint("mysql", m, false), "schema_migrations から version = 6 の行を削除してください")
+}
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- コメント
+CREATE TABLE a (
+  id integer
+);
+
+CREATE INDEX idx_a ON a(id);
+INSERT INTO a VALUES (1)`
+
+	assert.Equal(t, []string{
+		"CREATE TABLE a (\n  id integer\n)",
+		"CREATE INDEX idx_a ON a(id)",
+		"INSERT INTO a VALUES (1)",
+	}, splitStatements(script))
+}
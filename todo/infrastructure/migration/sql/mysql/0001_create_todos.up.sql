@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS `todos` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `title` longtext,
+  `done` boolean,
+  PRIMARY KEY (`id`)
+);
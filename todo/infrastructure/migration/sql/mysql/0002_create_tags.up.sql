@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS `tags` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `name` varchar(255) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_tags_name` (`name`)
+);
+CREATE TABLE IF NOT EXISTS `todo_tags` (
+  `todo_id` bigint unsigned,
+  `tag_id` bigint unsigned,
+  PRIMARY KEY (`todo_id`,`tag_id`),
+  CONSTRAINT `fk_todo_tags_todo` FOREIGN KEY (`todo_id`) REFERENCES `todos`(`id`),
+  CONSTRAINT `fk_todo_tags_tag` FOREIGN KEY (`tag_id`) REFERENCES `tags`(`id`)
+);
@@ -0,0 +1,2 @@
+DROP INDEX `idx_todos_deleted_at` ON `todos`;
+ALTER TABLE `todos` DROP COLUMN `deleted_at`;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS `audit_logs` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `todo_id` bigint unsigned NOT NULL,
+  `action` varchar(16) NOT NULL,
+  `actor` varchar(255) NOT NULL,
+  `version` bigint unsigned NOT NULL,
+  `changes` text,
+  `created_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_audit_logs_todo_id` (`todo_id`),
+  INDEX `idx_audit_logs_created_at` (`created_at`)
+);
@@ -0,0 +1,11 @@
+ALTER TABLE `todos`
+  ADD COLUMN `parent_id` bigint unsigned,
+  ADD COLUMN `description` text,
+  ADD COLUMN `priority` bigint NOT NULL DEFAULT 0,
+  ADD COLUMN `start_at` datetime(3) NULL,
+  ADD COLUMN `due_at` datetime(3) NULL,
+  ADD COLUMN `recurrence` varchar(255),
+  ADD COLUMN `created_at` datetime(3) NULL,
+  ADD INDEX `idx_todos_parent_id` (`parent_id`),
+  ADD INDEX `idx_todos_due_at` (`due_at`),
+  ADD INDEX `idx_todos_priority` (`priority`);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS "todos" (
+  "id" bigserial,
+  "title" text,
+  "done" boolean,
+  PRIMARY KEY ("id")
+);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS "tags" (
+  "id" bigserial,
+  "name" text NOT NULL,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_tags_name" ON "tags" ("name");
+CREATE TABLE IF NOT EXISTS "todo_tags" (
+  "todo_id" bigint,
+  "tag_id" bigint,
+  PRIMARY KEY ("todo_id","tag_id"),
+  CONSTRAINT "fk_todo_tags_todo" FOREIGN KEY ("todo_id") REFERENCES "todos"("id"),
+  CONSTRAINT "fk_todo_tags_tag" FOREIGN KEY ("tag_id") REFERENCES "tags"("id")
+);
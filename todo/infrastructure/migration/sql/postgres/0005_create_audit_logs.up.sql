@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS "audit_logs" (
+  "id" bigserial,
+  "todo_id" bigint NOT NULL,
+  "action" text NOT NULL,
+  "actor" text NOT NULL,
+  "version" bigint NOT NULL,
+  "changes" text,
+  "created_at" timestamptz NOT NULL,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_todo_id" ON "audit_logs" ("todo_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_created_at" ON "audit_logs" ("created_at");
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS "idx_todos_priority";
+DROP INDEX IF EXISTS "idx_todos_due_at";
+DROP INDEX IF EXISTS "idx_todos_parent_id";
+ALTER TABLE "todos"
+  DROP COLUMN "created_at",
+  DROP COLUMN "recurrence",
+  DROP COLUMN "due_at",
+  DROP COLUMN "start_at",
+  DROP COLUMN "priority",
+  DROP COLUMN "description",
+  DROP COLUMN "parent_id";
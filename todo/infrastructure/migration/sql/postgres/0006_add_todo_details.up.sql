@@ -0,0 +1,11 @@
+ALTER TABLE "todos"
+  ADD COLUMN "parent_id" bigint,
+  ADD COLUMN "description" text,
+  ADD COLUMN "priority" bigint NOT NULL DEFAULT 0,
+  ADD COLUMN "start_at" timestamptz,
+  ADD COLUMN "due_at" timestamptz,
+  ADD COLUMN "recurrence" varchar(255),
+  ADD COLUMN "created_at" timestamptz;
+CREATE INDEX IF NOT EXISTS "idx_todos_parent_id" ON "todos" ("parent_id");
+CREATE INDEX IF NOT EXISTS "idx_todos_due_at" ON "todos" ("due_at");
+CREATE INDEX IF NOT EXISTS "idx_todos_priority" ON "todos" ("priority");
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS `todos` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `title` text,
+  `done` numeric
+);
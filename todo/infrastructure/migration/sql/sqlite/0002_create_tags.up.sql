@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS `tags` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `name` text NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_tags_name` ON `tags`(`name`);
+CREATE TABLE IF NOT EXISTS `todo_tags` (
+  `todo_id` integer,
+  `tag_id` integer,
+  PRIMARY KEY (`todo_id`,`tag_id`),
+  CONSTRAINT `fk_todo_tags_todo` FOREIGN KEY (`todo_id`) REFERENCES `todos`(`id`),
+  CONSTRAINT `fk_todo_tags_tag` FOREIGN KEY (`tag_id`) REFERENCES `tags`(`id`)
+);
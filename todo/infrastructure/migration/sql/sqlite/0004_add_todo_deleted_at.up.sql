@@ -0,0 +1,2 @@
+ALTER TABLE `todos` ADD COLUMN `deleted_at` datetime;
+CREATE INDEX IF NOT EXISTS `idx_todos_deleted_at` ON `todos`(`deleted_at`);
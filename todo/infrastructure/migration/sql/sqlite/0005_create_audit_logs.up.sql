@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS `audit_logs` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `todo_id` integer NOT NULL,
+  `action` text NOT NULL,
+  `actor` text NOT NULL,
+  `version` integer NOT NULL,
+  `changes` text,
+  `created_at` datetime NOT NULL
+);
+CREATE INDEX IF NOT EXISTS `idx_audit_logs_todo_id` ON `audit_logs`(`todo_id`);
+CREATE INDEX IF NOT EXISTS `idx_audit_logs_created_at` ON `audit_logs`(`created_at`);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS `idx_todos_priority`;
+DROP INDEX IF EXISTS `idx_todos_due_at`;
+DROP INDEX IF EXISTS `idx_todos_parent_id`;
+ALTER TABLE `todos` DROP COLUMN `created_at`;
+ALTER TABLE `todos` DROP COLUMN `recurrence`;
+ALTER TABLE `todos` DROP COLUMN `due_at`;
+ALTER TABLE `todos` DROP COLUMN `start_at`;
+ALTER TABLE `todos` DROP COLUMN `priority`;
+ALTER TABLE `todos` DROP COLUMN `description`;
+ALTER TABLE `todos` DROP COLUMN `parent_id`;
@@ -0,0 +1,10 @@
+ALTER TABLE `todos` ADD COLUMN `parent_id` integer;
+ALTER TABLE `todos` ADD COLUMN `description` text;
+ALTER TABLE `todos` ADD COLUMN `priority` integer NOT NULL DEFAULT 0;
+ALTER TABLE `todos` ADD COLUMN `start_at` datetime;
+ALTER TABLE `todos` ADD COLUMN `due_at` datetime;
+ALTER TABLE `todos` ADD COLUMN `recurrence` text;
+ALTER TABLE `todos` ADD COLUMN `created_at` datetime;
+CREATE INDEX IF NOT EXISTS `idx_todos_parent_id` ON `todos`(`parent_id`);
+CREATE INDEX IF NOT EXISTS `idx_todos_due_at` ON `todos`(`due_at`);
+CREATE INDEX IF NOT EXISTS `idx_todos_priority` ON `todos`(`priority`);
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/user"
+	"strconv"
+	"time"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+)
+
+// actorHeader は変更した利用者をサーバーに伝えるリクエストヘッダー
+const actorHeader = "X-User"
+
+// actorTransport はすべてのリクエストに操作者のヘッダーを付ける
+type actorTransport struct {
+	actor string
+	base  http.RoundTripper
+}
+
+// RoundTrip はリクエストを複製して操作者のヘッダーを付けてから送信する
+func (t *actorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.actor != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(actorHeader, t.actor)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// currentUser はOSのユーザー名を返す（取得できない場合は空文字）
+func currentUser() string {
+	u, err := user.Current()
+	if err != nil {
+		return ""
+	}
+	return u.Username
+}
+
+// AuditOptions は監査ログを取得する際の条件
+type AuditOptions struct {
+	TodoID uint       // 指定した場合、そのTODOの監査ログのみを取得する
+	Actor  string     // 指定した場合、その利用者の監査ログのみを取得する
+	Since  *time.Time // 指定した場合、この日時以降の監査ログのみを取得する
+	Until  *time.Time // 指定した場合、この日時より前の監査ログのみを取得する
+	Limit  int        // 取得する最大件数（0の場合はサーバーの既定の件数）
+}
+
+// GetHistory 指定IDのTODOの変更履歴をAPIから取得（古い順）
+func (c *TodoClient) GetHistory(todoID string) ([]domain.AuditEntry, error) {
+	return c.getAuditEntries(fmt.Sprintf("%s/todos/%s/history", c.baseURL, todoID), "history")
+}
+
+// GetAuditLog 条件に一致するすべてのTODOの監査ログをAPIから取得（古い順）
+func (c *TodoClient) GetAuditLog(opts AuditOptions) ([]domain.AuditEntry, error) {
+	params := url.Values{}
+	if opts.TodoID != 0 {
+		params.Set("todo_id", strconv.FormatUint(uint64(opts.TodoID), 10))
+	}
+	if opts.Actor != "" {
+		params.Set("actor", opts.Actor)
+	}
+	if opts.Since != nil {
+		params.Set("since", opts.Since.Format(time.RFC3339))
+	}
+	if opts.Until != nil {
+		params.Set("until", opts.Until.Format(time.RFC3339))
+	}
+	if opts.Limit > 0 {
+		params.Set("limit", strconv.Itoa(opts.Limit))
+	}
+
+	u := c.baseURL + "/audit"
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+	return c.getAuditEntries(u, "audit log")
+}
+
+// getAuditEntries 指定したURLから監査ログを取得する
+func (c *TodoClient) getAuditEntries(u, what string) ([]domain.AuditEntry, error) {
+	resp, err := c.get(u)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("failed to get "+what, resp)
+	}
+
+	var entries []domain.AuditEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", what, err)
+	}
+	return entries, nil
+}
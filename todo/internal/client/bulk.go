@@ -0,0 +1,88 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+)
+
+// まとめて実行する操作の種類
+const (
+	BulkCreate = "create"
+	BulkUpdate = "update"
+	BulkDelete = "delete"
+)
+
+// BulkOperation はまとめて実行する1つの操作（APIへ送信する内容）
+type BulkOperation struct {
+	Action  string             `json:"action"`            // BulkCreate, BulkUpdate, BulkDelete のいずれか
+	ID      uint               `json:"id,omitempty"`      // 更新・削除するTODOのID
+	Version uint               `json:"version,omitempty"` // 指定した場合は、TODOがこのバージョンのときだけ更新・削除する
+	Todo    *CreateTodoRequest `json:"todo,omitempty"`    // 作成する内容
+	Patch   *TodoPatch         `json:"patch,omitempty"`   // 変更する項目
+}
+
+// BulkResult はまとめて実行した操作の結果
+type BulkResult struct {
+	Committed bool             `json:"committed"` // 変更を保存したか
+	Results   []BulkItemResult `json:"results"`   // 操作ごとの結果（指定した操作と同じ順）
+}
+
+// BulkItemResult は1つの操作の結果
+type BulkItemResult struct {
+	Status int          `json:"status"`          // 操作を個別に実行した場合のステータスコード
+	Todo   *domain.Todo `json:"todo,omitempty"`  // 作成・更新したTODO
+	Error  string       `json:"error,omitempty"` // 失敗した場合のエラーメッセージ
+}
+
+// BulkTodos 複数のTODOの作成・更新・削除をAPIを通じて1つのトランザクションでまとめて実行
+// bestEffort が false の場合は1つでも失敗するとすべて取り消し、失敗した操作のエラーを結果とともに返す
+// （412 Precondition Failed の場合は errors.Is で ErrConflict と判定できる）
+// bestEffort が true の場合は失敗した操作だけを取り消すため、操作ごとの結果を確認する
+func (c *TodoClient) BulkTodos(ops []BulkOperation, bestEffort bool) (*BulkResult, error) {
+	mode := "atomic"
+	if bestEffort {
+		mode = "best_effort"
+	}
+	jsonData, err := json.Marshal(map[string]interface{}{"mode": mode, "operations": ops})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal bulk operations: %w", err)
+	}
+
+	resp, err := c.post(c.baseURL+"/todos/bulk", "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to run bulk operations: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// 結果はJSONで返り、リクエスト自体の誤りはテキストで返る
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "application/json" {
+		return nil, statusError("failed to run bulk operations", resp)
+	}
+	var result BulkResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode bulk result: %w", err)
+	}
+	if !result.Committed {
+		return &result, result.failure(resp.StatusCode)
+	}
+	return &result, nil
+}
+
+// failure 保存しなかった結果のうち、失敗の原因となった操作のエラーを返す
+func (r *BulkResult) failure(status int) error {
+	for i, item := range r.Results {
+		if item.Status != status {
+			continue
+		}
+		if status == http.StatusPreconditionFailed {
+			return fmt.Errorf("bulk operation %d failed: %w: %s", i+1, ErrConflict, item.Error)
+		}
+		return fmt.Errorf("bulk operation %d failed: status code %d: %s", i+1, status, item.Error)
+	}
+	return fmt.Errorf("bulk operations were not committed: status code %d", status)
+}
@@ -2,32 +2,167 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
 )
 
 type TodoClient struct {
-	baseURL string
+	baseURL    string
+	httpClient *http.Client
+	ctx        context.Context // リクエストに設定する context（WithContext で設定する）
+}
+
+// DefaultTimeout は1つのリクエストの応答を待つ時間の既定値（送り直す場合はその時間も含む）
+const DefaultTimeout = 30 * time.Second
+
+// Option はTodoClientの任意設定
+type Option func(*TodoClient)
+
+// WithTimeout は1つのリクエストの応答を待つ時間を設定する（0の場合は制限しない）
+// 時間内に応答がない場合はリクエストを取り消してエラーを返す
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *TodoClient) {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+// ErrConflict は指定したバージョンのTODOが他の操作で更新されていたこと（412 Precondition Failed）を表す
+// 最新のTODOを取得し直してから操作をやり直す
+var ErrConflict = errors.New("todo was modified by another operation")
+
+// CreateTodoRequest はTODO作成時にAPIへ送信する内容
+type CreateTodoRequest struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	Priority    string     `json:"priority,omitempty"`
+	StartAt     *time.Time `json:"start_at,omitempty"`
+	DueAt       *time.Time `json:"due_at,omitempty"`
+	Recurrence  string     `json:"recurrence,omitempty"`
+}
+
+// TodoPatch はTODOを部分更新する際にAPIへ送信する内容（nilの項目は変更しない）
+// Description に空文字を指定すると説明を削除する
+type TodoPatch struct {
+	Title       *string `json:"title,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Done        *bool   `json:"done,omitempty"`
 }
 
 // NewTodoClient はTodoClientを作成
-func NewTodoClient(baseURL string) *TodoClient {
-	return &TodoClient{
+// 変更を監査ログに記録するため、OSのユーザー名を操作者としてすべてのリクエストに付ける
+// POSTリクエストにはIdempotency-Keyを付け、応答を受け取れなかった場合は同じキーで送り直す
+func NewTodoClient(baseURL string, opts ...Option) *TodoClient {
+	c := &TodoClient{
 		baseURL: baseURL,
+		httpClient: &http.Client{
+			Transport: &actorTransport{actor: currentUser(), base: &idempotencyTransport{base: newLogTransport(http.DefaultTransport)}},
+			Timeout:   DefaultTimeout,
+		},
+		ctx: context.Background(),
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ListOptions はTODO一覧を取得する際の条件
+type ListOptions struct {
+	Overdue      bool       // 期限切れの未完了タスクのみを取得する
+	Tags         []string   // 指定したタグ名で絞り込む
+	MatchAllTags bool       // trueの場合、すべてのタグが付いたタスクのみを取得する
+	Done         *bool      // 指定した場合、完了状態で絞り込む
+	Query        string     // タイトルと説明に含まれるキーワードで絞り込む
+	Sort         string     // 並び替え項目（priority, created, due, title）
+	Desc         bool       // 降順に並び替える
+	Limit        int        // 1ページの件数（0の場合はすべて取得する）
+	After        string     // 前のページで返されたカーソル
+	AsOf         *time.Time // 指定した場合、その時点の状態から取得する（サーバーがイベントストアを使用している場合のみ）
 }
 
 // GetTodos APIからすべてのTODOを取得
 func (c *TodoClient) GetTodos() ([]domain.Todo, error) {
-	resp, err := http.Get(c.baseURL + "/todos")
+	return c.ListTodos(ListOptions{})
+}
+
+// GetOverdueTodos APIから期限切れの未完了TODOを取得
+func (c *TodoClient) GetOverdueTodos() ([]domain.Todo, error) {
+	return c.ListTodos(ListOptions{Overdue: true})
+}
+
+// GetTodosSorted APIからサーバー側で並び替えたTODOを取得
+// sortには priority, created, due, title のいずれかを指定する
+func (c *TodoClient) GetTodosSorted(sort string, desc bool) ([]domain.Todo, error) {
+	return c.ListTodos(ListOptions{Sort: sort, Desc: desc})
+}
+
+// ListTodos APIから条件に一致するTODOを取得（絞り込みと並び替えはサーバー側で行う）
+func (c *TodoClient) ListTodos(opts ListOptions) ([]domain.Todo, error) {
+	return c.getTodos(c.listURL(opts))
+}
+
+// listURL はTODO一覧を取得するURLを条件から組み立てる
+func (c *TodoClient) listURL(opts ListOptions) string {
+	params := url.Values{}
+	if opts.Overdue {
+		params.Set("overdue", "true")
+	}
+	for _, tag := range opts.Tags {
+		params.Add("tag", tag)
+	}
+	if opts.MatchAllTags {
+		params.Set("tag_mode", "all")
+	}
+	if opts.Sort != "" {
+		params.Set("sort", opts.Sort)
+	}
+	if opts.Desc {
+		params.Set("order", "desc")
+	}
+	if opts.Done != nil {
+		params.Set("done", strconv.FormatBool(*opts.Done))
+	}
+	if opts.Query != "" {
+		params.Set("q", opts.Query)
+	}
+	if opts.Limit > 0 {
+		params.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.After != "" {
+		params.Set("after", opts.After)
+	}
+	if opts.AsOf != nil {
+		params.Set("as_of", opts.AsOf.Format(time.RFC3339Nano))
+	}
+
+	u := c.baseURL + "/todos"
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+	return u
+}
+
+// getTodos 指定URLからTODOの一覧を取得
+func (c *TodoClient) getTodos(url string) ([]domain.Todo, error) {
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get todos: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("failed to get todos", resp)
+	}
+
 	var todos []domain.Todo
 	if err := json.NewDecoder(resp.Body).Decode(&todos); err != nil {
 		return nil, fmt.Errorf("failed to decode todos: %w", err)
@@ -37,18 +172,26 @@ func (c *TodoClient) GetTodos() ([]domain.Todo, error) {
 
 // CreateTodo 新しいTODOをAPIを通じて作成
 func (c *TodoClient) CreateTodo(title string) (*domain.Todo, error) {
-	todo := domain.Todo{Title: title}
+	return c.CreateTodoWithRequest(CreateTodoRequest{Title: title})
+}
+
+// CreateTodoWithRequest 開始日時や期限日時を含む新しいTODOをAPIを通じて作成
+func (c *TodoClient) CreateTodoWithRequest(todo CreateTodoRequest) (*domain.Todo, error) {
 	jsonData, err := json.Marshal(todo)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal todo: %w", err)
 	}
 
-	resp, err := http.Post(c.baseURL+"/todos", "application/json", bytes.NewBuffer(jsonData))
+	resp, err := c.post(c.baseURL+"/todos", "application/json", bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create todo: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusCreated {
+		return nil, statusError("failed to create todo", resp)
+	}
+
 	var createdTodo domain.Todo
 	if err := json.NewDecoder(resp.Body).Decode(&createdTodo); err != nil {
 		return nil, fmt.Errorf("failed to decode created todo: %w", err)
@@ -57,21 +200,27 @@ func (c *TodoClient) CreateTodo(title string) (*domain.Todo, error) {
 }
 
 // PutTodoCompletionStatus 指定IDのTODOのステータスをAPIを通じて更新
-func (c *TodoClient) PutTodoCompletionStatus(todoID string, done bool) (*domain.Todo, error) {
+// version に0以外を指定した場合は、TODOがそのバージョンのときだけ更新する（以下の更新・削除のメソッドも同様）
+func (c *TodoClient) PutTodoCompletionStatus(todoID string, done bool, version uint) (*domain.Todo, error) {
 	url := fmt.Sprintf("%s/todos/%s", c.baseURL, todoID)
 
 	body := map[string]bool{"done": done}
 	jsonBody, _ := json.Marshal(body)
 
-	req, _ := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(jsonBody))
+	req, _ := c.newRequest(http.MethodPut, url, bytes.NewBuffer(jsonBody))
 	req.Header.Set("Content-Type", "application/json")
+	setIfMatch(req, version)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to put status: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("failed to put status", resp)
+	}
+
 	var updatedTodo domain.Todo
 	if err := json.NewDecoder(resp.Body).Decode(&updatedTodo); err != nil {
 		return nil, fmt.Errorf("failed to decode updated todo: %w", err)
@@ -79,22 +228,143 @@ func (c *TodoClient) PutTodoCompletionStatus(todoID string, done bool) (*domain.
 	return &updatedTodo, nil
 }
 
-// DeleteTodoByID 指定IDのTODOをAPIを通じて削除
-func (c *TodoClient) DeleteTodoByID(id string) error {
-	req, err := http.NewRequest(http.MethodDelete, c.baseURL+"/todos/"+id, nil)
+// PutTodoSchedule 指定IDのTODOの開始日時と期限日時をAPIを通じて更新
+func (c *TodoClient) PutTodoSchedule(todoID string, startAt, dueAt *time.Time, version uint) (*domain.Todo, error) {
+	url := fmt.Sprintf("%s/todos/%s/schedule", c.baseURL, todoID)
+
+	body := map[string]*time.Time{"start_at": startAt, "due_at": dueAt}
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schedule: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPut, url, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create schedule request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	setIfMatch(req, version)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to put schedule: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("failed to put schedule", resp)
+	}
+
+	var updatedTodo domain.Todo
+	if err := json.NewDecoder(resp.Body).Decode(&updatedTodo); err != nil {
+		return nil, fmt.Errorf("failed to decode updated todo: %w", err)
+	}
+	return &updatedTodo, nil
+}
+
+// PutTodoPriority 指定IDのTODOの優先度をAPIを通じて更新
+func (c *TodoClient) PutTodoPriority(todoID string, priority string, version uint) (*domain.Todo, error) {
+	url := fmt.Sprintf("%s/todos/%s/priority", c.baseURL, todoID)
+
+	jsonBody, err := json.Marshal(map[string]string{"priority": priority})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal priority: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPut, url, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create priority request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	setIfMatch(req, version)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to put priority: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("failed to put priority", resp)
+	}
+
+	var updatedTodo domain.Todo
+	if err := json.NewDecoder(resp.Body).Decode(&updatedTodo); err != nil {
+		return nil, fmt.Errorf("failed to decode updated todo: %w", err)
+	}
+	return &updatedTodo, nil
+}
+
+// PatchTodo 指定IDのTODOをAPIを通じて部分更新（JSON Merge Patch）
+func (c *TodoClient) PatchTodo(todoID string, patch TodoPatch, version uint) (*domain.Todo, error) {
+	url := fmt.Sprintf("%s/todos/%s", c.baseURL, todoID)
+
+	jsonBody, err := json.Marshal(patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal patch: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPatch, url, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create patch request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	setIfMatch(req, version)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch todo: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("failed to patch todo", resp)
+	}
+
+	var updatedTodo domain.Todo
+	if err := json.NewDecoder(resp.Body).Decode(&updatedTodo); err != nil {
+		return nil, fmt.Errorf("failed to decode updated todo: %w", err)
+	}
+	return &updatedTodo, nil
+}
+
+// DeleteTodoByID 指定IDのTODOをAPIを通じてゴミ箱に移動（ゴミ箱に移動するとバージョンが1つ進む）
+func (c *TodoClient) DeleteTodoByID(id string, version uint) error {
+	req, err := c.newRequest(http.MethodDelete, c.baseURL+"/todos/"+id, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create delete todo request: %w", err)
 	}
+	setIfMatch(req, version)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to delete todo: %w", err)
 	}
 	defer resp.Body.Close()
 
     // サーバーはStatusNoContent(204)を返すので、それも成功と見なす
+    if resp.StatusCode == http.StatusPreconditionFailed {
+        return statusError("failed to delete todo", resp)
+    }
     if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
         return fmt.Errorf("failed to delete todo: status code %d", resp.StatusCode)
     }
 	return nil
 }
+
+// setIfMatch 更新・削除の前提とするTODOのバージョンをIf-Matchヘッダーに設定（0の場合は設定しない）
+func setIfMatch(req *http.Request, version uint) {
+	if version != 0 {
+		req.Header.Set("If-Match", `"`+strconv.FormatUint(uint64(version), 10)+`"`)
+	}
+}
+
+// statusError 想定外のステータスコードをレスポンス本文とともにエラーにする
+// 412 Precondition Failed の場合は errors.Is で ErrConflict と判定できるようにする
+func statusError(msg string, resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusPreconditionFailed {
+		return fmt.Errorf("%s: %w: %s", msg, ErrConflict, bytes.TrimSpace(body))
+	}
+	return fmt.Errorf("%s: status code %d: %s", msg, resp.StatusCode, bytes.TrimSpace(body))
+}
@@ -0,0 +1,39 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// WithContext は ctx を設定したリクエストを送信するクライアントを返す
+// ctx が終了した場合は送信中のリクエストを取り消す。元のクライアントは変更しない
+func (c *TodoClient) WithContext(ctx context.Context) *TodoClient {
+	clone := *c
+	clone.ctx = ctx
+	return &clone
+}
+
+// newRequest は c.ctx を設定したリクエストを作成する
+func (c *TodoClient) newRequest(method, url string, body io.Reader) (*http.Request, error) {
+	return http.NewRequestWithContext(c.ctx, method, url, body)
+}
+
+// get は c.ctx を設定したGETリクエストを送信する（http.Client.Get と同じ）
+func (c *TodoClient) get(url string) (*http.Response, error) {
+	req, err := c.newRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.httpClient.Do(req)
+}
+
+// post は c.ctx を設定したPOSTリクエストを送信する（http.Client.Post と同じ）
+func (c *TodoClient) post(url, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := c.newRequest(http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return c.httpClient.Do(req)
+}
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// ReadyStatus はサーバーがリクエストを処理できる状態かどうか（/readyz の結果）
+type ReadyStatus struct {
+	Status string            `json:"status"`           // ok または unavailable
+	Checks map[string]string `json:"checks,omitempty"` // 項目ごとの結果（ok またはエラーメッセージ）
+}
+
+// CheckReady サーバーがリクエストを処理できる状態かをAPIから取得
+// 処理できない状態（503 Service Unavailable）の場合は、確認に失敗した項目を含むエラーを結果とともに返す
+func (c *TodoClient) CheckReady() (*ReadyStatus, error) {
+	resp, err := c.get(c.baseURL + "/readyz")
+	if err != nil {
+		return nil, fmt.Errorf("failed to check readiness: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+		return nil, statusError("failed to check readiness", resp)
+	}
+	var status ReadyStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("failed to decode readiness: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &status, fmt.Errorf("server is not ready: %s", status.failures())
+	}
+	return &status, nil
+}
+
+// failures は確認に失敗した項目を名前順に「名前: エラーメッセージ」の形式でつなげる
+func (s *ReadyStatus) failures() string {
+	var failed []string
+	for _, name := range slices.Sorted(maps.Keys(s.Checks)) {
+		if result := s.Checks[name]; result != "ok" {
+			failed = append(failed, name+": "+result)
+		}
+	}
+	return strings.Join(failed, ", ")
+}
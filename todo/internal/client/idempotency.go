@@ -0,0 +1,59 @@
+package client
+
+import (
+	"crypto/rand"
+	"net/http"
+	"time"
+)
+
+// idempotencyKeyHeader は同じ操作の再送であることをサーバーに伝えるリクエストヘッダー
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// idempotencyRetries は応答を受け取れなかったPOSTリクエストを送り直す回数
+const idempotencyRetries = 2
+
+// idempotencyRetryInterval は送り直すまでの待ち時間（送り直すたびに長くする）
+const idempotencyRetryInterval = 200 * time.Millisecond
+
+// idempotencyTransport はPOSTリクエストにIdempotency-Keyを付け、応答を受け取れなかった場合は同じキーで送り直す
+// サーバーは同じキーのリクエストを1回だけ処理するため、送り直してもTODOが重複して作成されることはない
+type idempotencyTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip はリクエストを複製してIdempotency-Keyを付けてから送信する
+func (t *idempotencyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodPost {
+		return t.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	if req.Header.Get(idempotencyKeyHeader) == "" {
+		req.Header.Set(idempotencyKeyHeader, rand.Text())
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err == nil || attempt > idempotencyRetries || req.Context().Err() != nil {
+			return resp, err
+		}
+		// 本文を読み直せない場合は送り直さない
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, err
+			}
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return resp, err
+			}
+			req.Body = body
+		}
+		// 待っている間にリクエストがキャンセルされた場合は、送り直さずに終了する
+		timer := time.NewTimer(time.Duration(attempt) * idempotencyRetryInterval)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
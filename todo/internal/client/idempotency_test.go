@@ -0,0 +1,133 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// roundTripperFunc は関数を http.RoundTripper として使う
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// receivedRequest はサーバーが受け取ったリクエスト
+type receivedRequest struct {
+	key  string
+	body string
+}
+
+// newDroppingServer は最初の drops 回の接続を応答せずに切断するサーバーを作成し、受け取ったリクエストを記録する
+func newDroppingServer(t *testing.T, drops int) (*httptest.Server, func() []receivedRequest) {
+	var mu sync.Mutex
+	var received []receivedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, receivedRequest{key: r.Header.Get(idempotencyKeyHeader), body: string(body)})
+		drop := len(received) <= drops
+		mu.Unlock()
+		if drop {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if assert.NoError(t, err) { // ハンドラーの goroutine では require を使えない
+				conn.Close()
+			}
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []receivedRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]receivedRequest(nil), received...)
+	}
+}
+
+// newIdempotencyClient は接続を使い回さない idempotencyTransport のクライアントを作成する
+func newIdempotencyClient(t *testing.T) *http.Client {
+	base := &http.Transport{DisableKeepAlives: true}
+	t.Cleanup(base.CloseIdleConnections)
+	return &http.Client{Transport: &idempotencyTransport{base: base}}
+}
+
+func TestIdempotencyTransport(t *testing.T) {
+	t.Run("応答を受け取れなかったPOSTは同じキーと本文で送り直す", func(t *testing.T) {
+		server, received := newDroppingServer(t, 1)
+
+		resp, err := newIdempotencyClient(t).Post(server.URL+"/todos", "application/json", strings.NewReader(`{"title":"買い物"}`))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		requests := received()
+		require.Len(t, requests, 2)
+		assert.NotEmpty(t, requests[0].key)
+		assert.Equal(t, requests[0].key, requests[1].key)
+		assert.Equal(t, `{"title":"買い物"}`, requests[0].body)
+		assert.Equal(t, requests[0].body, requests[1].body)
+	})
+
+	t.Run("指定したキーはそのまま使う", func(t *testing.T) {
+		server, received := newDroppingServer(t, 0)
+
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/todos", strings.NewReader(`{}`))
+		require.NoError(t, err)
+		req.Header.Set(idempotencyKeyHeader, "key-1")
+		resp, err := newIdempotencyClient(t).Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		requests := received()
+		require.Len(t, requests, 1)
+		assert.Equal(t, "key-1", requests[0].key)
+	})
+
+	t.Run("送り直す回数には上限がある", func(t *testing.T) {
+		server, received := newDroppingServer(t, idempotencyRetries+1)
+
+		_, err := newIdempotencyClient(t).Post(server.URL+"/todos", "application/json", strings.NewReader(`{}`))
+		assert.Error(t, err)
+		assert.Len(t, received(), idempotencyRetries+1)
+	})
+
+	t.Run("POST以外は送り直さず、キーも付けない", func(t *testing.T) {
+		server, received := newDroppingServer(t, 1)
+
+		_, err := newIdempotencyClient(t).Get(server.URL + "/todos")
+		assert.Error(t, err)
+		requests := received()
+		require.Len(t, requests, 1)
+		assert.Empty(t, requests[0].key)
+	})
+
+	t.Run("送り直すのを待っている間にキャンセルした場合はすぐに終了する", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		attempts := 0
+		transport := &idempotencyTransport{base: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			time.AfterFunc(10*time.Millisecond, cancel) // 送り直すまでの待ち時間の途中でキャンセルする
+			return nil, errors.New("接続が切断されました")
+		})}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://example.com/todos", http.NoBody)
+		require.NoError(t, err)
+		start := time.Now()
+		_, err = transport.RoundTrip(req)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Less(t, time.Since(start), idempotencyRetryInterval)
+		assert.Equal(t, 1, attempts)
+	})
+}
@@ -0,0 +1,32 @@
+package client
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/logger"
+)
+
+// logTransport は送信したリクエストとレスポンスを DEBUG レベルでログに記録する
+// 送り直した場合は、送信するたびに記録する
+type logTransport struct {
+	base http.RoundTripper
+	log  *logger.Logger
+}
+
+// newLogTransport はコンポーネント client のLoggerで記録する logTransport を作成する
+func newLogTransport(base http.RoundTripper) *logTransport {
+	return &logTransport{base: base, log: logger.GetLogger().Named(logger.ComponentClient)}
+}
+
+// RoundTrip はリクエストを送信し、メソッド・URL・ステータスコード・処理時間を記録する
+func (t *logTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		t.log.Debugw("APIリクエストに失敗しました", "method", req.Method, "url", req.URL.String(), "duration", time.Since(start), "error", err)
+		return nil, err
+	}
+	t.log.Debugw("APIリクエストを送信しました", "method", req.Method, "url", req.URL.String(), "status", resp.StatusCode, "duration", time.Since(start))
+	return resp, err
+}
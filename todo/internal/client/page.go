@@ -0,0 +1,116 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+)
+
+// defaultPageSize は AllTodos で ListOptions.Limit が未指定の場合の1ページの件数
+const defaultPageSize = 50
+
+// TodoPage はページ単位で取得したTODOの一覧
+type TodoPage struct {
+	Todos []domain.Todo // このページのTODO
+	Total int64         // 絞り込み条件に一致するTODOの総数（X-Total-Count）
+	Next  string        // 次のページのURL（最後のページの場合は空）
+}
+
+// ListTodosPage APIから条件に一致するTODOを1ページ分取得
+func (c *TodoClient) ListTodosPage(opts ListOptions) (*TodoPage, error) {
+	return c.getTodoPage(c.listURL(opts))
+}
+
+// NextPage 前のページのLinkヘッダーが指す次のページを取得
+func (c *TodoClient) NextPage(page *TodoPage) (*TodoPage, error) {
+	if page.Next == "" {
+		return nil, fmt.Errorf("no next page")
+	}
+	return c.getTodoPage(page.Next)
+}
+
+// AllTodos 条件に一致するすべてのTODOを、ページを順にたどりながら1件ずつ返す
+// エラーが発生した場合はそのエラーを返して終了する
+func (c *TodoClient) AllTodos(opts ListOptions) iter.Seq2[domain.Todo, error] {
+	if opts.Limit <= 0 {
+		opts.Limit = defaultPageSize
+	}
+	return func(yield func(domain.Todo, error) bool) {
+		u := c.listURL(opts)
+		for u != "" {
+			page, err := c.getTodoPage(u)
+			if err != nil {
+				yield(domain.Todo{}, err)
+				return
+			}
+			for _, todo := range page.Todos {
+				if !yield(todo, nil) {
+					return
+				}
+			}
+			u = page.Next
+		}
+	}
+}
+
+// getTodoPage 指定URLからTODOを1ページ分取得
+func (c *TodoClient) getTodoPage(u string) (*TodoPage, error) {
+	resp, err := c.get(u)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get todos: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("failed to get todos", resp)
+	}
+
+	page := &TodoPage{}
+	if err := json.NewDecoder(resp.Body).Decode(&page.Todos); err != nil {
+		return nil, fmt.Errorf("failed to decode todos: %w", err)
+	}
+	if total := resp.Header.Get("X-Total-Count"); total != "" {
+		if page.Total, err = strconv.ParseInt(total, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid X-Total-Count header: %w", err)
+		}
+	}
+	if next := nextLink(resp.Header.Values("Link")); next != "" {
+		ref, err := url.Parse(next)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Link header: %w", err)
+		}
+		page.Next = resp.Request.URL.ResolveReference(ref).String()
+	}
+	return page, nil
+}
+
+// nextLink はLinkヘッダー（RFC 8288）から rel="next" のURLを取り出す
+func nextLink(headers []string) string {
+	for _, header := range headers {
+		for _, link := range strings.Split(header, ",") {
+			parts := strings.Split(link, ";")
+			target := strings.TrimSpace(parts[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+			for _, param := range parts[1:] {
+				key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+				if !ok || !strings.EqualFold(key, "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(value, `"`)) {
+					if strings.EqualFold(rel, "next") {
+						return target[1 : len(target)-1]
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+)
+
+// SearchTodos タイトルと説明をキーワードで全文検索し、関連度の高い順に取得（limitが0の場合はサーバーの既定の件数）
+func (c *TodoClient) SearchTodos(query string, limit int) ([]domain.SearchResult, error) {
+	params := url.Values{"q": {query}}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+
+	resp, err := c.get(c.baseURL + "/todos/search?" + params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to search todos: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("failed to search todos", resp)
+	}
+
+	var results []domain.SearchResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("failed to decode search results: %w", err)
+	}
+	return results, nil
+}
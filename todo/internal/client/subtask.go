@@ -0,0 +1,40 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+)
+
+// GetSubtasks 指定IDのTODOのサブタスクをAPIから取得
+func (c *TodoClient) GetSubtasks(todoID string) ([]domain.Todo, error) {
+	return c.getTodos(fmt.Sprintf("%s/todos/%s/subtasks", c.baseURL, todoID))
+}
+
+// CreateSubtask 指定IDのTODOの下にサブタスクをAPIを通じて作成
+func (c *TodoClient) CreateSubtask(parentID string, todo CreateTodoRequest) (*domain.Todo, error) {
+	jsonData, err := json.Marshal(todo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal subtask: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/todos/%s/subtasks", c.baseURL, parentID)
+	resp, err := c.post(url, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create subtask: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return nil, statusError("failed to create subtask", resp)
+	}
+
+	var createdTodo domain.Todo
+	if err := json.NewDecoder(resp.Body).Decode(&createdTodo); err != nil {
+		return nil, fmt.Errorf("failed to decode created subtask: %w", err)
+	}
+	return &createdTodo, nil
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+)
+
+// GetTags APIからすべてのタグを取得
+func (c *TodoClient) GetTags() ([]domain.Tag, error) {
+	resp, err := c.get(c.baseURL + "/tags")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("failed to get tags", resp)
+	}
+
+	var tags []domain.Tag
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, fmt.Errorf("failed to decode tags: %w", err)
+	}
+	return tags, nil
+}
+
+// CreateTag 新しいタグをAPIを通じて作成
+func (c *TodoClient) CreateTag(name string) (*domain.Tag, error) {
+	jsonData, err := json.Marshal(map[string]string{"name": name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tag: %w", err)
+	}
+
+	resp, err := c.post(c.baseURL+"/tags", "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tag: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return nil, statusError("failed to create tag", resp)
+	}
+
+	var tag domain.Tag
+	if err := json.NewDecoder(resp.Body).Decode(&tag); err != nil {
+		return nil, fmt.Errorf("failed to decode created tag: %w", err)
+	}
+	return &tag, nil
+}
+
+// DeleteTagByID 指定IDのタグをAPIを通じて削除
+func (c *TodoClient) DeleteTagByID(id string) error {
+	req, err := c.newRequest(http.MethodDelete, c.baseURL+"/tags/"+id, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create delete tag request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return statusError("failed to delete tag", resp)
+	}
+	return nil
+}
+
+// AttachTag 指定IDのTODOにタグをAPIを通じて付与
+func (c *TodoClient) AttachTag(todoID string, tagID string) (*domain.Todo, error) {
+	return c.changeTag(http.MethodPut, todoID, tagID)
+}
+
+// DetachTag 指定IDのTODOからタグをAPIを通じて解除
+func (c *TodoClient) DetachTag(todoID string, tagID string) (*domain.Todo, error) {
+	return c.changeTag(http.MethodDelete, todoID, tagID)
+}
+
+// changeTag タグの付与・解除のリクエストを送信
+func (c *TodoClient) changeTag(method string, todoID string, tagID string) (*domain.Todo, error) {
+	url := fmt.Sprintf("%s/todos/%s/tags/%s", c.baseURL, todoID, tagID)
+
+	req, err := c.newRequest(method, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tag request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to change tag: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("failed to change tag", resp)
+	}
+
+	var todo domain.Todo
+	if err := json.NewDecoder(resp.Body).Decode(&todo); err != nil {
+		return nil, fmt.Errorf("failed to decode todo: %w", err)
+	}
+	return &todo, nil
+}
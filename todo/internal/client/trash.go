@@ -0,0 +1,41 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+)
+
+// GetTrash ゴミ箱のTODOをAPIから取得（ゴミ箱に移動した日時の新しい順）
+func (c *TodoClient) GetTrash() ([]domain.Todo, error) {
+	return c.getTodos(c.baseURL + "/todos/trash")
+}
+
+// RestoreTodo ゴミ箱にある指定IDのTODOをAPIを通じて元に戻す
+// version にはゴミ箱に移動した後のバージョンを指定する（0の場合は確認しない）
+func (c *TodoClient) RestoreTodo(todoID string, version uint) (*domain.Todo, error) {
+	url := fmt.Sprintf("%s/todos/%s/restore", c.baseURL, todoID)
+	req, err := c.newRequest(http.MethodPost, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create restore request: %w", err)
+	}
+	setIfMatch(req, version)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore todo: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("failed to restore todo", resp)
+	}
+
+	var restoredTodo domain.Todo
+	if err := json.NewDecoder(resp.Body).Decode(&restoredTodo); err != nil {
+		return nil, fmt.Errorf("failed to decode restored todo: %w", err)
+	}
+	return &restoredTodo, nil
+}
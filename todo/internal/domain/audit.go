@@ -0,0 +1,97 @@
+package domain
+
+import (
+    "bytes"
+    "encoding/json"
+    "time"
+)
+
+// AuditAction は監査ログに記録する操作の種類
+type AuditAction string
+
+// 監査ログに記録する操作を定義
+const (
+    AuditCreate  AuditAction = "create"  // 作成
+    AuditUpdate  AuditAction = "update"  // 更新
+    AuditDelete  AuditAction = "delete"  // ゴミ箱に移動
+    AuditRestore AuditAction = "restore" // ゴミ箱から元に戻す
+    AuditPurge   AuditAction = "purge"   // ゴミ箱から完全に削除
+)
+
+// SystemActor は利用者の操作ではなく、アプリケーションが自動で行った変更の操作者
+const SystemActor = "system"
+
+// AuditEntry はTODOに対する1回の変更の記録（監査ログ）
+// 一度記録した内容は変更しない
+type AuditEntry struct {
+    ID        uint                   `json:"id"`                // 監査ログの一意識別子（記録した順に増える）
+    TodoID    uint                   `json:"todo_id"`           // 変更したTODOのID
+    Action    AuditAction            `json:"action"`            // 操作の種類
+    Actor     string                 `json:"actor"`             // 変更した利用者
+    Version   uint                   `json:"version"`           // 変更後のTODOのバージョン（完全に削除した場合は削除前のバージョン）
+    Changes   map[string]FieldChange `json:"changes,omitempty"` // 変更した項目（JSONの項目名ごとの変更前と変更後の値）
+    CreatedAt time.Time              `json:"created_at"`        // 変更した日時
+}
+
+// FieldChange は1つの項目の変更前と変更後の値（JSON）
+type FieldChange struct {
+    Before json.RawMessage `json:"before"` // 変更前の値（作成の場合はnull）
+    After  json.RawMessage `json:"after"`  // 変更後の値
+}
+
+// AuditQuery は監査ログの絞り込み条件
+type AuditQuery struct {
+    TodoID *uint      // 指定した場合、そのTODOの監査ログのみを取得する
+    Actor  string     // 指定した場合、その利用者の監査ログのみを取得する
+    Since  *time.Time // 指定した場合、この日時以降の監査ログのみを取得する
+    Until  *time.Time // 指定した場合、この日時より前の監査ログのみを取得する
+    Limit  int        // 取得する最大件数（0の場合はすべて取得する）
+}
+
+// auditFields は監査ログで変更を記録するTODOの項目（JSONの項目名と値）
+// ID・バージョン・作成日時・タグは記録しない
+var auditFields = []struct {
+    name  string
+    value func(Todo) interface{}
+}{
+    {"parent_id", func(t Todo) interface{} { return t.ParentID }},
+    {"title", func(t Todo) interface{} { return t.Title }},
+    {"description", func(t Todo) interface{} { return t.Description }},
+    {"done", func(t Todo) interface{} { return t.Done }},
+    {"priority", func(t Todo) interface{} { return t.Priority }},
+    {"start_at", func(t Todo) interface{} { return t.StartAt }},
+    {"due_at", func(t Todo) interface{} { return t.DueAt }},
+    {"recurrence", func(t Todo) interface{} { return t.Recurrence }},
+    {"deleted_at", func(t Todo) interface{} { return t.DeletedAt }},
+}
+
+// TodoChanges は before から after への変更を項目ごとに返す（値が同じ項目は含めない）
+// before が nil の場合は作成として扱い、初期値から変更した項目のみを変更前の値をnullにして返す
+func TodoChanges(before *Todo, after Todo) map[string]FieldChange {
+    base := Todo{}
+    if before != nil {
+        base = *before
+    }
+
+    changes := make(map[string]FieldChange)
+    for _, f := range auditFields {
+        b, a := marshalValue(f.value(base)), marshalValue(f.value(after))
+        if bytes.Equal(b, a) {
+            continue
+        }
+        if before == nil {
+            b = json.RawMessage("null")
+        }
+        changes[f.name] = FieldChange{Before: b, After: a}
+    }
+    return changes
+}
+
+// marshalValue は項目の値をJSONにする（TODOの項目は常にJSONにできる）
+func marshalValue(v interface{}) json.RawMessage {
+    data, err := json.Marshal(v)
+    if err != nil {
+        return json.RawMessage("null")
+    }
+    return data
+}
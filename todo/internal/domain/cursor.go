@@ -0,0 +1,86 @@
+package domain
+
+import (
+    "encoding/base64"
+    "encoding/json"
+    "fmt"
+    "strconv"
+    "time"
+)
+
+// Cursor はページングで直前のページの最後のTODOの位置を表す
+// 並び替え項目の値とIDを保持するため、指していたTODOが削除されても続きから取得できる
+type Cursor struct {
+    Sort SortField `json:"s,omitempty"` // カーソルを作成した際の並び替え項目
+    Desc bool      `json:"d,omitempty"` // カーソルを作成した際の並び順
+    ID   uint      `json:"i"`           // TODOのID
+    Key  string    `json:"k,omitempty"` // 並び替え項目の値（期限未設定の場合は空）
+}
+
+// NewCursor は指定したTODOの位置を表すカーソルを作成する
+func NewCursor(todo Todo, sort SortField, desc bool) Cursor {
+    c := Cursor{Sort: sort, Desc: desc, ID: todo.ID}
+    switch sort {
+    case SortByPriority:
+        c.Key = strconv.Itoa(int(todo.Priority))
+    case SortByCreated:
+        c.Key = todo.CreatedAt.Format(time.RFC3339Nano)
+    case SortByDue:
+        if todo.DueAt != nil {
+            c.Key = todo.DueAt.Format(time.RFC3339Nano)
+        }
+    case SortByTitle:
+        c.Key = todo.Title
+    }
+    return c
+}
+
+// ParseCursor はURLで受け取ったカーソル文字列を解析する
+func ParseCursor(s string) (Cursor, error) {
+    var c Cursor
+    data, err := base64.RawURLEncoding.DecodeString(s)
+    if err != nil {
+        return Cursor{}, fmt.Errorf("カーソルの形式が不正です")
+    }
+    if err := json.Unmarshal(data, &c); err != nil {
+        return Cursor{}, fmt.Errorf("カーソルの形式が不正です")
+    }
+    if _, err := ParseSortField(string(c.Sort)); err != nil {
+        return Cursor{}, fmt.Errorf("カーソルの形式が不正です")
+    }
+    if _, err := c.SortValue(); err != nil {
+        return Cursor{}, err
+    }
+    return c, nil
+}
+
+// Encode はカーソルをURLで使用できる文字列に変換する
+func (c Cursor) Encode() string {
+    data, _ := json.Marshal(c)
+    return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// SortValue は並び替え項目の値を比較に使用する型で返す
+// ID順の場合と期限未設定の場合は nil を返す
+func (c Cursor) SortValue() (interface{}, error) {
+    switch c.Sort {
+    case SortByPriority:
+        p, err := strconv.Atoi(c.Key)
+        if err != nil {
+            return nil, fmt.Errorf("カーソルの優先度が不正です")
+        }
+        return p, nil
+    case SortByCreated, SortByDue:
+        if c.Sort == SortByDue && c.Key == "" {
+            return nil, nil
+        }
+        t, err := time.Parse(time.RFC3339Nano, c.Key)
+        if err != nil {
+            return nil, fmt.Errorf("カーソルの日時が不正です")
+        }
+        return t, nil
+    case SortByTitle:
+        return c.Key, nil
+    }
+    return nil, nil
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+    "encoding/json"
+    "fmt"
+)
+
+// Priority はタスクの優先度
+// データベースには数値で保存し、大きいほど優先度が高い
+type Priority int
+
+// 優先度を定義
+const (
+    PriorityNone Priority = iota
+    PriorityLow
+    PriorityMedium
+    PriorityHigh
+    PriorityUrgent
+)
+
+// 優先度の文字列表現（JSONやクエリパラメータで使用）
+var priorityNames = map[Priority]string{
+    PriorityNone:   "none",
+    PriorityLow:    "low",
+    PriorityMedium: "medium",
+    PriorityHigh:   "high",
+    PriorityUrgent: "urgent",
+}
+
+// Priorities は定義済みの優先度を低い順に返す
+func Priorities() []Priority {
+    return []Priority{PriorityNone, PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent}
+}
+
+// ParsePriority は文字列から優先度を取得する
+// 空文字の場合は PriorityNone を返す
+func ParsePriority(s string) (Priority, error) {
+    if s == "" {
+        return PriorityNone, nil
+    }
+    for p, name := range priorityNames {
+        if name == s {
+            return p, nil
+        }
+    }
+    return PriorityNone, fmt.Errorf("不明な優先度です: %s", s)
+}
+
+// IsValid は定義済みの優先度かどうかを判定する
+func (p Priority) IsValid() bool {
+    _, ok := priorityNames[p]
+    return ok
+}
+
+// String は優先度の文字列表現を返す
+func (p Priority) String() string {
+    if name, ok := priorityNames[p]; ok {
+        return name
+    }
+    return fmt.Sprintf("Priority(%d)", int(p))
+}
+
+// MarshalJSON は優先度を文字列としてJSONに変換する
+func (p Priority) MarshalJSON() ([]byte, error) {
+    if !p.IsValid() {
+        return nil, fmt.Errorf("不明な優先度です: %d", int(p))
+    }
+    return json.Marshal(p.String())
+}
+
+// UnmarshalJSON はJSONの文字列から優先度を復元する
+func (p *Priority) UnmarshalJSON(data []byte) error {
+    var s string
+    if err := json.Unmarshal(data, &s); err != nil {
+        return err
+    }
+    parsed, err := ParsePriority(s)
+    if err != nil {
+        return err
+    }
+    *p = parsed
+    return nil
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+    "fmt"
+    "time"
+)
+
+// SortField はTODO一覧の並び替えに使用する項目
+type SortField string
+
+// 並び替え項目を定義
+const (
+    SortByDefault  SortField = ""         // ID順（作成順）
+    SortByPriority SortField = "priority" // 優先度順
+    SortByCreated  SortField = "created"  // 作成日時順
+    SortByDue      SortField = "due"      // 期限日時順（期限未設定は常に末尾）
+    SortByTitle    SortField = "title"    // タイトル順
+)
+
+// ParseSortField は文字列から並び替え項目を取得する
+func ParseSortField(s string) (SortField, error) {
+    switch f := SortField(s); f {
+    case SortByDefault, SortByPriority, SortByCreated, SortByDue, SortByTitle:
+        return f, nil
+    }
+    return SortByDefault, fmt.Errorf("不明な並び替え項目です: %s", s)
+}
+
+// TagMatch は複数のタグで絞り込む際の一致条件
+type TagMatch string
+
+// タグの一致条件を定義
+const (
+    TagMatchAny TagMatch = "any" // いずれかのタグが付いていれば一致
+    TagMatchAll TagMatch = "all" // すべてのタグが付いていれば一致
+)
+
+// ParseTagMatch は文字列からタグの一致条件を取得する
+// 空文字の場合は TagMatchAny を返す
+func ParseTagMatch(s string) (TagMatch, error) {
+    switch m := TagMatch(s); m {
+    case "":
+        return TagMatchAny, nil
+    case TagMatchAny, TagMatchAll:
+        return m, nil
+    }
+    return TagMatchAny, fmt.Errorf("不明なタグの一致条件です: %s", s)
+}
+
+// TodoQuery はTODO一覧を取得する際の絞り込み条件
+type TodoQuery struct {
+    ParentID *uint      // 指定した親タスクのサブタスクのみを取得する（nilの場合は絞り込まない）
+    Overdue  bool       // trueの場合、期限切れの未完了タスクのみを取得する
+    Now      time.Time  // 期限切れ判定の基準時刻
+    Tags     []string   // 指定したタグ名で絞り込む（空の場合は絞り込まない）
+    TagMatch TagMatch   // 複数のタグを指定した場合の一致条件
+    Done     *bool      // 指定した完了状態のタスクのみを取得する（nilの場合は絞り込まない）
+    Search   string     // タイトルまたは説明に含まれる文字列で絞り込む（空の場合は絞り込まない）
+    Sort     SortField  // 並び替え項目
+    Desc     bool       // trueの場合、降順に並び替える
+    Limit    int        // 取得する最大件数（0の場合は制限しない）
+    After    *Cursor    // 指定したカーソルより後ろのタスクのみを取得する（nilの場合は先頭から）
+    AsOf     *time.Time // 指定した日時の時点の状態から取得する（nilの場合は現在。イベントストアでのみ指定できる）
+}
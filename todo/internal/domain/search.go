@@ -0,0 +1,16 @@
+package domain
+
+// 検索結果で一致箇所を囲む文字列
+const (
+    HighlightStart = "<mark>"
+    HighlightEnd   = "</mark>"
+)
+
+// SearchResult は全文検索で一致したTODOと一致箇所
+// Title と Snippet の本文はエスケープしないため、HTMLとして表示する場合は呼び出し側でエスケープする
+type SearchResult struct {
+    Todo    Todo    `json:"todo"`              // 一致したTODO
+    Rank    float64 `json:"rank"`              // 関連度（小さいほど関連が高い。部分一致で検索した場合はタイトルに一致すると-1、それ以外は0）
+    Title   string  `json:"title"`             // 一致箇所を<mark>で囲んだタイトル
+    Snippet string  `json:"snippet,omitempty"` // 説明のうち一致箇所の前後を抜き出したもの
+}
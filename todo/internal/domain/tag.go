@@ -0,0 +1,8 @@
+package domain
+
+// Tag はタスクを分類するためのラベル
+// タスクとは多対多の関係（中間テーブル todo_tags）で結びつく
+type Tag struct {
+    ID   uint   `gorm:"primaryKey"`                       // タグの一意識別子
+    Name string `gorm:"uniqueIndex;not null" json:"name"` // タグ名（重複不可）
+}
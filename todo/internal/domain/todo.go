@@ -1,8 +1,47 @@
 package domain
 
+import "time"
+
 // Todo はタスク管理のための基本的なデータ構造
 type Todo struct {
-    ID    uint   `gorm:"primaryKey"` // タスクの一意識別子
-    Title string `json:"title"`  // タスクのタイトル
-    Done  bool   `json:"done"`   // タスクの完了状態（true: 完了、false: 未完了）
+    ID          uint       `gorm:"primaryKey"`                                 // タスクの一意識別子
+    ParentID    *uint      `gorm:"index" json:"parent_id,omitempty"`           // 親タスクのID（サブタスクの場合のみ設定）
+    Title       string     `json:"title"`                                      // タスクのタイトル
+    Description string     `gorm:"type:text" json:"description,omitempty"`     // タスクの詳細な説明（任意）
+    Done        bool       `json:"done"`                                       // タスクの完了状態（true: 完了、false: 未完了）
+    Priority    Priority   `gorm:"not null;default:0;index" json:"priority"`   // タスクの優先度
+    StartAt     *time.Time `json:"start_at,omitempty"`                         // 開始日時（未設定の場合はnil）
+    DueAt       *time.Time `gorm:"index" json:"due_at,omitempty"`              // 期限日時（未設定の場合はnil）
+    Recurrence  string     `gorm:"size:255" json:"recurrence,omitempty"`       // 繰り返しルール（RFC 5545 の RRULE。繰り返さない場合は空）
+    Version     uint       `gorm:"not null;default:1" json:"version"`          // 更新のたびに増えるバージョン（楽観的排他制御に使用）
+    CreatedAt   time.Time  `json:"created_at"`                                 // 作成日時（GORMが自動で設定）
+    DeletedAt   *time.Time `gorm:"index" json:"deleted_at,omitempty"`          // ゴミ箱に移動した日時（ゴミ箱にない場合はnil）
+    Tags        []Tag      `gorm:"many2many:todo_tags;" json:"tags,omitempty"` // 付与されたタグ
+}
+
+// IsDeleted はタスクがゴミ箱にあるかどうかを判定する
+func (t Todo) IsDeleted() bool {
+    return t.DeletedAt != nil
+}
+
+// NormalizeSchedule は開始日時と期限日時をUTCにそろえる
+// SQLiteなどは日時を時差付きの文字列として保存・比較するため、保存する前に呼び出して時差の異なる値が混ざらないようにする
+func (t *Todo) NormalizeSchedule() {
+    t.StartAt = utcTime(t.StartAt)
+    t.DueAt = utcTime(t.DueAt)
+}
+
+// utcTime は日時をUTCにしたコピーを返す（nilの場合はnil）
+func utcTime(t *time.Time) *time.Time {
+    if t == nil {
+        return nil
+    }
+    utc := t.UTC()
+    return &utc
+}
+
+// IsOverdue は指定時刻の時点でタスクが期限切れかどうかを判定する
+// 完了済みのタスクや期限が未設定のタスクは期限切れとみなさない
+func (t Todo) IsOverdue(now time.Time) bool {
+    return !t.Done && t.DueAt != nil && t.DueAt.Before(now)
 }
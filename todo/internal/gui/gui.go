@@ -1,13 +1,22 @@
 package gui
 
 import (
+	"cmp"
+	"context"
+	"errors"
 	"fmt"
 	"image/color"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/client"
 	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/logger"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
@@ -19,82 +28,185 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
-func StartGUI(apiBaseURL string) {
+// 期限切れタスクの背景色
+var overdueColor = color.NRGBA{R: 0xff, G: 0x52, B: 0x52, A: 0x40}
+
+// サーバーの状態の表示色（確認中・接続中・接続できない）
+var (
+	checkingColor = color.NRGBA{R: 0xa0, G: 0xa0, B: 0xa0, A: 0xff}
+	readyColor    = color.NRGBA{R: 0x4c, G: 0xaf, B: 0x50, A: 0xff}
+	notReadyColor = color.NRGBA{R: 0xff, G: 0x52, B: 0x52, A: 0xff}
+)
+
+// 優先度の表示名
+var priorityLabels = map[domain.Priority]string{
+	domain.PriorityNone:   "なし",
+	domain.PriorityLow:    "低",
+	domain.PriorityMedium: "中",
+	domain.PriorityHigh:   "高",
+	domain.PriorityUrgent: "緊急",
+}
+
+// タグで絞り込まない場合の選択肢
+const allTagsLabel = "すべてのタグ"
+
+// 表示していたタスクが他の操作で更新されていた場合のメッセージ
+const conflictMessage = "このタスクは他の操作で更新されています。最新の内容を表示しますので、もう一度操作してください。"
+
+// 削除後に「元に戻す」を表示しておく時間
+const undoSnackbarDuration = 5 * time.Second
+
+// sortOption は並び替えの選択肢に対応するAPIのパラメータ
+type sortOption struct {
+	field string
+	desc  bool
+}
+
+// 並び替えの選択肢（表示順）
+var sortOptionLabels = []string{"作成順", "優先度順", "期限順", "タイトル順"}
+
+var sortOptions = map[string]sortOption{
+	"作成順":   {field: "created"},
+	"優先度順":  {field: "priority", desc: true},
+	"期限順":   {field: "due"},
+	"タイトル順": {field: "title"},
+}
+
+// 繰り返しの選択肢（表示順）
+var recurrenceLabels = []string{"繰り返しなし", "毎日", "平日", "毎週", "毎月", "毎月第1月曜日"}
+
+// 繰り返しの選択肢に対応する RRULE
+var recurrenceRules = map[string]string{
+	"繰り返しなし":  "",
+	"毎日":      "FREQ=DAILY",
+	"平日":      "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR",
+	"毎週":      "FREQ=WEEKLY",
+	"毎月":      "FREQ=MONTHLY",
+	"毎月第1月曜日": "FREQ=MONTHLY;BYDAY=1MO",
+}
+
+// StartGUI はAPIを利用するGUIを起動し、ウィンドウを閉じるか ctx が終了するまで戻らない（opts はAPIクライアントの設定）
+// ctx が終了した場合は送信中のリクエストを取り消してGUIを終了する
+// logDir を指定した場合は、メニューにログフォルダを開く項目を追加する
+func StartGUI(ctx context.Context, apiBaseURL string, logDir string, opts ...client.Option) {
 	a := app.New()
 	w := a.NewWindow("TODO アプリ")
 
-	todoClient := client.NewTodoClient(apiBaseURL)
+	todoClient := client.NewTodoClient(apiBaseURL, opts...).WithContext(ctx)
 	var todos []domain.Todo
-	currentFilter := "all"
+	var currentDone *bool // nilの場合は完了状態で絞り込まない
+	currentQuery := ""
+	currentSort := sortOptions[sortOptionLabels[0]]
+	currentTag := ""
 
 	var todoList *widget.List
-	// タスクのリフレッシュ
+	var tagSelect *widget.Select
+	var trashed []domain.Todo
+	var trashList *widget.List
+	// タスクのリフレッシュ（絞り込み・検索・並び替えはサーバー側で行い、ページを順にたどってすべて取得する）
 	refreshTodos := func() {
-		t, err := todoClient.GetTodos()
-		if err != nil {
-			dialog.ShowError(fmt.Errorf("TODOの取得に失敗しました: %v", err), w)
-			return
+		opts := client.ListOptions{Done: currentDone, Query: currentQuery, Sort: currentSort.field, Desc: currentSort.desc}
+		if currentTag != "" {
+			opts.Tags = []string{currentTag}
+		}
+		var t []domain.Todo
+		for todo, err := range todoClient.AllTodos(opts) {
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("TODOの取得に失敗しました: %v", err), w)
+				return
+			}
+			t = append(t, todo)
 		}
 		todos = t
 		todoList.Refresh()
+
+		// タグの選択肢を最新の状態にする
+		tags, err := todoClient.GetTags()
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("タグの取得に失敗しました: %v", err), w)
+			return
+		}
+		options := []string{allTagsLabel}
+		for _, tag := range tags {
+			options = append(options, tag.Name)
+		}
+		tagSelect.Options = options
+		tagSelect.Refresh()
 	}
 
-	// フィルタリングされたタスクの取得
-	filteredTodos := func() []domain.Todo {
-		switch currentFilter {
-		case "done":
-			var done []domain.Todo
-			for _, t := range todos {
-				if t.Done {
-					done = append(done, t)
-				}
-			}
-			return done
-		case "undone":
-			var undone []domain.Todo
-			for _, t := range todos {
-				if !t.Done {
-					undone = append(undone, t)
-				}
-			}
-			return undone
-		default:
-			return todos
+	// ゴミ箱のリフレッシュ
+	refreshTrash := func() {
+		t, err := todoClient.GetTrash()
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("ゴミ箱の取得に失敗しました: %v", err), w)
+			return
+		}
+		trashed = t
+		trashList.Refresh()
+	}
+
+	// ゴミ箱のタスクを元に戻す（version はゴミ箱に移動した後のバージョン）
+	restoreTodo := func(id string, version uint) {
+		_, err := todoClient.RestoreTodo(id, version)
+		if errors.Is(err, client.ErrConflict) {
+			dialog.ShowInformation("元に戻せませんでした", conflictMessage, w)
+		} else if err != nil {
+			dialog.ShowError(fmt.Errorf("タスクを元に戻せませんでした: %v", err), w)
 		}
+		refreshTodos()
+		refreshTrash()
 	}
 
 	// タスクリストを表示
 	todoList = widget.NewList(
 		func() int {
-			return len(filteredTodos())
+			return len(todos)
 		},
 		func() fyne.CanvasObject {
 			completeCheck := widget.NewCheck("", nil)  // 完了用チェックボックス
 			label := widget.NewLabel("")
-			left := container.NewHBox(completeCheck, label)
+			titleEntry := widget.NewEntry() // タイトルのインライン編集用
+			titleEntry.Hide()
+			title := container.NewStack(label, titleEntry)
 
+			editBtn := widget.NewButtonWithIcon("", theme.DocumentCreateIcon(), nil)
 			deleteBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), nil)
-			row := container.NewBorder(nil, nil, nil, deleteBtn, left)
+			row := container.NewBorder(nil, nil, completeCheck, container.NewHBox(editBtn, deleteBtn), title)
 
 			bg := canvas.NewRectangle(color.Transparent)
 			return container.NewStack(bg, row)
 		},
 		func(i widget.ListItemID, obj fyne.CanvasObject) {
+			bg := obj.(*fyne.Container).Objects[0].(*canvas.Rectangle)
 			row := obj.(*fyne.Container).Objects[1].(*fyne.Container)
 		
-			left := row.Objects[0].(*fyne.Container)
-			deleteBtn := row.Objects[1].(*widget.Button)
+			title := row.Objects[0].(*fyne.Container)
+			completeCheck := row.Objects[1].(*widget.Check)
+			buttons := row.Objects[2].(*fyne.Container)
 		
-			completeCheck := left.Objects[0].(*widget.Check)
-			label := left.Objects[1].(*widget.Label)
+			label := title.Objects[0].(*widget.Label)
+			titleEntry := title.Objects[1].(*widget.Entry)
+			editBtn := buttons.Objects[0].(*widget.Button)
+			deleteBtn := buttons.Objects[1].(*widget.Button)
 		
-			filtered := filteredTodos()
-			if i >= len(filtered) {
+			if i >= len(todos) {
 				return // インデックスが範囲外の場合は何もしない
 			}
 			
-			todo := filtered[i] // i番目のタスクを取得
-			label.SetText(todo.Title)
+			todo := todos[i] // i番目のタスクを取得
+			label.SetText(todoLabel(todo))
+
+			// 行は使い回されるため、編集中の状態を解除しておく
+			titleEntry.Hide()
+			label.Show()
+
+			// 期限切れのタスクは背景色で強調表示
+			if todo.IsOverdue(time.Now()) {
+				bg.FillColor = overdueColor
+			} else {
+				bg.FillColor = color.Transparent
+			}
+			bg.Refresh()
 
 			// 重要: OnChangedハンドラを設定する前にチェック状態を設定
 			completeCheck.OnChanged = nil // 一時的にハンドラを無効化
@@ -103,18 +215,61 @@ func StartGUI(apiBaseURL string) {
 			// OnChangedの中で参照がずれないようにtodoIDを固定
 			todoID := strconv.Itoa(int(todo.ID))
 		
+			// 更新・削除は表示しているバージョンを指定し、他の操作で更新されていた場合は最新の内容を表示し直す
 			completeCheck.OnChanged = func(done bool) {
-				go func(id string, status bool) {
-					todoClient.PutTodoCompletionStatus(id, status)
+				go func(id string, status bool, version uint) {
+					if _, err := todoClient.PutTodoCompletionStatus(id, status, version); errors.Is(err, client.ErrConflict) {
+						dialog.ShowInformation("更新できませんでした", conflictMessage, w)
+					}
 					time.Sleep(200 * time.Millisecond)
 					refreshTodos()
-				}(todoID, done)
+				}(todoID, done, todo.Version)
 			}
 		
+			// 編集ボタンでタイトルを入力欄に切り替え、Enterで保存する（もう一度押すと取り消し）
+			editBtn.OnTapped = func() {
+				if titleEntry.Visible() {
+					titleEntry.Hide()
+					label.Show()
+					return
+				}
+				titleEntry.SetText(todo.Title)
+				label.Hide()
+				titleEntry.Show()
+				w.Canvas().Focus(titleEntry)
+			}
+			titleEntry.OnSubmitted = func(text string) {
+				if text != todo.Title {
+					_, err := todoClient.PatchTodo(todoID, client.TodoPatch{Title: &text}, todo.Version)
+					if errors.Is(err, client.ErrConflict) {
+						dialog.ShowInformation("更新できませんでした", conflictMessage, w)
+						refreshTodos()
+						return
+					}
+					if err != nil {
+						dialog.ShowError(fmt.Errorf("タイトルの変更に失敗しました: %v", err), w)
+						return
+					}
+				}
+				titleEntry.Hide()
+				label.Show()
+				refreshTodos()
+			}
+		
+			// 削除したタスクはゴミ箱に移動し、しばらくの間は「元に戻す」で取り消せる
 			deleteBtn.OnTapped = func() {
 				dialog.ShowConfirm("確認", "このタスクを削除しますか？", func(confirmed bool) {
 					if confirmed {
-						todoClient.DeleteTodoByID(todoID)
+						err := todoClient.DeleteTodoByID(todoID, todo.Version)
+						if errors.Is(err, client.ErrConflict) {
+							dialog.ShowInformation("削除できませんでした", conflictMessage, w)
+						} else if err != nil {
+							dialog.ShowError(fmt.Errorf("タスクの削除に失敗しました: %v", err), w)
+						} else {
+							showSnackbar(w.Canvas(), "タスクを削除しました", "元に戻す", func() {
+								restoreTodo(todoID, todo.Version+1)
+							})
+						}
 						refreshTodos()
 					}
 				}, w)
@@ -125,38 +280,168 @@ func StartGUI(apiBaseURL string) {
 	input := widget.NewEntry()
 	input.SetPlaceHolder("タスクを入力してください")
 
-	addBtn := widget.NewButton("追加", func() {
+	prioritySelect := widget.NewSelect(priorityOptions(), nil)
+	prioritySelect.SetSelected(priorityLabels[domain.PriorityNone])
+
+	recurrenceSelect := widget.NewSelect(recurrenceLabels, nil)
+	recurrenceSelect.SetSelected(recurrenceLabels[0])
+
+	dueInput := widget.NewEntry()
+	dueInput.SetPlaceHolder("期限 (YYYY-MM-DD または YYYY-MM-DD HH:MM、省略可)")
+
+	// 通信が遅い場合に二重に追加しないよう、送信中は追加ボタンを無効にする
+	// （応答を受け取れずに送り直した場合も、クライアントが同じIdempotency-Keyを付けるためサーバーで重複しない）
+	var addBtn *widget.Button
+	addBtn = widget.NewButton("追加", func() {
 		if input.Text != "" {
-			_, err := todoClient.CreateTodo(input.Text)
+			dueAt, err := parseDueDate(dueInput.Text)
 			if err != nil {
-				dialog.ShowError(fmt.Errorf("TODOの追加に失敗しました: %v", err), w)
+				dialog.ShowError(err, w)
 				return
 			}
-			input.SetText("")
-			refreshTodos() // 画面を更新
+			req := client.CreateTodoRequest{
+				Title:    input.Text,
+				Priority: priorityFromLabel(prioritySelect.Selected).String(),
+				DueAt:    dueAt,
+				// 繰り返しは期限日時を起点にするため、期限の入力が必要
+				Recurrence: recurrenceRules[recurrenceSelect.Selected],
+			}
+			addBtn.Disable()
+			go func() {
+				defer addBtn.Enable()
+				if _, err := todoClient.CreateTodoWithRequest(req); err != nil {
+					dialog.ShowError(fmt.Errorf("TODOの追加に失敗しました: %v", err), w)
+					return
+				}
+				input.SetText("")
+				dueInput.SetText("")
+				prioritySelect.SetSelected(priorityLabels[domain.PriorityNone])
+				recurrenceSelect.SetSelected(recurrenceLabels[0])
+				refreshTodos() // 画面を更新
+			}()
 		}
 	})
 
-	inputLine := container.NewBorder(nil, nil, nil, addBtn, input)
+	inputLine := container.NewVBox(
+		container.NewBorder(nil, nil, nil, addBtn, input),
+		container.NewBorder(nil, nil, container.NewHBox(prioritySelect, recurrenceSelect), nil, dueInput),
+	)
 
-	// フィルターボタン
+	// フィルターボタン（完了状態での絞り込みもサーバー側で行う）
 	filterRadio := widget.NewRadioGroup([]string{"全て", "未完了のみ", "完了のみ"}, func(value string) {
 		switch value {
 		case "全て":
-			currentFilter = "all"
+			currentDone = nil
 		case "未完了のみ":
-			currentFilter = "undone"
+			done := false
+			currentDone = &done
 		case "完了のみ":
-			currentFilter = "done"
+			done := true
+			currentDone = &done
 		}
-		todoList.Refresh()
+		refreshTodos()
 	})
 	filterRadio.Horizontal = true
 	filterRadio.Selected = "全て" // 初期状態
 
+	// 並び替えの選択
+	sortSelect := widget.NewSelect(sortOptionLabels, func(value string) {
+		currentSort = sortOptions[value]
+		refreshTodos()
+	})
+	sortSelect.Selected = sortOptionLabels[0] // 初期状態
+
+	// タグでの絞り込み
+	tagSelect = widget.NewSelect([]string{allTagsLabel}, func(value string) {
+		if value == allTagsLabel {
+			currentTag = ""
+		} else {
+			currentTag = value
+		}
+		refreshTodos()
+	})
+	tagSelect.Selected = allTagsLabel // 初期状態
+
+	// キーワード検索（Enterで検索し、空にすると解除）
+	searchInput := widget.NewEntry()
+	searchInput.SetPlaceHolder("キーワードで検索")
+	searchInput.OnSubmitted = func(text string) {
+		currentQuery = strings.TrimSpace(text)
+		refreshTodos()
+	}
+
+	// 表示している未完了のタスクをすべて完了にする（1つでも完了にできない場合は何も変更しない）
+	completeAllBtn := widget.NewButtonWithIcon("すべて完了", theme.ConfirmIcon(), func() {
+		var ops []client.BulkOperation
+		done := true
+		for _, todo := range childrenFirst(todos) {
+			if !todo.Done {
+				ops = append(ops, client.BulkOperation{Action: client.BulkUpdate, ID: todo.ID, Version: todo.Version, Patch: &client.TodoPatch{Done: &done}})
+			}
+		}
+		if len(ops) == 0 {
+			return
+		}
+		_, err := todoClient.BulkTodos(ops, false)
+		if errors.Is(err, client.ErrConflict) {
+			dialog.ShowInformation("完了にできませんでした", conflictMessage, w)
+		} else if err != nil {
+			dialog.ShowError(fmt.Errorf("タスクを完了にできませんでした: %v", err), w)
+		}
+		refreshTodos()
+	})
+
+	// 完了したタスクを絞り込みに関係なくすべてゴミ箱に移動する（削除できなかったものがあっても残りは削除する）
+	deleteDoneBtn := widget.NewButtonWithIcon("完了済みを削除", theme.DeleteIcon(), func() {
+		done := true
+		var ops []client.BulkOperation
+		for todo, err := range todoClient.AllTodos(client.ListOptions{Done: &done}) {
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("TODOの取得に失敗しました: %v", err), w)
+				return
+			}
+			ops = append(ops, client.BulkOperation{Action: client.BulkDelete, ID: todo.ID, Version: todo.Version})
+		}
+		if len(ops) == 0 {
+			return
+		}
+		dialog.ShowConfirm("確認", fmt.Sprintf("完了した %d 件のタスクを削除しますか？", len(ops)), func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			result, err := todoClient.BulkTodos(ops, true)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("タスクの削除に失敗しました: %v", err), w)
+				refreshTodos()
+				return
+			}
+			failed := 0
+			for _, item := range result.Results {
+				// 親タスクと一緒にゴミ箱に移動したサブタスクは見つからないため、失敗として扱わない
+				if item.Status != http.StatusNoContent && item.Status != http.StatusNotFound {
+					failed++
+				}
+			}
+			if failed > 0 {
+				dialog.ShowInformation("一部のタスクを削除できませんでした", fmt.Sprintf("%d 件のタスクは削除できませんでした。最新の内容を表示しますので、もう一度操作してください。", failed), w)
+			}
+			refreshTodos()
+		}, w)
+	})
+
+	filterLine := container.NewVBox(
+		container.NewBorder(nil, nil, nil, container.NewHBox(tagSelect, sortSelect), filterRadio),
+		container.NewBorder(nil, nil, nil, container.NewHBox(completeAllBtn, deleteDoneBtn), searchInput),
+	)
+
+	// サーバーの状態（/readyz）を定期的に確認し、ヘッダーに表示する
+	readyText := canvas.NewText("● 確認中", checkingColor)
+	go watchReadiness(ctx, todoClient, readyText, logger.GetLogger().Named(logger.ComponentGUI))
+
 	header := container.NewHBox(
 		canvas.NewText("Todoアプリ", color.White),
 		layout.NewSpacer(),
+		readyText,
 	)
 
 	headerBG := canvas.NewRectangle(color.Black)
@@ -166,16 +451,217 @@ func StartGUI(apiBaseURL string) {
 	scroll := container.NewScroll(todoList)
 	scroll.SetMinSize(fyne.NewSize(400, 300))
 
+	// ゴミ箱のタスク一覧（元に戻すボタン付き）
+	trashList = widget.NewList(
+		func() int {
+			return len(trashed)
+		},
+		func() fyne.CanvasObject {
+			restoreBtn := widget.NewButtonWithIcon("元に戻す", theme.ContentUndoIcon(), nil)
+			return container.NewBorder(nil, nil, nil, restoreBtn, widget.NewLabel(""))
+		},
+		func(i widget.ListItemID, obj fyne.CanvasObject) {
+			if i >= len(trashed) {
+				return
+			}
+			row := obj.(*fyne.Container)
+			label := row.Objects[0].(*widget.Label)
+			restoreBtn := row.Objects[1].(*widget.Button)
+
+			todo := trashed[i]
+			label.SetText(fmt.Sprintf("%s（%s に削除）", todo.Title, todo.DeletedAt.Local().Format("2006-01-02 15:04")))
+			restoreBtn.OnTapped = func() {
+				restoreTodo(strconv.Itoa(int(todo.ID)), todo.Version)
+			}
+		},
+	)
+	trashScroll := container.NewScroll(trashList)
+	trashScroll.SetMinSize(fyne.NewSize(400, 300))
+
+	tabs := container.NewAppTabs(
+		container.NewTabItem("タスク", container.NewVBox(inputLine, filterLine, scroll)),
+		container.NewTabItem("ゴミ箱", container.NewVBox(
+			widget.NewLabel("削除したタスクは一定期間が過ぎると完全に削除されます"),
+			trashScroll,
+		)),
+	)
+	tabs.OnSelected = func(tab *container.TabItem) {
+		if tab.Text == "ゴミ箱" {
+			refreshTrash()
+		}
+	}
+
 	main := container.NewVBox(
 		headerContent,
-		inputLine,
-		filterRadio,
-		scroll,
+		tabs,
 	)
 
+	if logDir != "" {
+		w.SetMainMenu(fyne.NewMainMenu(fyne.NewMenu("ヘルプ",
+			fyne.NewMenuItem("ログフォルダを開く", func() {
+				if err := a.OpenURL(fileURL(logDir)); err != nil {
+					dialog.ShowError(fmt.Errorf("ログフォルダを開けませんでした: %v", err), w)
+				}
+			}),
+		)))
+	}
+
 	refreshTodos()
 	w.SetContent(main)
 	w.Resize(fyne.NewSize(500, 600))
 	w.SetFixedSize(false) // ウィンドウサイズ変更を許可
+	stop := context.AfterFunc(ctx, a.Quit)
+	defer stop()
 	w.ShowAndRun()
-}
\ No newline at end of file
+}
+
+// fileURL はディレクトリのパスを file:// のURLにする（相対パスは絶対パスにし、Windows のパスは /C:/... の形にする）
+func fileURL(dir string) *url.URL {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	path := filepath.ToSlash(dir)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return &url.URL{Scheme: "file", Path: path}
+}
+
+// readinessPollInterval はサーバーの状態を確認する間隔
+const readinessPollInterval = 5 * time.Second
+
+// watchReadiness は ctx が終了するまで readinessPollInterval ごとにサーバーの状態を確認し、text に表示する
+// 状態が変わった場合だけログに記録する
+func watchReadiness(ctx context.Context, todoClient *client.TodoClient, text *canvas.Text, log *logger.Logger) {
+	ticker := time.NewTicker(readinessPollInterval)
+	defer ticker.Stop()
+	var lastErr error
+	for {
+		_, err := todoClient.CheckReady()
+		if ctx.Err() != nil {
+			return
+		}
+		switch {
+		case err != nil && lastErr == nil:
+			log.Warnf("Server is not ready: %v", err)
+		case err == nil && lastErr != nil:
+			log.Info("Server is ready")
+		}
+		lastErr = err
+
+		if err != nil {
+			text.Text, text.Color = "● 接続できません", notReadyColor
+		} else {
+			text.Text, text.Color = "● 接続中", readyColor
+		}
+		text.Refresh()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// showSnackbar はウィンドウの下部にメッセージと操作ボタンを一定時間表示する
+// ボタンを押すか undoSnackbarDuration が過ぎると閉じる
+func showSnackbar(c fyne.Canvas, message string, actionLabel string, action func()) {
+	var popup *widget.PopUp
+	actionBtn := widget.NewButton(actionLabel, func() {
+		popup.Hide()
+		action()
+	})
+	popup = widget.NewPopUp(container.NewHBox(widget.NewLabel(message), actionBtn), c)
+
+	size := popup.MinSize()
+	popup.ShowAtPosition(fyne.NewPos((c.Size().Width-size.Width)/2, c.Size().Height-size.Height-theme.Padding()*4))
+	time.AfterFunc(undoSnackbarDuration, popup.Hide)
+}
+
+// childrenFirst はサブタスクが親タスクより前になるように並べ替えたタスクを返す
+// 完了ルールで親タスクを完了にする際にサブタスクが先に完了しているよう、階層の深いものから並べる
+func childrenFirst(todos []domain.Todo) []domain.Todo {
+	parents := make(map[uint]*uint, len(todos))
+	for _, todo := range todos {
+		parents[todo.ID] = todo.ParentID
+	}
+	depth := func(todo domain.Todo) int {
+		d := 0
+		for p := todo.ParentID; p != nil && d < len(todos); p = parents[*p] {
+			d++
+		}
+		return d
+	}
+
+	sorted := slices.Clone(todos)
+	slices.SortStableFunc(sorted, func(a, b domain.Todo) int { return cmp.Compare(depth(b), depth(a)) })
+	return sorted
+}
+
+// todoLabel はリストに表示するタスクの文字列を作成する
+func todoLabel(todo domain.Todo) string {
+	text := todo.Title
+	if todo.ParentID != nil {
+		text = "└ " + text // サブタスク
+	}
+	if todo.Priority != domain.PriorityNone {
+		text = fmt.Sprintf("[%s] %s", priorityLabels[todo.Priority], text)
+	}
+	if todo.DueAt != nil {
+		text = fmt.Sprintf("%s（期限: %s）", text, todo.DueAt.Local().Format("2006/01/02 15:04"))
+	}
+	if todo.Recurrence != "" {
+		text += " ↻" + recurrenceLabel(todo.Recurrence)
+	}
+	for _, tag := range todo.Tags {
+		text += " #" + tag.Name
+	}
+	return text
+}
+
+// recurrenceLabel は繰り返しルールの表示名を返す（選択肢にないルールはそのまま表示する）
+func recurrenceLabel(rule string) string {
+	for _, label := range recurrenceLabels {
+		if recurrenceRules[label] == rule {
+			return label
+		}
+	}
+	return rule
+}
+
+// priorityOptions は優先度の選択肢を低い順に返す
+func priorityOptions() []string {
+	var options []string
+	for _, p := range domain.Priorities() {
+		options = append(options, priorityLabels[p])
+	}
+	return options
+}
+
+// priorityFromLabel は表示名から優先度を取得する
+func priorityFromLabel(label string) domain.Priority {
+	for p, l := range priorityLabels {
+		if l == label {
+			return p
+		}
+	}
+	return domain.PriorityNone
+}
+
+// parseDueDate は入力された期限を解析する
+// 日付のみが入力された場合はその日の終わりを期限とする
+func parseDueDate(text string) (*time.Time, error) {
+	if text == "" {
+		return nil, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04", text, time.Local); err == nil {
+		return &t, nil
+	}
+	d, err := time.ParseInLocation("2006-01-02", text, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("期限の形式が正しくありません: %s", text)
+	}
+	endOfDay := time.Date(d.Year(), d.Month(), d.Day(), 23, 59, 0, 0, time.Local)
+	return &endOfDay, nil
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
 	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/usecase"
 	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
 	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/utils"
@@ -38,7 +39,7 @@ func (h *TodoHandler) CreateTodoHandler(c *gin.Context) {
 	}
 
 	sanitizedTitle := utils.SanitizeInput(req.Title)
-    todo, err := h.usecase.CreateTodo(sanitizedTitle)
+    todo, err := h.usecase.CreateTodo(usecase.CreateTodoInput{Title: sanitizedTitle})
     if err != nil {
         if errors.IsInvalidInput(err) {
             c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -52,7 +53,7 @@ func (h *TodoHandler) CreateTodoHandler(c *gin.Context) {
 
 // GetTodosHandler はすべてのTODOを取得するハンドラ
 func (h *TodoHandler) GetTodosHandler(c *gin.Context) {
-    todos, err := h.usecase.GetTodos()
+    todos, err := h.usecase.GetTodos(domain.TodoQuery{})
     if err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{"error": "Todoの取得中にエラーが発生しました"})
         return
@@ -68,7 +69,7 @@ func (h *TodoHandler) UpdateTodoHandler(c *gin.Context) {
         c.JSON(http.StatusBadRequest, gin.H{"error": "無効なステータスです"})
         return
     }
-    todo, err := h.usecase.UpdateTodo(id, doneStatus)
+    todo, err := h.usecase.UpdateTodo(id, doneStatus, 0)
     if err != nil {
         c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
         return
@@ -79,7 +80,7 @@ func (h *TodoHandler) UpdateTodoHandler(c *gin.Context) {
 // DeleteTodoHandler はTODOを削除するハンドラ
 func (h *TodoHandler) DeleteTodoHandler(c *gin.Context) {
     id := c.Param("id")
-    if err := h.usecase.DeleteTodoByID(id); err != nil {
+    if err := h.usecase.DeleteTodoByID(id, 0); err != nil {
         c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
         return
     }
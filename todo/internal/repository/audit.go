@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"gorm.io/gorm"
+)
+
+// auditRecord は監査ログのテーブル（audit_logs）の1行
+// 変更した項目はJSONの文字列として保存する
+type auditRecord struct {
+	ID        uint `gorm:"primaryKey"`
+	TodoID    uint
+	Action    string
+	Actor     string
+	Version   uint
+	Changes   string
+	CreatedAt time.Time
+}
+
+// TableName は監査ログのテーブル名を返す
+func (auditRecord) TableName() string {
+	return "audit_logs"
+}
+
+// entry は監査ログの行をドメインモデルに変換する
+func (rec auditRecord) entry() (domain.AuditEntry, error) {
+	entry := domain.AuditEntry{
+		ID:        rec.ID,
+		TodoID:    rec.TodoID,
+		Action:    domain.AuditAction(rec.Action),
+		Actor:     rec.Actor,
+		Version:   rec.Version,
+		CreatedAt: rec.CreatedAt,
+	}
+	if rec.Changes != "" {
+		if err := json.Unmarshal([]byte(rec.Changes), &entry.Changes); err != nil {
+			return domain.AuditEntry{}, err
+		}
+	}
+	return entry, nil
+}
+
+// auditTime は監査ログに記録する日時（MySQLの datetime(3) に合わせてミリ秒に切り捨てる）
+func auditTime() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
+
+// deletedAtChanges はゴミ箱への移動・復元による deleted_at の変更
+func deletedAtChanges(before, after *time.Time) map[string]domain.FieldChange {
+	b, _ := json.Marshal(before)
+	a, _ := json.Marshal(after)
+	return map[string]domain.FieldChange{"deleted_at": {Before: b, After: a}}
+}
+
+// writeAudit は監査ログを1件記録する（audit が nil の場合は何もしない）
+// 変更したTodoのIDとバージョン、記録した日時は todo と at から設定する
+func writeAudit(tx *gorm.DB, audit *domain.AuditEntry, todo domain.Todo, at time.Time) error {
+	if audit == nil {
+		return nil
+	}
+	audit.TodoID, audit.Version, audit.CreatedAt = todo.ID, todo.Version, at
+
+	rec := auditRecord{
+		TodoID:    audit.TodoID,
+		Action:    string(audit.Action),
+		Actor:     audit.Actor,
+		Version:   audit.Version,
+		CreatedAt: audit.CreatedAt,
+	}
+	if len(audit.Changes) > 0 {
+		changes, err := json.Marshal(audit.Changes)
+		if err != nil {
+			return err
+		}
+		rec.Changes = string(changes)
+	}
+	if err := tx.Create(&rec).Error; err != nil {
+		return err
+	}
+	audit.ID = rec.ID
+	return nil
+}
+
+// writeAuditForIDs は指定したTodoのそれぞれについて、audit と同じ操作・変更内容の監査ログを記録する
+// バージョンは記録する時点で保存されているものを使う（audit が nil の場合は何もしない）
+func writeAuditForIDs(tx *gorm.DB, audit *domain.AuditEntry, ids []uint, changes map[string]domain.FieldChange, at time.Time) error {
+	if audit == nil || len(ids) == 0 {
+		return nil
+	}
+	var todos []domain.Todo
+	if err := tx.Select("id", "version").Where("id IN ?", ids).Order("id").Find(&todos).Error; err != nil {
+		return err
+	}
+	for _, todo := range todos {
+		entry := *audit
+		entry.Changes = changes
+		if err := writeAudit(tx, &entry, todo, at); err != nil {
+			return err
+		}
+		if todo.ID == ids[0] {
+			*audit = entry // 指定したTodoの監査ログを呼び出し元に返す
+		}
+	}
+	return nil
+}
+
+// FindAuditLog は条件に一致する監査ログを記録した順に取得するメソッド
+func (r *TodoRepository) FindAuditLog(query domain.AuditQuery) ([]domain.AuditEntry, error) {
+	db := r.db.Model(&auditRecord{})
+	if query.TodoID != nil {
+		db = db.Where("todo_id = ?", *query.TodoID)
+	}
+	if query.Actor != "" {
+		db = db.Where("actor = ?", query.Actor)
+	}
+	if query.Since != nil {
+		db = db.Where("created_at >= ?", query.Since.UTC())
+	}
+	if query.Until != nil {
+		db = db.Where("created_at < ?", query.Until.UTC())
+	}
+	if query.Limit > 0 {
+		db = db.Limit(query.Limit)
+	}
+
+	var records []auditRecord
+	if err := db.Order("id").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	entries := make([]domain.AuditEntry, 0, len(records))
+	for _, rec := range records {
+		entry, err := rec.entry()
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
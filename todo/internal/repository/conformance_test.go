@@ -0,0 +1,721 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/infrastructure"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/infrastructure/migration"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// このファイルのテストは、GORM（SQLite）・メモリ・JSONファイル・イベントログのすべてのリポジトリで同じ振る舞いになることを確認する
+
+// repositories はテスト対象のリポジトリの組
+type repositories struct {
+	todos TodoRepositoryInterface
+	tags  TagRepositoryInterface
+}
+
+// backends は振る舞いをそろえるリポジトリの実装
+var backends = []struct {
+	name string
+	open func(t *testing.T) repositories
+}{
+	{
+		name: "gorm",
+		open: func(t *testing.T) repositories {
+			db := setupSQLiteDB(t)
+			return repositories{todos: NewTodoRepository(db), tags: NewTagRepository(db)}
+		},
+	},
+	{
+		name: "memory",
+		open: func(t *testing.T) repositories {
+			store := NewMemoryStore()
+			return repositories{todos: store.TodoRepository(), tags: store.TagRepository()}
+		},
+	},
+	{
+		name: "file",
+		open: func(t *testing.T) repositories {
+			store, err := OpenFileStore(filepath.Join(t.TempDir(), "todo.json"))
+			require.NoError(t, err)
+			t.Cleanup(func() { store.Close() })
+			return repositories{todos: store.TodoRepository(), tags: store.TagRepository()}
+		},
+	},
+	{
+		name: "events",
+		open: func(t *testing.T) repositories {
+			// スナップショットの作成も確認できるよう、間隔を短くする
+			store, err := OpenEventStore(filepath.Join(t.TempDir(), "todo.events"), WithSnapshotInterval(3))
+			require.NoError(t, err)
+			t.Cleanup(func() { store.Close() })
+			return repositories{todos: store.TodoRepository(), tags: store.TagRepository()}
+		},
+	},
+}
+
+// forEachBackend はすべてのリポジトリの実装で fn を実行する
+func forEachBackend(t *testing.T, fn func(t *testing.T, repos repositories)) {
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			fn(t, backend.open(t))
+		})
+	}
+}
+
+// setupSQLiteDB はインメモリのSQLiteデータベースを作成し、マイグレーションを適用する
+// 接続ごとに別のデータベースになるため、接続数を1に制限する
+func setupSQLiteDB(t *testing.T) *gorm.DB {
+	db, err := infrastructure.InitDB("sqlite://file::memory:", infrastructure.WithMaxOpenConns(1))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	})
+	migrator, err := migration.New(db)
+	require.NoError(t, err)
+	_, err = migrator.Up()
+	require.NoError(t, err)
+	return db
+}
+
+// createTodos はテスト用のTodoを作成する
+func createTodos(t *testing.T, repo TodoRepositoryInterface, todos ...*domain.Todo) {
+	for _, todo := range todos {
+		require.NoError(t, repo.Create(todo, nil))
+	}
+}
+
+// todoIDs はTodoのIDを順に返す
+func todoIDs(todos []domain.Todo) []uint {
+	ids := make([]uint, len(todos))
+	for i, todo := range todos {
+		ids[i] = todo.ID
+	}
+	return ids
+}
+
+// idString はIDをリポジトリの検索に渡す文字列にする
+func idString(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+// tagNames はタグの名前を順に返す
+func tagNames(tags []domain.Tag) []string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	return names
+}
+
+func TestConformanceCreateFindUpdate(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos repositories) {
+		due := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+		todo := &domain.Todo{Title: "牛乳を買う", Description: "低脂肪", Priority: domain.PriorityHigh, DueAt: &due}
+		createTodos(t, repos.todos, todo)
+		assert.NotZero(t, todo.ID)
+		assert.False(t, todo.CreatedAt.IsZero())
+
+		found, err := repos.todos.FindByID(idString(todo.ID))
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, "牛乳を買う", found.Title)
+		assert.Equal(t, "低脂肪", found.Description)
+		assert.Equal(t, domain.PriorityHigh, found.Priority)
+		assert.True(t, due.Equal(*found.DueAt))
+		assert.True(t, todo.CreatedAt.Equal(found.CreatedAt))
+		assert.Empty(t, found.Tags)
+
+		missing, err := repos.todos.FindByID("999")
+		require.NoError(t, err)
+		assert.Nil(t, missing)
+
+		// 更新してもタグとの関連は変わらない
+		tag := &domain.Tag{Name: "買い物"}
+		require.NoError(t, repos.tags.Create(tag))
+		require.NoError(t, repos.tags.Attach(found, tag))
+		found.Title = "豆乳を買う"
+		found.Done = true
+		found.DueAt = nil
+		found.Tags = nil
+		require.NoError(t, repos.todos.Update(found, nil))
+
+		updated, err := repos.todos.FindByID(idString(todo.ID))
+		require.NoError(t, err)
+		assert.Equal(t, "豆乳を買う", updated.Title)
+		assert.True(t, updated.Done)
+		assert.Nil(t, updated.DueAt)
+		assert.Equal(t, []string{"買い物"}, tagNames(updated.Tags))
+
+		// タグ付きで作成したTodoは関連も保存する（繰り返しタスクの次の回）
+		next := &domain.Todo{Title: "牛乳を買う", Tags: updated.Tags}
+		createTodos(t, repos.todos, next)
+		found, err = repos.todos.FindByID(idString(next.ID))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"買い物"}, tagNames(found.Tags))
+	})
+}
+
+func TestConformanceFindAllPaging(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos repositories) {
+		repo := repos.todos
+
+		base := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+		due := func(d int) *time.Time {
+			v := base.AddDate(0, 0, d)
+			return &v
+		}
+		createTodos(t, repo,
+			&domain.Todo{Title: "b", Priority: domain.PriorityHigh, DueAt: due(2)},
+			&domain.Todo{Title: "a", Priority: domain.PriorityLow},
+			&domain.Todo{Title: "c", Priority: domain.PriorityHigh, DueAt: due(1)},
+			&domain.Todo{Title: "a", Priority: domain.PriorityNone, DueAt: due(2)},
+			&domain.Todo{Title: "d", Priority: domain.PriorityLow},
+		)
+
+		for _, sort := range []domain.SortField{domain.SortByDefault, domain.SortByPriority, domain.SortByCreated, domain.SortByDue, domain.SortByTitle} {
+			for _, desc := range []bool{false, true} {
+				query := domain.TodoQuery{Sort: sort, Desc: desc}
+				all, err := repo.FindAll(query)
+				require.NoError(t, err)
+				require.Len(t, all, 5)
+
+				// 2件ずつカーソルでたどった結果が、一度に取得した結果と一致すること
+				var paged []uint
+				query.Limit = 2
+				for {
+					page, err := repo.FindAll(query)
+					require.NoError(t, err)
+					for _, todo := range page {
+						paged = append(paged, todo.ID)
+					}
+					if len(page) < query.Limit {
+						break
+					}
+					cursor := domain.NewCursor(page[len(page)-1], sort, desc)
+					query.After = &cursor
+				}
+
+				var expected []uint
+				for _, todo := range all {
+					expected = append(expected, todo.ID)
+				}
+				assert.Equal(t, expected, paged, "sort=%s desc=%v", sort, desc)
+			}
+		}
+
+		// 期限未設定のタスクは並び順に関わらず末尾に置く
+		byDue, err := repo.FindAll(domain.TodoQuery{Sort: domain.SortByDue, Desc: true})
+		require.NoError(t, err)
+		assert.Nil(t, byDue[3].DueAt)
+		assert.Nil(t, byDue[4].DueAt)
+	})
+}
+
+func TestConformanceSearchAndCount(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos repositories) {
+		repo := repos.todos
+		createTodos(t, repo,
+			&domain.Todo{Title: "Weekly MEETING", Description: "議事録を共有"},
+			&domain.Todo{Title: "買い物", Description: "meeting room の予約", Done: true},
+			&domain.Todo{Title: "100%達成"},
+		)
+
+		todos, err := repo.FindAll(domain.TodoQuery{Search: "Meeting"})
+		require.NoError(t, err)
+		assert.Len(t, todos, 2)
+
+		done := false
+		count, err := repo.Count(domain.TodoQuery{Search: "meeting", Done: &done})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		// ワイルドカードの文字はそのままの文字として検索する
+		count, err = repo.Count(domain.TodoQuery{Search: "0%達"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+		count, err = repo.Count(domain.TodoQuery{Search: "1_0"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+
+		results, err := repo.Search("meeting", 10)
+		require.NoError(t, err)
+		if assert.Len(t, results, 2) {
+			// タイトルに一致したものを先に返す
+			assert.Equal(t, "Weekly <mark>MEETING</mark>", results[0].Title)
+			assert.Equal(t, "<mark>meeting</mark> room の予約", results[1].Snippet)
+		}
+	})
+}
+
+func TestConformanceDeleteWithSubtasks(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos repositories) {
+		repo := repos.todos
+
+		parent := &domain.Todo{Title: "親"}
+		createTodos(t, repo, parent)
+		child := &domain.Todo{Title: "子", ParentID: &parent.ID}
+		createTodos(t, repo, child)
+		grandchild := &domain.Todo{Title: "孫", ParentID: &child.ID}
+		other := &domain.Todo{Title: "別のタスク"}
+		createTodos(t, repo, grandchild, other)
+
+		tag := &domain.Tag{Name: "仕事"}
+		require.NoError(t, repos.tags.Create(tag))
+		require.NoError(t, repos.tags.Attach(grandchild, tag))
+
+		require.NoError(t, repo.Delete(parent, nil))
+
+		todos, err := repo.FindAll(domain.TodoQuery{})
+		require.NoError(t, err)
+		if assert.Len(t, todos, 1) {
+			assert.Equal(t, other.ID, todos[0].ID)
+		}
+
+		// ゴミ箱のTodoはタグで絞り込んでも一致せず、タグ自体は残る
+		count, err := repo.Count(domain.TodoQuery{Tags: []string{"仕事"}})
+		require.NoError(t, err)
+		assert.Zero(t, count)
+		found, err := repos.tags.FindByName("仕事")
+		require.NoError(t, err)
+		assert.NotNil(t, found)
+
+		// サブタスクも一緒にゴミ箱に移動している
+		trashed, err := repo.FindTrashed()
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []uint{parent.ID, child.ID, grandchild.ID}, todoIDs(trashed))
+	})
+}
+
+func TestConformanceTrash(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos repositories) {
+		repo := repos.todos
+
+		parent := &domain.Todo{Title: "親"}
+		createTodos(t, repo, parent)
+		child := &domain.Todo{Title: "子", ParentID: &parent.ID}
+		earlier := &domain.Todo{Title: "先に削除した子", ParentID: &parent.ID}
+		createTodos(t, repo, child, earlier)
+		tag := &domain.Tag{Name: "仕事"}
+		require.NoError(t, repos.tags.Create(tag))
+		require.NoError(t, repos.tags.Attach(child, tag))
+
+		// 先に削除したサブタスクは、親を元に戻しても戻らない
+		require.NoError(t, repo.Delete(earlier, nil))
+		time.Sleep(2 * time.Millisecond)
+		require.NoError(t, repo.Delete(parent, nil))
+		assert.NotNil(t, parent.DeletedAt)
+		assert.Equal(t, uint(2), parent.Version)
+
+		missing, err := repo.FindByID(idString(parent.ID))
+		require.NoError(t, err)
+		assert.Nil(t, missing)
+		assert.ErrorIs(t, repo.Update(parent, nil), ErrVersionConflict)
+		assert.ErrorIs(t, repo.Delete(parent, nil), ErrVersionConflict)
+
+		trashed, err := repo.FindTrashed()
+		require.NoError(t, err)
+		assert.Equal(t, []uint{parent.ID, child.ID, earlier.ID}, todoIDs(trashed))
+
+		stored, err := repo.FindTrashedByID(idString(parent.ID))
+		require.NoError(t, err)
+		require.NotNil(t, stored)
+		assert.True(t, stored.IsDeleted())
+		notTrashed, err := repo.FindTrashedByID("999")
+		require.NoError(t, err)
+		assert.Nil(t, notTrashed)
+
+		// 古いバージョンでは元に戻せない
+		stale := *stored
+		stale.Version--
+		assert.ErrorIs(t, repo.Restore(&stale, nil), ErrVersionConflict)
+
+		require.NoError(t, repo.Restore(stored, nil))
+		assert.Nil(t, stored.DeletedAt)
+		assert.Equal(t, uint(3), stored.Version)
+
+		todos, err := repo.FindAll(domain.TodoQuery{})
+		require.NoError(t, err)
+		assert.Equal(t, []uint{parent.ID, child.ID}, todoIDs(todos))
+		restored, err := repo.FindByID(idString(child.ID))
+		require.NoError(t, err)
+		require.NotNil(t, restored)
+		assert.Equal(t, []string{"仕事"}, tagNames(restored.Tags))
+		assert.Equal(t, uint(4), restored.Version)
+
+		// 指定した日時より前にゴミ箱に移動したものだけを完全に削除する
+		purged, err := repo.Purge(earlier.DeletedAt.Add(-time.Second), nil)
+		require.NoError(t, err)
+		assert.Zero(t, purged)
+		purged, err = repo.Purge(time.Now().Add(time.Second), nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), purged)
+		trashed, err = repo.FindTrashed()
+		require.NoError(t, err)
+		assert.Empty(t, trashed)
+	})
+}
+
+func TestConformanceAuditLog(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos repositories) {
+		repo := repos.todos
+
+		parent := &domain.Todo{Title: "親"}
+		created := &domain.AuditEntry{Action: domain.AuditCreate, Actor: "alice", Changes: domain.TodoChanges(nil, *parent)}
+		require.NoError(t, repo.Create(parent, created))
+		assert.NotZero(t, created.ID)
+		assert.Equal(t, parent.ID, created.TodoID)
+		child := &domain.Todo{Title: "子", ParentID: &parent.ID}
+		require.NoError(t, repo.Create(child, nil))
+
+		before := *parent
+		parent.Done = true
+		require.NoError(t, repo.Update(parent, &domain.AuditEntry{Action: domain.AuditUpdate, Actor: "bob", Changes: domain.TodoChanges(&before, *parent)}))
+
+		// 競合して更新できなかった操作は記録しない
+		assert.ErrorIs(t, repo.Update(&before, &domain.AuditEntry{Action: domain.AuditUpdate, Actor: "carol"}), ErrVersionConflict)
+
+		// ゴミ箱への移動と復元は、一緒に移動したサブタスクの分も記録する
+		require.NoError(t, repo.Delete(parent, &domain.AuditEntry{Action: domain.AuditDelete, Actor: "alice"}))
+		require.NoError(t, repo.Restore(parent, &domain.AuditEntry{Action: domain.AuditRestore, Actor: "alice"}))
+
+		entries, err := repo.FindAuditLog(domain.AuditQuery{TodoID: &parent.ID})
+		require.NoError(t, err)
+		require.Len(t, entries, 4)
+		for i, expected := range []struct {
+			action  domain.AuditAction
+			actor   string
+			version uint
+		}{
+			{domain.AuditCreate, "alice", 1},
+			{domain.AuditUpdate, "bob", 2},
+			{domain.AuditDelete, "alice", 3},
+			{domain.AuditRestore, "alice", 4},
+		} {
+			assert.Equal(t, expected.action, entries[i].Action)
+			assert.Equal(t, expected.actor, entries[i].Actor)
+			assert.Equal(t, expected.version, entries[i].Version)
+		}
+		assert.Equal(t, map[string]string{"title": `null -> "親"`}, changeStrings(entries[0].Changes))
+		assert.Equal(t, map[string]string{"done": "false -> true"}, changeStrings(entries[1].Changes))
+		assert.Equal(t, "null", string(entries[2].Changes["deleted_at"].Before))
+		assert.Equal(t, "null", string(entries[3].Changes["deleted_at"].After))
+
+		subtaskEntries, err := repo.FindAuditLog(domain.AuditQuery{TodoID: &child.ID})
+		require.NoError(t, err)
+		if assert.Len(t, subtaskEntries, 2) {
+			assert.Equal(t, domain.AuditDelete, subtaskEntries[0].Action)
+			assert.Equal(t, uint(2), subtaskEntries[0].Version)
+			assert.Equal(t, domain.AuditRestore, subtaskEntries[1].Action)
+		}
+
+		// 利用者・件数・期間で絞り込む
+		all, err := repo.FindAuditLog(domain.AuditQuery{})
+		require.NoError(t, err)
+		assert.Len(t, all, 6)
+		byActor, err := repo.FindAuditLog(domain.AuditQuery{Actor: "bob"})
+		require.NoError(t, err)
+		assert.Equal(t, []uint{entries[1].ID}, auditIDs(byActor))
+		limited, err := repo.FindAuditLog(domain.AuditQuery{Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, auditIDs(all[:2]), auditIDs(limited))
+		since, until := entries[0].CreatedAt, entries[0].CreatedAt
+		ranged, err := repo.FindAuditLog(domain.AuditQuery{Since: &since})
+		require.NoError(t, err)
+		assert.Len(t, ranged, 6)
+		ranged, err = repo.FindAuditLog(domain.AuditQuery{Until: &until})
+		require.NoError(t, err)
+		assert.Empty(t, ranged)
+
+		// 完全に削除した後も監査ログは残る
+		require.NoError(t, repo.Delete(parent, nil))
+		_, err = repo.Purge(time.Now().Add(time.Second), &domain.AuditEntry{Action: domain.AuditPurge, Actor: domain.SystemActor})
+		require.NoError(t, err)
+		entries, err = repo.FindAuditLog(domain.AuditQuery{TodoID: &parent.ID})
+		require.NoError(t, err)
+		if assert.Len(t, entries, 5) {
+			assert.Equal(t, domain.AuditPurge, entries[4].Action)
+			assert.Equal(t, uint(5), entries[4].Version)
+		}
+	})
+}
+
+// changeStrings は変更内容を「変更前 -> 変更後」の文字列にする
+func changeStrings(changes map[string]domain.FieldChange) map[string]string {
+	result := make(map[string]string, len(changes))
+	for name, change := range changes {
+		result[name] = string(change.Before) + " -> " + string(change.After)
+	}
+	return result
+}
+
+// auditIDs は監査ログのIDを順に返す
+func auditIDs(entries []domain.AuditEntry) []uint {
+	ids := make([]uint, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.ID
+	}
+	return ids
+}
+
+func TestConformanceVersioning(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos repositories) {
+		repo := repos.todos
+
+		todo := &domain.Todo{Title: "報告書を書く"}
+		createTodos(t, repo, todo)
+		assert.Equal(t, uint(1), todo.Version)
+
+		// 同じバージョンを取得した2つの操作のうち、後から更新した側は失敗する
+		first, err := repo.FindByID(idString(todo.ID))
+		require.NoError(t, err)
+		second, err := repo.FindByID(idString(todo.ID))
+		require.NoError(t, err)
+
+		first.Title = "報告書を提出する"
+		require.NoError(t, repo.Update(first, nil))
+		assert.Equal(t, uint(2), first.Version)
+
+		second.Done = true
+		assert.ErrorIs(t, repo.Update(second, nil), ErrVersionConflict)
+		assert.Equal(t, uint(1), second.Version)
+		assert.ErrorIs(t, repo.Delete(second, nil), ErrVersionConflict)
+
+		stored, err := repo.FindByID(idString(todo.ID))
+		require.NoError(t, err)
+		require.NotNil(t, stored)
+		assert.Equal(t, "報告書を提出する", stored.Title)
+		assert.False(t, stored.Done)
+		assert.Equal(t, uint(2), stored.Version)
+
+		// タグの付け外しでもバージョンが進む
+		tag := &domain.Tag{Name: "仕事"}
+		require.NoError(t, repos.tags.Create(tag))
+		require.NoError(t, repos.tags.Attach(first, tag))
+		require.NoError(t, repos.tags.Detach(first, tag))
+		assert.Equal(t, uint(4), first.Version)
+		stored, err = repo.FindByID(idString(todo.ID))
+		require.NoError(t, err)
+		assert.Equal(t, uint(4), stored.Version)
+
+		assert.ErrorIs(t, repo.Update(&domain.Todo{ID: 999, Title: "存在しない", Version: 1}, nil), ErrVersionConflict)
+		require.NoError(t, repo.Delete(first, nil))
+		missing, err := repo.FindByID(idString(todo.ID))
+		require.NoError(t, err)
+		assert.Nil(t, missing)
+	})
+}
+
+func TestConformanceBatch(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos repositories) {
+		repo := repos.todos
+		existing := &domain.Todo{Title: "既存のタスク"}
+		createTodos(t, repo, existing)
+		errFailed := errors.New("失敗")
+
+		// fn がエラーを返した場合はすべての操作を取り消す
+		err := repo.Batch(func(tx TodoRepositoryInterface) error {
+			createTodos(t, tx, &domain.Todo{Title: "取り消すタスク"})
+			found, err := tx.FindByID(idString(existing.ID))
+			require.NoError(t, err)
+			found.Done = true
+			require.NoError(t, tx.Update(found, &domain.AuditEntry{Action: domain.AuditUpdate, Actor: "alice"}))
+			return errFailed
+		})
+		assert.ErrorIs(t, err, errFailed)
+		todos, err := repo.FindAll(domain.TodoQuery{})
+		require.NoError(t, err)
+		require.Len(t, todos, 1)
+		assert.False(t, todos[0].Done)
+		assert.Equal(t, uint(1), todos[0].Version)
+		audit, err := repo.FindAuditLog(domain.AuditQuery{})
+		require.NoError(t, err)
+		assert.Empty(t, audit)
+
+		// 入れ子の Batch で失敗した操作だけを取り消し、残りの操作はまとめて保存する
+		var created, skipped *domain.Todo
+		err = repo.Batch(func(tx TodoRepositoryInterface) error {
+			created = &domain.Todo{Title: "追加するタスク"}
+			require.NoError(t, tx.Create(created, &domain.AuditEntry{Action: domain.AuditCreate, Actor: "alice"}))
+
+			err := tx.Batch(func(sp TodoRepositoryInterface) error {
+				skipped = &domain.Todo{Title: "取り消すタスク"}
+				createTodos(t, sp, skipped)
+				return errFailed
+			})
+			assert.ErrorIs(t, err, errFailed)
+
+			found, err := tx.FindByID(idString(existing.ID))
+			require.NoError(t, err)
+			return tx.Delete(found, &domain.AuditEntry{Action: domain.AuditDelete, Actor: "bob"})
+		})
+		require.NoError(t, err)
+
+		todos, err = repo.FindAll(domain.TodoQuery{})
+		require.NoError(t, err)
+		assert.Equal(t, []uint{created.ID}, todoIDs(todos))
+		missing, err := repo.FindByID(idString(existing.ID))
+		require.NoError(t, err)
+		assert.Nil(t, missing)
+
+		audit, err = repo.FindAuditLog(domain.AuditQuery{})
+		require.NoError(t, err)
+		require.Len(t, audit, 2)
+		assert.Equal(t, []domain.AuditAction{domain.AuditCreate, domain.AuditDelete}, []domain.AuditAction{audit[0].Action, audit[1].Action})
+		assert.Equal(t, []uint{created.ID, existing.ID}, []uint{audit[0].TodoID, audit[1].TodoID})
+	})
+}
+
+func TestConformanceContext(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos repositories) {
+		existing := &domain.Todo{Title: "既存のタスク"}
+		createTodos(t, repos.todos, existing)
+
+		// 終了した ctx を設定したリポジトリは処理しない
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := repos.todos.WithContext(ctx).FindAll(domain.TodoQuery{})
+		assert.ErrorIs(t, err, context.Canceled)
+		err = repos.todos.WithContext(ctx).Create(&domain.Todo{Title: "作成しないタスク"}, nil)
+		assert.ErrorIs(t, err, context.Canceled)
+		_, err = repos.tags.WithContext(ctx).FindAll()
+		assert.ErrorIs(t, err, context.Canceled)
+
+		// Batch の途中で ctx が終了した場合はすべての操作を取り消す
+		ctx, cancel = context.WithCancel(context.Background())
+		err = repos.todos.WithContext(ctx).Batch(func(tx TodoRepositoryInterface) error {
+			createTodos(t, tx, &domain.Todo{Title: "取り消すタスク"})
+			cancel()
+			return nil
+		})
+		assert.Error(t, err)
+
+		// 元のリポジトリは ctx の影響を受けない
+		todos, err := repos.todos.FindAll(domain.TodoQuery{})
+		require.NoError(t, err)
+		assert.Equal(t, []uint{existing.ID}, todoIDs(todos))
+	})
+}
+
+func TestConformanceFilters(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos repositories) {
+		now := time.Date(2025, 4, 10, 12, 0, 0, 0, time.UTC)
+		past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+		parent := &domain.Todo{Title: "親", DueAt: &past}
+		createTodos(t, repos.todos, parent)
+		child := &domain.Todo{Title: "子", ParentID: &parent.ID, DueAt: &future}
+		doneChild := &domain.Todo{Title: "完了した子", ParentID: &parent.ID, DueAt: &past, Done: true}
+		plain := &domain.Todo{Title: "期限なし"}
+		createTodos(t, repos.todos, child, doneChild, plain)
+
+		// 時差の異なる期限も同じ時刻として比較する（東京 11:30Z は期限切れ、ロサンゼルス 12:45Z は期限内）
+		tokyoDue := time.Date(2025, 4, 10, 20, 30, 0, 0, time.FixedZone("JST", 9*60*60))
+		laDue := time.Date(2025, 4, 10, 5, 45, 0, 0, time.FixedZone("PDT", -7*60*60))
+		tokyo := &domain.Todo{Title: "東京の期限", DueAt: &tokyoDue}
+		la := &domain.Todo{Title: "ロサンゼルスの期限", DueAt: &laDue}
+		createTodos(t, repos.todos, tokyo, la)
+
+		work, home := &domain.Tag{Name: "仕事"}, &domain.Tag{Name: "家"}
+		require.NoError(t, repos.tags.Create(work))
+		require.NoError(t, repos.tags.Create(home))
+		require.NoError(t, repos.tags.Attach(parent, work))
+		require.NoError(t, repos.tags.Attach(parent, home))
+		require.NoError(t, repos.tags.Attach(child, work))
+
+		done := true
+		testCases := []struct {
+			name     string
+			query    domain.TodoQuery
+			expected []uint
+			total    int // Count の結果（カーソルは件数に影響しないため、0の場合は expected の件数）
+		}{
+			{name: "親タスク", query: domain.TodoQuery{ParentID: &parent.ID}, expected: []uint{child.ID, doneChild.ID}},
+			{name: "期限切れ", query: domain.TodoQuery{Overdue: true, Now: now}, expected: []uint{parent.ID, tokyo.ID}},
+			{name: "期限切れ（時差のある現在時刻）", query: domain.TodoQuery{Overdue: true, Now: now.In(time.FixedZone("JST", 9*60*60))}, expected: []uint{parent.ID, tokyo.ID}},
+			{name: "期限順", query: domain.TodoQuery{Sort: domain.SortByDue}, expected: []uint{parent.ID, doneChild.ID, tokyo.ID, la.ID, child.ID, plain.ID}},
+			{name: "期限順のカーソル", query: domain.TodoQuery{Sort: domain.SortByDue, After: &domain.Cursor{Sort: domain.SortByDue, ID: tokyo.ID, Key: tokyoDue.Format(time.RFC3339Nano)}}, expected: []uint{la.ID, child.ID, plain.ID}, total: 6},
+			{name: "完了状態", query: domain.TodoQuery{Done: &done}, expected: []uint{doneChild.ID}},
+			{name: "いずれかのタグ", query: domain.TodoQuery{Tags: []string{"仕事", "家"}, TagMatch: domain.TagMatchAny}, expected: []uint{parent.ID, child.ID}},
+			{name: "すべてのタグ", query: domain.TodoQuery{Tags: []string{"仕事", "家", "家"}, TagMatch: domain.TagMatchAll}, expected: []uint{parent.ID}},
+			{name: "存在しないタグ", query: domain.TodoQuery{Tags: []string{"趣味"}}, expected: []uint{}},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				todos, err := repos.todos.FindAll(tc.query)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, todoIDs(todos))
+
+				total := tc.total
+				if total == 0 {
+					total = len(tc.expected)
+				}
+				count, err := repos.todos.Count(tc.query)
+				require.NoError(t, err)
+				assert.Equal(t, int64(total), count)
+			})
+		}
+	})
+}
+
+func TestConformanceTags(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos repositories) {
+		work, home := &domain.Tag{Name: "仕事"}, &domain.Tag{Name: "家"}
+		require.NoError(t, repos.tags.Create(work))
+		require.NoError(t, repos.tags.Create(home))
+		assert.Error(t, repos.tags.Create(&domain.Tag{Name: "仕事"}), "タグ名は重複できない")
+
+		home.Name = "自宅"
+		require.NoError(t, repos.tags.Update(home))
+		tags, err := repos.tags.FindAll()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"仕事", "自宅"}, tagNames(tags))
+
+		found, err := repos.tags.FindByName("自宅")
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, home.ID, found.ID)
+		missing, err := repos.tags.FindByName("家")
+		require.NoError(t, err)
+		assert.Nil(t, missing)
+		missing, err = repos.tags.FindByID("999")
+		require.NoError(t, err)
+		assert.Nil(t, missing)
+
+		// 付与は何度行っても1つだけ
+		todo := &domain.Todo{Title: "掃除"}
+		createTodos(t, repos.todos, todo)
+		require.NoError(t, repos.tags.Attach(todo, work))
+		require.NoError(t, repos.tags.Attach(todo, work))
+		require.NoError(t, repos.tags.Attach(todo, home))
+		tagged, err := repos.todos.FindByID(idString(todo.ID))
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"仕事", "自宅"}, tagNames(tagged.Tags))
+
+		require.NoError(t, repos.tags.Detach(todo, work))
+		tagged, err = repos.todos.FindByID(idString(todo.ID))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"自宅"}, tagNames(tagged.Tags))
+
+		// タグを削除するとタスクからも外れる
+		require.NoError(t, repos.tags.Delete(home))
+		tagged, err = repos.todos.FindByID(idString(todo.ID))
+		require.NoError(t, err)
+		assert.Empty(t, tagged.Tags)
+		tags, err = repos.tags.FindAll()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"仕事"}, tagNames(tags))
+	})
+}
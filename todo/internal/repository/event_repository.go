@@ -0,0 +1,618 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+)
+
+// DefaultSnapshotInterval はスナップショットを作成する間隔（前回のスナップショットから記録したイベントの数）の既定値
+const DefaultSnapshotInterval = 100
+
+// eventType はイベントの種類
+type eventType string
+
+// イベントログに記録するイベントを定義
+const (
+	eventTodoCreated        eventType = "TodoCreated"        // Todoを作成した（todo に作成したTodo）
+	eventTitleChanged       eventType = "TitleChanged"       // タイトルを変更した
+	eventDescriptionChanged eventType = "DescriptionChanged" // 説明を変更した
+	eventCompleted          eventType = "Completed"          // 完了にした
+	eventReopened           eventType = "Reopened"           // 未完了に戻した
+	eventPriorityChanged    eventType = "PriorityChanged"    // 優先度を変更した
+	eventScheduleChanged    eventType = "ScheduleChanged"    // 開始日時・期限日時を変更した
+	eventRecurrenceChanged  eventType = "RecurrenceChanged"  // 繰り返しルールを変更した
+	eventParentChanged      eventType = "ParentChanged"      // 親タスクを変更した
+	eventTouched            eventType = "Touched"            // 内容を変えずに更新した（バージョンだけが進む）
+	eventDeleted            eventType = "Deleted"            // ゴミ箱に移動した（value にゴミ箱に移動した日時）
+	eventRestored           eventType = "Restored"           // ゴミ箱から元に戻した
+	eventPurged             eventType = "Purged"             // 完全に削除した
+	eventTagAttached        eventType = "TagAttached"        // Todoにタグを付与した
+	eventTagDetached        eventType = "TagDetached"        // Todoからタグを外した
+	eventTagCreated         eventType = "TagCreated"         // タグを作成した（tag に作成したタグ）
+	eventTagRenamed         eventType = "TagRenamed"         // タグの名前を変更した（tag に変更後のタグ）
+	eventTagDeleted         eventType = "TagDeleted"         // タグを削除した
+)
+
+// event はTodoまたはタグに対する1つの変更
+type event struct {
+	Type    eventType       `json:"type"`
+	TodoID  uint            `json:"todo_id,omitempty"`
+	TagID   uint            `json:"tag_id,omitempty"`
+	Version uint            `json:"version,omitempty"` // 変更後のTodoのバージョン（完全に削除した場合は削除前のバージョン）
+	Todo    *todoRecord     `json:"todo,omitempty"`
+	Tag     *domain.Tag     `json:"tag,omitempty"`
+	Value   json.RawMessage `json:"value,omitempty"` // 1つの項目を変更するイベントの変更後の値
+}
+
+// scheduleValue は ScheduleChanged の変更後の値
+type scheduleValue struct {
+	StartAt *time.Time `json:"start_at"`
+	DueAt   *time.Time `json:"due_at"`
+}
+
+// eventBatch はイベントログの1行（1回の変更で記録したイベント。Batch でまとめて行った変更はその配列を1行にする）
+// 監査ログは別に保存せず、操作と利用者をここに記録してイベントから作成する
+type eventBatch struct {
+	Seq    uint64             `json:"seq"`              // 記録した順の連番
+	At     time.Time          `json:"at"`               // 変更した日時
+	Actor  string             `json:"actor,omitempty"`  // 変更した利用者（監査ログを記録しない変更の場合は空）
+	Action domain.AuditAction `json:"action,omitempty"` // 監査ログに記録する操作（監査ログを記録しない変更の場合は空）
+	Events []event            `json:"events"`
+}
+
+// eventSnapshot はスナップショットファイルの内容（Seq までのイベントを適用した状態）
+type eventSnapshot struct {
+	Seq uint64    `json:"seq"`
+	At  time.Time `json:"at"`
+	fileSnapshot
+}
+
+// EventStore はTodoとTagの変更をイベントとして追記するファイル（イベントログ）に保存するストア
+// 起動時にスナップショットとそれ以降のイベントからメモリ上の状態（MemoryStore）を作り直す
+// イベントログは追記のみで書き換えないため、任意の時点の状態もイベントを再生して取得できる
+type EventStore struct {
+	*MemoryStore
+	path             string
+	snapshotInterval int // 前回のスナップショットからこの数のイベントを記録するとスナップショットを作成する（0以下の場合は作成しない）
+	log              *os.File
+	size             int64  // イベントログの書き込みが完了している部分の大きさ
+	seq              uint64 // 最後に記録した変更の連番
+	pending          int    // 前回のスナップショットから記録したイベントの数
+	unlock           func() error
+}
+
+// EventStoreOption はEventStoreの任意設定
+type EventStoreOption func(*EventStore)
+
+// WithSnapshotInterval はスナップショットを作成する間隔（イベントの数）を設定する
+// スナップショットがあれば、起動時にはそれより後のイベントだけを再生する
+func WithSnapshotInterval(n int) EventStoreOption {
+	return func(s *EventStore) {
+		s.snapshotInterval = n
+	}
+}
+
+// OpenEventStore は指定したイベントログを読み込んだEventStoreを作成する（ファイルがない場合は空の状態から始める）
+// スナップショットは <path>.snapshot に保存する。Close するまでロックファイル（<path>.lock）をロックする
+func OpenEventStore(path string, opts ...EventStoreOption) (*EventStore, error) {
+	if path == "" {
+		return nil, errors.New("イベントログのパスを指定してください")
+	}
+	unlock, err := lockFile(path + ".lock")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &EventStore{path: path, snapshotInterval: DefaultSnapshotInterval, unlock: unlock}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if err := s.load(); err != nil {
+		unlock()
+		return nil, err
+	}
+	s.persist = s.record
+	return s, nil
+}
+
+// Close はイベントログを閉じてロックファイルのロックを解除する
+func (s *EventStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return errors.Join(s.log.Close(), s.unlock())
+}
+
+// TodoRepository はストアのTodoを操作するリポジトリを返す
+func (s *EventStore) TodoRepository() TodoRepositoryInterface {
+	return &EventTodoRepository{MemoryTodoRepository: s.MemoryStore.TodoRepository().(*MemoryTodoRepository), events: s}
+}
+
+// snapshotPath はスナップショットファイルのパスを返す
+func (s *EventStore) snapshotPath() string {
+	return s.path + ".snapshot"
+}
+
+// load はスナップショットとそれより後のイベントから現在の状態を作成し、イベントログを追記できるように開く
+func (s *EventStore) load() error {
+	snapshot, err := readEventSnapshot(s.snapshotPath())
+	if err != nil {
+		return err
+	}
+	data := snapshot.storeData()
+	s.seq = snapshot.Seq
+
+	f, err := os.OpenFile(s.path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("%s を開けません: %w", s.path, err)
+	}
+	size, err := readEvents(f, func(batch eventBatch) (bool, error) {
+		if batch.Seq <= snapshot.Seq {
+			return true, nil
+		}
+		s.seq = batch.Seq
+		s.pending += len(batch.Events)
+		return true, applyBatch(&data, batch)
+	})
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("%s の形式が正しくありません: %w", s.path, err)
+	}
+	// 書き込み途中で終了した最後の行を取り除き、続けて追記できるようにする
+	if err := f.Truncate(size); err != nil {
+		f.Close()
+		return fmt.Errorf("%s を修復できません: %w", s.path, err)
+	}
+
+	s.MemoryStore = &MemoryStore{data: data}
+	s.log, s.size = f, size
+	return nil
+}
+
+// record は変更前と変更後のデータの差分をイベントとしてイベントログに追記する
+// Batch でまとめて行った変更は変更ごとの行を配列にして1行で追記し、途中までの変更だけが残らないようにする
+// 記録したイベントの数がスナップショットの間隔に達した場合は、変更後のデータのスナップショットも作成する
+func (s *EventStore) record(changes []storeChange) error {
+	var batches []eventBatch
+	for _, change := range changes {
+		events := diffEvents(change.before, change.after)
+		if len(events) == 0 {
+			continue
+		}
+		batch := eventBatch{Seq: s.seq + uint64(len(batches)) + 1, At: auditTime(), Events: events}
+		if added := change.after.audit[len(change.before.audit):]; len(added) > 0 {
+			// 監査ログと同じ日時にし、イベントから作成した監査ログが記録したものと一致するようにする
+			batch.At, batch.Actor, batch.Action = added[0].CreatedAt, added[0].Actor, added[0].Action
+		}
+		batches = append(batches, batch)
+	}
+	if len(batches) == 0 {
+		return nil
+	}
+
+	var line []byte
+	var err error
+	if len(batches) == 1 {
+		line, err = json.Marshal(batches[0])
+	} else {
+		line, err = json.Marshal(batches)
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.appendLine(append(line, '\n')); err != nil {
+		return fmt.Errorf("%s に保存できません: %w", s.path, err)
+	}
+	last := batches[len(batches)-1]
+	s.seq = last.Seq
+	for _, batch := range batches {
+		s.pending += len(batch.Events)
+	}
+
+	if s.snapshotInterval > 0 && s.pending >= s.snapshotInterval {
+		// イベントログには記録済みのため、スナップショットを作成できなくても変更は失わない（次の変更で作成し直す）
+		if err := s.saveSnapshot(changes[len(changes)-1].after, last); err == nil {
+			s.pending = 0
+		}
+	}
+	return nil
+}
+
+// appendLine はイベントログに1行を追記し、ディスクに書き込む
+// 書き込みに失敗した場合は途中まで書き込んだ内容を取り除く
+func (s *EventStore) appendLine(line []byte) error {
+	_, err := s.log.Write(line)
+	if err == nil {
+		err = s.log.Sync()
+	}
+	if err != nil {
+		s.log.Truncate(s.size)
+		return err
+	}
+	s.size += int64(len(line))
+	return nil
+}
+
+// saveSnapshot は batch までのイベントを適用したデータをスナップショットファイルに書き出す
+func (s *EventStore) saveSnapshot(d *storeData, batch eventBatch) error {
+	snapshot := eventSnapshot{Seq: batch.Seq, At: batch.At, fileSnapshot: newFileSnapshot(d)}
+	content, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.snapshotPath(), content)
+}
+
+// stateAt はイベントログを再生して、指定した日時の時点のデータを作成する
+// 指定した日時がスナップショットより後の場合は、スナップショットから再生する
+func (s *EventStore) stateAt(ctx context.Context, t time.Time) (storeData, error) {
+	snapshot, err := readEventSnapshot(s.snapshotPath())
+	if err != nil {
+		return storeData{}, err
+	}
+	if snapshot.At.After(t) {
+		snapshot = eventSnapshot{}
+	}
+	data := snapshot.storeData()
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		return storeData{}, fmt.Errorf("%s を読み込めません: %w", s.path, err)
+	}
+	defer f.Close()
+	_, err = readEvents(f, func(batch eventBatch) (bool, error) {
+		// イベントログが長い場合は再生に時間がかかるため、ctx が終了したら中断する
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		if batch.Seq <= snapshot.Seq {
+			return true, nil
+		}
+		if batch.At.After(t) {
+			return false, nil
+		}
+		return true, applyBatch(&data, batch)
+	})
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return storeData{}, ctxErr
+	}
+	if err != nil {
+		return storeData{}, fmt.Errorf("%s の形式が正しくありません: %w", s.path, err)
+	}
+	return data, nil
+}
+
+// readEventSnapshot はスナップショットファイルを読み込む（ファイルがない場合は空の状態を返す）
+func readEventSnapshot(path string) (eventSnapshot, error) {
+	var snapshot eventSnapshot
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return snapshot, nil
+	}
+	if err != nil {
+		return snapshot, fmt.Errorf("%s を読み込めません: %w", path, err)
+	}
+	if err := json.Unmarshal(content, &snapshot); err != nil {
+		return snapshot, fmt.Errorf("%s の形式が正しくありません: %w", path, err)
+	}
+	return snapshot, nil
+}
+
+// readEvents はイベントログを先頭から読み、1回の変更ごとに fn を呼び出す
+// fn が false かエラーを返した場合はそこで読み込みを終える。書き込み途中で終了した改行のない最後の行は読み飛ばす
+// 戻り値は読み込みを終えた位置（最後まで読んだ場合は書き込みが完了している部分の大きさ）
+func readEvents(r io.Reader, fn func(batch eventBatch) (bool, error)) (int64, error) {
+	reader := bufio.NewReader(r)
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return offset, nil
+		}
+		if err != nil {
+			return offset, err
+		}
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			var batches []eventBatch
+			if trimmed[0] == '[' {
+				err = json.Unmarshal(trimmed, &batches) // Batch でまとめて行った変更
+			} else {
+				batches = make([]eventBatch, 1)
+				err = json.Unmarshal(trimmed, &batches[0])
+			}
+			if err != nil {
+				return offset, fmt.Errorf("%d バイト目: %w", offset, err)
+			}
+			for _, batch := range batches {
+				if next, err := fn(batch); err != nil || !next {
+					return offset, err
+				}
+			}
+		}
+		offset += int64(len(line))
+	}
+}
+
+// diffEvents は変更前と変更後のデータの差分をイベントにする
+// 作成したタグ、TodoごとのID順の変更、削除したタグの順に並べる
+func diffEvents(before, after *storeData) []event {
+	var events []event
+	for _, id := range slices.Sorted(maps.Keys(after.tags)) {
+		tag := after.tags[id]
+		old, ok := before.tags[id]
+		switch {
+		case !ok:
+			events = append(events, event{Type: eventTagCreated, TagID: id, Tag: &tag})
+		case old != tag:
+			events = append(events, event{Type: eventTagRenamed, TagID: id, Tag: &tag})
+		}
+	}
+
+	ids := slices.Collect(maps.Keys(before.todos))
+	for id := range after.todos {
+		if _, ok := before.todos[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		old, existed := before.todos[id]
+		rec, exists := after.todos[id]
+		switch {
+		case !existed:
+			events = append(events, event{Type: eventTodoCreated, TodoID: id, Version: rec.Version, Todo: &rec})
+		case !exists:
+			events = append(events, event{Type: eventPurged, TodoID: id, Version: old.Version})
+		default:
+			events = append(events, todoEvents(old, rec)...)
+		}
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(before.tags)) {
+		if _, ok := after.tags[id]; !ok {
+			events = append(events, event{Type: eventTagDeleted, TagID: id})
+		}
+	}
+	return events
+}
+
+// todoEvents は保存されているTodoの変更をイベントにする（作成日時は変更しないため記録しない）
+func todoEvents(old, rec todoRecord) []event {
+	var events []event
+	add := func(typ eventType, value any) {
+		ev := event{Type: typ, TodoID: rec.ID, Version: rec.Version}
+		if value != nil {
+			ev.Value, _ = json.Marshal(value) // Todoの項目は常にJSONにできる
+		}
+		events = append(events, ev)
+	}
+
+	if old.Title != rec.Title {
+		add(eventTitleChanged, rec.Title)
+	}
+	if old.Description != rec.Description {
+		add(eventDescriptionChanged, rec.Description)
+	}
+	if old.Done != rec.Done {
+		if rec.Done {
+			add(eventCompleted, nil)
+		} else {
+			add(eventReopened, nil)
+		}
+	}
+	if old.Priority != rec.Priority {
+		add(eventPriorityChanged, rec.Priority)
+	}
+	if !equalTime(old.StartAt, rec.StartAt) || !equalTime(old.DueAt, rec.DueAt) {
+		add(eventScheduleChanged, scheduleValue{StartAt: rec.StartAt, DueAt: rec.DueAt})
+	}
+	if old.Recurrence != rec.Recurrence {
+		add(eventRecurrenceChanged, rec.Recurrence)
+	}
+	if !equalPtr(old.ParentID, rec.ParentID) {
+		add(eventParentChanged, rec.ParentID)
+	}
+	if !equalTime(old.DeletedAt, rec.DeletedAt) {
+		if rec.DeletedAt != nil {
+			add(eventDeleted, rec.DeletedAt)
+		} else {
+			add(eventRestored, nil)
+		}
+	}
+	for _, tagID := range rec.TagIDs {
+		if !slices.Contains(old.TagIDs, tagID) {
+			events = append(events, event{Type: eventTagAttached, TodoID: rec.ID, TagID: tagID, Version: rec.Version})
+		}
+	}
+	for _, tagID := range old.TagIDs {
+		if !slices.Contains(rec.TagIDs, tagID) {
+			events = append(events, event{Type: eventTagDetached, TodoID: rec.ID, TagID: tagID, Version: rec.Version})
+		}
+	}
+	if len(events) == 0 && old.Version != rec.Version {
+		add(eventTouched, nil)
+	}
+	return events
+}
+
+// equalTime は2つの日時が同じかどうかを判定する（どちらもnilの場合は同じとする）
+func equalTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// equalPtr は2つのポインタの指す値が同じかどうかを判定する（どちらもnilの場合は同じとする）
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// applyBatch は1回の変更で記録したイベントをデータに適用する
+// 操作が記録されている場合は、変更したTodoごとに変更前と変更後の差分から監査ログを作成する（MemoryStore が記録するものと同じ）
+func applyBatch(d *storeData, batch eventBatch) error {
+	var ids []uint
+	before := make(map[uint]todoRecord)
+	for _, ev := range batch.Events {
+		if ev.TodoID != 0 && !slices.Contains(ids, ev.TodoID) {
+			ids = append(ids, ev.TodoID)
+			if rec, ok := d.todos[ev.TodoID]; ok {
+				before[ev.TodoID] = rec
+			}
+		}
+		if err := applyEvent(d, ev); err != nil {
+			return fmt.Errorf("%d 番目の変更: %w", batch.Seq, err)
+		}
+	}
+	if batch.Action == "" {
+		return nil
+	}
+
+	slices.Sort(ids)
+	for _, id := range ids {
+		entry := domain.AuditEntry{Action: batch.Action, Actor: batch.Actor}
+		old, existed := before[id]
+		rec, exists := d.todos[id]
+		switch {
+		case !exists:
+			d.appendAudit(&entry, old.Todo, batch.At)
+		case !existed:
+			entry.Changes = domain.TodoChanges(nil, rec.Todo)
+			d.appendAudit(&entry, rec.Todo, batch.At)
+		default:
+			entry.Changes = domain.TodoChanges(&old.Todo, rec.Todo)
+			d.appendAudit(&entry, rec.Todo, batch.At)
+		}
+	}
+	return nil
+}
+
+// applyEvent は1つのイベントをデータに適用する
+// 保持している値は変更せず、置き換える（storeData と同じ）
+func applyEvent(d *storeData, ev event) error {
+	switch ev.Type {
+	case eventTagCreated, eventTagRenamed:
+		d.tags[ev.TagID] = *ev.Tag
+		d.nextTagID = max(d.nextTagID, ev.TagID)
+		return nil
+	case eventTagDeleted:
+		delete(d.tags, ev.TagID)
+		return nil
+	case eventTodoCreated:
+		d.todos[ev.TodoID] = *ev.Todo
+		d.nextTodoID = max(d.nextTodoID, ev.TodoID)
+		return nil
+	case eventPurged:
+		delete(d.todos, ev.TodoID)
+		return nil
+	}
+
+	rec, ok := d.todos[ev.TodoID]
+	if !ok {
+		return fmt.Errorf("%s: ID %d のTodoがありません", ev.Type, ev.TodoID)
+	}
+	rec.Version = ev.Version
+
+	var err error
+	switch ev.Type {
+	case eventTitleChanged:
+		err = json.Unmarshal(ev.Value, &rec.Title)
+	case eventDescriptionChanged:
+		err = json.Unmarshal(ev.Value, &rec.Description)
+	case eventCompleted:
+		rec.Done = true
+	case eventReopened:
+		rec.Done = false
+	case eventPriorityChanged:
+		err = json.Unmarshal(ev.Value, &rec.Priority)
+	case eventScheduleChanged:
+		var v scheduleValue
+		err = json.Unmarshal(ev.Value, &v)
+		rec.StartAt, rec.DueAt = v.StartAt, v.DueAt
+	case eventRecurrenceChanged:
+		err = json.Unmarshal(ev.Value, &rec.Recurrence)
+	case eventParentChanged:
+		rec.ParentID = nil // 保持している値を書き換えないよう、新しく割り当てる
+		err = json.Unmarshal(ev.Value, &rec.ParentID)
+	case eventDeleted:
+		rec.DeletedAt = nil
+		err = json.Unmarshal(ev.Value, &rec.DeletedAt)
+	case eventRestored:
+		rec.DeletedAt = nil
+	case eventTagAttached:
+		rec.TagIDs = append(slices.Clone(rec.TagIDs), ev.TagID)
+	case eventTagDetached:
+		rec.TagIDs = slices.DeleteFunc(slices.Clone(rec.TagIDs), func(id uint) bool { return id == ev.TagID })
+	case eventTouched:
+	default:
+		return fmt.Errorf("不明なイベントです: %s", ev.Type)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: 値の形式が正しくありません: %w", ev.Type, err)
+	}
+	d.todos[ev.TodoID] = rec
+	return nil
+}
+
+// EventTodoRepository はEventStoreのTodoのデータアクセスを担当する構造体
+// 現在の状態の取得と変更は MemoryTodoRepository と同じで、過去の時点の状態はイベントログを再生して取得する
+type EventTodoRepository struct {
+	*MemoryTodoRepository
+	events *EventStore
+}
+
+// FindAll は絞り込み条件に一致するTodoを取得するメソッド
+// query.AsOf を指定した場合は、その時点の状態から取得する
+func (r *EventTodoRepository) FindAll(query domain.TodoQuery) ([]domain.Todo, error) {
+	if query.AsOf == nil {
+		return r.MemoryTodoRepository.FindAll(query)
+	}
+	past, err := r.pastRepository(*query.AsOf)
+	if err != nil {
+		return nil, err
+	}
+	query.AsOf = nil
+	return past.FindAll(query)
+}
+
+// Count は絞り込み条件に一致するTodoの件数を取得するメソッド
+// query.AsOf を指定した場合は、その時点の状態から数える
+func (r *EventTodoRepository) Count(query domain.TodoQuery) (int64, error) {
+	if query.AsOf == nil {
+		return r.MemoryTodoRepository.Count(query)
+	}
+	past, err := r.pastRepository(*query.AsOf)
+	if err != nil {
+		return 0, err
+	}
+	query.AsOf = nil
+	return past.Count(query)
+}
+
+// pastRepository は指定した日時の時点の状態を保持する読み取り用のリポジトリを返す
+func (r *EventTodoRepository) pastRepository(t time.Time) (*MemoryTodoRepository, error) {
+	data, err := r.events.stateAt(r.ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	return &MemoryTodoRepository{store: &MemoryStore{data: data}, ctx: r.ctx}, nil
+}
+
+// WithContext は ctx が終了した時点で処理を中断するリポジトリを返すメソッド
+func (r *EventTodoRepository) WithContext(ctx context.Context) TodoRepositoryInterface {
+	return &EventTodoRepository{MemoryTodoRepository: &MemoryTodoRepository{store: r.store, ctx: ctx}, events: r.events}
+}
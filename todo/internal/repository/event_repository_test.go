@@ -0,0 +1,291 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storeState は開き直す前後で比較するストアの内容（日時の内部表現の違いを除くためJSONにする）
+func storeState(t *testing.T, store *EventStore) string {
+	todos, err := store.TodoRepository().FindAll(domain.TodoQuery{})
+	require.NoError(t, err)
+	trashed, err := store.TodoRepository().FindTrashed()
+	require.NoError(t, err)
+	tags, err := store.TagRepository().FindAll()
+	require.NoError(t, err)
+	audit, err := store.TodoRepository().FindAuditLog(domain.AuditQuery{})
+	require.NoError(t, err)
+
+	content, err := json.Marshal(map[string]any{"todos": todos, "trashed": trashed, "tags": tags, "audit": audit})
+	require.NoError(t, err)
+	return string(content)
+}
+
+// eventTypes はイベントログの各行のイベントの種類を返す
+func eventTypes(t *testing.T, path string) [][]eventType {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var types [][]eventType
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var batch eventBatch
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &batch))
+		var line []eventType
+		for _, ev := range batch.Events {
+			line = append(line, ev.Type)
+		}
+		types = append(types, line)
+	}
+	require.NoError(t, scanner.Err())
+	return types
+}
+
+func TestEventStoreReplay(t *testing.T) {
+	for _, interval := range []int{0, 4} {
+		t.Run("snapshot interval "+idString(uint(interval)), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "todo.events")
+			store, err := OpenEventStore(path, WithSnapshotInterval(interval))
+			require.NoError(t, err)
+			repo, tagRepo := store.TodoRepository(), store.TagRepository()
+
+			work := &domain.Tag{Name: "仕事"}
+			require.NoError(t, tagRepo.Create(work))
+			parent := &domain.Todo{Title: "資料を作る", Tags: []domain.Tag{*work, {Name: "急ぎ"}}}
+			require.NoError(t, repo.Create(parent, &domain.AuditEntry{Action: domain.AuditCreate, Actor: "alice", Changes: domain.TodoChanges(nil, *parent)}))
+			child := &domain.Todo{Title: "下書き", ParentID: &parent.ID}
+			createTodos(t, repo, child)
+
+			before := *parent
+			dueAt := time.Date(2025, 4, 1, 18, 0, 0, 0, time.UTC)
+			parent.Title, parent.Done, parent.Priority, parent.DueAt = "資料を仕上げる", true, domain.PriorityHigh, &dueAt
+			require.NoError(t, repo.Update(parent, &domain.AuditEntry{Action: domain.AuditUpdate, Actor: "bob", Changes: domain.TodoChanges(&before, *parent)}))
+			require.NoError(t, tagRepo.Detach(parent, work))
+			work.Name = "業務"
+			require.NoError(t, tagRepo.Update(work))
+			reopened := &domain.Todo{Title: "やり直す", Done: true, Recurrence: "FREQ=DAILY"}
+			createTodos(t, repo, reopened)
+			reopened.Done, reopened.Description, reopened.Recurrence = false, "手順を見直す", ""
+			require.NoError(t, repo.Update(reopened, nil))
+
+			require.NoError(t, repo.Delete(child, &domain.AuditEntry{Action: domain.AuditDelete, Actor: "alice"}))
+			require.NoError(t, repo.Restore(child, &domain.AuditEntry{Action: domain.AuditRestore, Actor: "alice"}))
+			require.NoError(t, repo.Delete(child, &domain.AuditEntry{Action: domain.AuditDelete, Actor: "alice"}))
+			_, err = repo.Purge(time.Now().Add(time.Second), &domain.AuditEntry{Action: domain.AuditPurge, Actor: domain.SystemActor})
+			require.NoError(t, err)
+			trashed := &domain.Todo{Title: "ゴミ箱に残す"}
+			createTodos(t, repo, trashed)
+			require.NoError(t, repo.Delete(trashed, nil))
+
+			expected := storeState(t, store)
+			require.NoError(t, store.Close())
+
+			// 開き直すとイベントから同じ状態と監査ログを作り直す
+			store, err = OpenEventStore(path, WithSnapshotInterval(interval))
+			require.NoError(t, err)
+			defer store.Close()
+			assert.JSONEq(t, expected, storeState(t, store))
+
+			_, err = os.Stat(path + ".snapshot")
+			assert.Equal(t, interval > 0, err == nil, "スナップショットは間隔を指定した場合のみ作成する")
+
+			// 削除したIDは再利用しない
+			next := &domain.Todo{Title: "次のタスク"}
+			createTodos(t, store.TodoRepository(), next)
+			assert.Equal(t, trashed.ID+1, next.ID)
+		})
+	}
+}
+
+func TestEventStoreEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.events")
+	store, err := OpenEventStore(path)
+	require.NoError(t, err)
+	defer store.Close()
+	repo := store.TodoRepository()
+
+	todo := &domain.Todo{Title: "買い物"}
+	createTodos(t, repo, todo)
+	todo.Done = true
+	require.NoError(t, repo.Update(todo, nil))
+	todo.Title, todo.Done = "牛乳を買う", false
+	require.NoError(t, repo.Update(todo, nil))
+	require.NoError(t, repo.Update(todo, nil))
+	require.NoError(t, repo.Delete(todo, nil))
+	_, err = repo.Purge(time.Now().Add(time.Second), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]eventType{
+		{eventTodoCreated},
+		{eventCompleted},
+		{eventTitleChanged, eventReopened},
+		{eventTouched},
+		{eventDeleted},
+		{eventPurged},
+	}, eventTypes(t, path))
+}
+
+func TestEventStoreBatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.events")
+	store, err := OpenEventStore(path, WithSnapshotInterval(0))
+	require.NoError(t, err)
+	repo := store.TodoRepository()
+
+	todo := &domain.Todo{Title: "買い物"}
+	createTodos(t, repo, todo)
+	err = repo.Batch(func(tx TodoRepositoryInterface) error {
+		require.NoError(t, tx.Create(&domain.Todo{Title: "掃除"}, &domain.AuditEntry{Action: domain.AuditCreate, Actor: "alice"}))
+		return tx.Batch(func(sp TodoRepositoryInterface) error {
+			createTodos(t, sp, &domain.Todo{Title: "取り消すタスク"})
+			return assert.AnError
+		})
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+	require.NoError(t, repo.Batch(func(tx TodoRepositoryInterface) error {
+		created := &domain.Todo{Title: "掃除"}
+		require.NoError(t, tx.Create(created, &domain.AuditEntry{Action: domain.AuditCreate, Actor: "alice", Changes: domain.TodoChanges(nil, *created)}))
+		before := *todo
+		todo.Done = true
+		return tx.Update(todo, &domain.AuditEntry{Action: domain.AuditUpdate, Actor: "bob", Changes: domain.TodoChanges(&before, *todo)})
+	}))
+	expected := storeState(t, store)
+	require.NoError(t, store.Close())
+
+	// まとめて行った変更は途中までの変更が残らないよう1行に記録する
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, bytes.Count(content, []byte("\n")))
+
+	// 開き直すと変更ごとの操作者で監査ログを作り直す
+	store, err = OpenEventStore(path)
+	require.NoError(t, err)
+	defer store.Close()
+	assert.JSONEq(t, expected, storeState(t, store))
+	audit, err := store.TodoRepository().FindAuditLog(domain.AuditQuery{})
+	require.NoError(t, err)
+	require.Len(t, audit, 2)
+	assert.Equal(t, []string{"alice", "bob"}, []string{audit[0].Actor, audit[1].Actor})
+}
+
+func TestEventStoreAsOf(t *testing.T) {
+	for _, interval := range []int{0, 1} {
+		t.Run("snapshot interval "+idString(uint(interval)), func(t *testing.T) {
+			store, err := OpenEventStore(filepath.Join(t.TempDir(), "todo.events"), WithSnapshotInterval(interval))
+			require.NoError(t, err)
+			defer store.Close()
+			repo := store.TodoRepository()
+
+			// 監査ログの日時はミリ秒に切り捨てるため、操作の間を空けて前後関係を確実にする
+			pause := func() time.Time {
+				time.Sleep(2 * time.Millisecond)
+				at := time.Now()
+				time.Sleep(2 * time.Millisecond)
+				return at
+			}
+
+			beforeCreate := pause()
+			todo := &domain.Todo{Title: "買い物"}
+			createTodos(t, repo, todo)
+			createTodos(t, repo, &domain.Todo{Title: "掃除"})
+			afterCreate := pause()
+			todo.Title, todo.Done = "牛乳を買う", true
+			require.NoError(t, repo.Update(todo, nil))
+			afterUpdate := pause()
+			require.NoError(t, repo.Delete(todo, nil))
+
+			done := true
+			for _, tc := range []struct {
+				name   string
+				asOf   time.Time
+				query  domain.TodoQuery
+				titles []string
+			}{
+				{name: "作成前", asOf: beforeCreate, titles: []string{}},
+				{name: "作成後", asOf: afterCreate, titles: []string{"買い物", "掃除"}},
+				{name: "更新後", asOf: afterUpdate, titles: []string{"牛乳を買う", "掃除"}},
+				{name: "絞り込み", asOf: afterUpdate, query: domain.TodoQuery{Done: &done}, titles: []string{"牛乳を買う"}},
+				{name: "削除後", asOf: time.Now(), titles: []string{"掃除"}},
+			} {
+				query := tc.query
+				query.AsOf = &tc.asOf
+				todos, err := repo.FindAll(query)
+				require.NoError(t, err, tc.name)
+				titles := []string{}
+				for _, todo := range todos {
+					titles = append(titles, todo.Title)
+				}
+				assert.Equal(t, tc.titles, titles, tc.name)
+
+				count, err := repo.Count(query)
+				require.NoError(t, err, tc.name)
+				assert.Equal(t, int64(len(tc.titles)), count, tc.name)
+			}
+
+			// 過去の状態を取得しても現在の状態は変わらない
+			count, err := repo.Count(domain.TodoQuery{})
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), count)
+		})
+	}
+}
+
+func TestAsOfNotSupported(t *testing.T) {
+	now := time.Now()
+	repo := NewMemoryStore().TodoRepository()
+
+	_, err := repo.FindAll(domain.TodoQuery{AsOf: &now})
+	assert.ErrorIs(t, err, ErrAsOfNotSupported)
+	_, err = repo.Count(domain.TodoQuery{AsOf: &now})
+	assert.ErrorIs(t, err, ErrAsOfNotSupported)
+}
+
+func TestEventStoreTornWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.events")
+	store, err := OpenEventStore(path)
+	require.NoError(t, err)
+	createTodos(t, store.TodoRepository(), &domain.Todo{Title: "保存済み"})
+	require.NoError(t, store.Close())
+
+	// 書き込み途中で終了した行は読み飛ばし、続けて追記できるように取り除く
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"seq":2,"at":"2025-04-01T`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	store, err = OpenEventStore(path)
+	require.NoError(t, err)
+	createTodos(t, store.TodoRepository(), &domain.Todo{Title: "追加"})
+	require.NoError(t, store.Close())
+
+	store, err = OpenEventStore(path)
+	require.NoError(t, err)
+	defer store.Close()
+	count, err := store.TodoRepository().Count(domain.TodoQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestOpenEventStoreInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.events")
+	require.NoError(t, os.WriteFile(path, []byte("{\n"), 0o644))
+
+	_, err := OpenEventStore(path)
+	assert.ErrorContains(t, err, "の形式が正しくありません")
+
+	// 読み込みに失敗した場合はロックを解除する
+	require.NoError(t, os.WriteFile(path, nil, 0o644))
+	store, err := OpenEventStore(path)
+	require.NoError(t, err)
+	store.Close()
+}
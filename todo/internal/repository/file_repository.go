@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+)
+
+// FileStore はTodoとTagをJSONファイルに保存するストア
+// データはメモリ上に保持し（MemoryStore）、変更のたびにファイル全体を書き出す
+// 書き込み途中で終了してもファイルが壊れないよう、一時ファイルに書き出してから置き換える
+type FileStore struct {
+	*MemoryStore
+	path   string
+	unlock func() error // ロックファイルのロックを解除する
+}
+
+// fileSnapshot はJSONファイルの内容
+type fileSnapshot struct {
+	NextTodoID  uint                `json:"next_todo_id"`
+	NextTagID   uint                `json:"next_tag_id"`
+	NextAuditID uint                `json:"next_audit_id,omitempty"`
+	Todos       []todoRecord        `json:"todos"`
+	Tags        []domain.Tag        `json:"tags"`
+	Audit       []domain.AuditEntry `json:"audit,omitempty"`
+}
+
+// OpenFileStore は指定したJSONファイルを読み込んだFileStoreを作成する（ファイルがない場合は空の状態から始める）
+// 同じファイルを複数のプロセスで同時に更新しないよう、Close するまでロックファイル（<path>.lock）をロックする
+func OpenFileStore(path string) (*FileStore, error) {
+	if path == "" {
+		return nil, errors.New("JSONファイルのパスを指定してください")
+	}
+	unlock, err := lockFile(path + ".lock")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := loadSnapshot(path)
+	if err != nil {
+		unlock()
+		return nil, err
+	}
+	s := &FileStore{MemoryStore: &MemoryStore{data: data}, path: path, unlock: unlock}
+	s.persist = func(changes []storeChange) error { return s.save(changes[len(changes)-1].after) }
+	return s, nil
+}
+
+// Close はロックファイルのロックを解除する
+// 変更は都度ファイルに書き出しているため、閉じる際に保存する必要はない
+func (s *FileStore) Close() error {
+	return s.unlock()
+}
+
+// loadSnapshot はJSONファイルを読み込む
+func loadSnapshot(path string) (storeData, error) {
+	data := newStoreData()
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return data, nil
+	}
+	if err != nil {
+		return data, fmt.Errorf("%s を読み込めません: %w", path, err)
+	}
+
+	var snapshot fileSnapshot
+	if err := json.Unmarshal(content, &snapshot); err != nil {
+		return data, fmt.Errorf("%s の形式が正しくありません: %w", path, err)
+	}
+	return snapshot.storeData(), nil
+}
+
+// storeData はJSONファイルの内容からストアのデータを作成する
+func (snapshot *fileSnapshot) storeData() storeData {
+	data := newStoreData()
+	data.nextTodoID, data.nextTagID, data.nextAuditID = snapshot.NextTodoID, snapshot.NextTagID, snapshot.NextAuditID
+	for _, rec := range snapshot.Todos {
+		data.todos[rec.ID] = rec
+		data.nextTodoID = max(data.nextTodoID, rec.ID)
+	}
+	for _, tag := range snapshot.Tags {
+		data.tags[tag.ID] = tag
+		data.nextTagID = max(data.nextTagID, tag.ID)
+	}
+	data.audit = snapshot.Audit
+	for _, entry := range snapshot.Audit {
+		data.nextAuditID = max(data.nextAuditID, entry.ID)
+	}
+	return data
+}
+
+// newFileSnapshot はストアのデータをJSONファイルに書き出す内容にする（TodoとTagはID順に並べる）
+func newFileSnapshot(d *storeData) fileSnapshot {
+	snapshot := fileSnapshot{
+		NextTodoID:  d.nextTodoID,
+		NextTagID:   d.nextTagID,
+		NextAuditID: d.nextAuditID,
+		Todos:       make([]todoRecord, 0, len(d.todos)),
+		Tags:        make([]domain.Tag, 0, len(d.tags)),
+		Audit:       d.audit,
+	}
+	for _, rec := range d.todos {
+		snapshot.Todos = append(snapshot.Todos, rec)
+	}
+	slices.SortFunc(snapshot.Todos, func(a, b todoRecord) int { return cmp.Compare(a.ID, b.ID) })
+	for _, tag := range d.tags {
+		snapshot.Tags = append(snapshot.Tags, tag)
+	}
+	slices.SortFunc(snapshot.Tags, func(a, b domain.Tag) int { return cmp.Compare(a.ID, b.ID) })
+	return snapshot
+}
+
+// save はデータをJSONファイルに書き出す
+// 同じディレクトリの一時ファイルに書き出してから名前を変更するため、ファイルは常に変更前か変更後の内容になる
+func (s *FileStore) save(d *storeData) error {
+	snapshot := newFileSnapshot(d)
+	content, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(s.path, content); err != nil {
+		return fmt.Errorf("%s に保存できません: %w", s.path, err)
+	}
+	return nil
+}
+
+// writeFileAtomic は一時ファイルに書き出してから名前を変更して、ファイルの内容を置き換える
+func writeFileAtomic(path string, content []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // 名前の変更に成功した場合は何もしない
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	// 名前を変更した後に電源が切れても内容が失われないよう、先にディスクに書き込む
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
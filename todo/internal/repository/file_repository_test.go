@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStorePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.json")
+
+	store, err := OpenFileStore(path)
+	require.NoError(t, err)
+	tag := &domain.Tag{Name: "仕事"}
+	require.NoError(t, store.TagRepository().Create(tag))
+	first := &domain.Todo{Title: "資料を作る", Priority: domain.PriorityHigh, Tags: []domain.Tag{*tag}}
+	second := &domain.Todo{Title: "削除するタスク"}
+	createTodos(t, store.TodoRepository(), first, second)
+	require.NoError(t, store.TodoRepository().Delete(second, nil))
+	require.NoError(t, store.Close())
+
+	// 一時ファイルを残さない
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.ElementsMatch(t, []string{"todo.json", "todo.json.lock"}, names)
+
+	// 開き直しても同じ内容を読み込み、削除したIDは再利用しない
+	store, err = OpenFileStore(path)
+	require.NoError(t, err)
+	defer store.Close()
+
+	todos, err := store.TodoRepository().FindAll(domain.TodoQuery{})
+	require.NoError(t, err)
+	if assert.Len(t, todos, 1) {
+		assert.Equal(t, "資料を作る", todos[0].Title)
+		assert.Equal(t, domain.PriorityHigh, todos[0].Priority)
+		assert.Equal(t, []string{"仕事"}, tagNames(todos[0].Tags))
+		assert.True(t, first.CreatedAt.Equal(todos[0].CreatedAt))
+	}
+	third := &domain.Todo{Title: "次のタスク"}
+	createTodos(t, store.TodoRepository(), third)
+	assert.Equal(t, second.ID+1, third.ID)
+}
+
+func TestFileStoreLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.json")
+
+	store, err := OpenFileStore(path)
+	require.NoError(t, err)
+
+	// 使用中のファイルは開けない
+	_, err = OpenFileStore(path)
+	assert.EqualError(t, err, path+".lock は別のプロセスが使用中です")
+
+	require.NoError(t, store.Close())
+	store, err = OpenFileStore(path)
+	require.NoError(t, err)
+	store.Close()
+}
+
+func TestFileStoreSaveFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "todo.json")
+
+	store, err := OpenFileStore(path)
+	require.NoError(t, err)
+	defer store.Close()
+	createTodos(t, store.TodoRepository(), &domain.Todo{Title: "保存済み"})
+
+	// 保存できない場合はエラーを返し、メモリ上の変更も取り消す
+	store.path = filepath.Join(dir, "missing", "todo.json")
+	assert.Error(t, store.TodoRepository().Create(&domain.Todo{Title: "保存できない"}, nil))
+
+	count, err := store.TodoRepository().Count(domain.TodoQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestOpenFileStoreInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o644))
+
+	_, err := OpenFileStore(path)
+	assert.ErrorContains(t, err, "の形式が正しくありません")
+
+	// 読み込みに失敗した場合はロックを解除する
+	require.NoError(t, os.WriteFile(path, []byte(`{"todos": []}`), 0o644))
+	store, err := OpenFileStore(path)
+	require.NoError(t, err)
+	store.Close()
+}
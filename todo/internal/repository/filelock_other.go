@@ -0,0 +1,27 @@
+//go:build !unix
+
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// lockFile はロックファイルを新規に作成してロックとし、ロックを解除する（ファイルを削除する）関数を返す
+// 異常終了した場合はロックファイルが残るため、他に使用しているプロセスがないことを確認してから削除する
+func lockFile(path string) (func() error, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, fs.ErrExist) {
+		return nil, fmt.Errorf("%s は別のプロセスが使用中です（使用中のプロセスがない場合は削除してください）", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ロックファイルを作成できません: %w", err)
+	}
+
+	return func() error {
+		f.Close()
+		return os.Remove(path)
+	}, nil
+}
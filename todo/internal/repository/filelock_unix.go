@@ -0,0 +1,31 @@
+//go:build unix
+
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// lockFile はロックファイルを排他ロックし、ロックを解除する関数を返す
+// ロックはプロセスが終了すると自動的に解除されるため、異常終了してもロックファイルが残って使えなくなることはない
+func lockFile(path string) (func() error, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("ロックファイルを作成できません: %w", err)
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, fmt.Errorf("%s は別のプロセスが使用中です", path)
+		}
+		return nil, fmt.Errorf("ロックファイルをロックできません: %w", err)
+	}
+
+	return func() error {
+		syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		return f.Close()
+	}, nil
+}
@@ -0,0 +1,883 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+)
+
+// MemoryStore はTodoとTagをメモリ上に保持するストア
+// GORMを使わないため、テストやデータを永続化しない軽量な構成で使う。複数のgoroutineから同時に使用できる
+type MemoryStore struct {
+	mu      sync.RWMutex
+	data    storeData
+	persist func(changes []storeChange) error // 変更のたびに呼び出す保存処理（FileStore・EventStore が設定する）
+}
+
+// storeChange は1回の変更の変更前と変更後のデータ
+// Batch でまとめて行った変更は、変更した順に複数渡す
+type storeChange struct {
+	before, after *storeData
+}
+
+// storeData はストアが保持するデータ
+// 値は更新のたびに置き換え、保持している値そのものは変更しない（保存に失敗した際に元に戻せるようにするため）
+type storeData struct {
+	nextTodoID  uint
+	nextTagID   uint
+	nextAuditID uint
+	todos       map[uint]todoRecord
+	tags        map[uint]domain.Tag
+	audit       []domain.AuditEntry // 監査ログ（記録した順。追加のみで変更しない）
+}
+
+// todoRecord はストアに保持するTodo（タグは付与したタグのIDとして持つ）
+type todoRecord struct {
+	domain.Todo
+	TagIDs []uint `json:"tag_ids,omitempty"`
+}
+
+// NewMemoryStore はMemoryStoreのコンストラクタ
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: newStoreData()}
+}
+
+// newStoreData は空のデータを作成する
+func newStoreData() storeData {
+	return storeData{todos: make(map[uint]todoRecord), tags: make(map[uint]domain.Tag)}
+}
+
+// TodoRepository はストアのTodoを操作するリポジトリを返す
+func (s *MemoryStore) TodoRepository() TodoRepositoryInterface {
+	return &MemoryTodoRepository{store: s, ctx: context.Background()}
+}
+
+// TagRepository はストアのTagを操作するリポジトリを返す
+func (s *MemoryStore) TagRepository() TagRepositoryInterface {
+	return &MemoryTagRepository{store: s, ctx: context.Background()}
+}
+
+// view は読み取りロックを取得して fn を実行する
+// ロックを待つ間に ctx が終了した場合は fn を実行せずに ctx のエラーを返す（update・batch も同じ）
+func (s *MemoryStore) view(ctx context.Context, fn func(d *storeData) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return fn(&s.data)
+}
+
+// update は書き込みロックを取得して fn でデータを変更し、保存処理があれば保存する
+// fn はエラーを返す場合はデータを変更しないこと。保存に失敗した場合は変更前のデータに戻す
+func (s *MemoryStore) update(ctx context.Context, fn func(d *storeData) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if s.persist == nil {
+		return fn(&s.data)
+	}
+	backup := s.data.clone()
+	if err := fn(&s.data); err != nil {
+		return err
+	}
+	if err := s.persist([]storeChange{{before: &backup, after: &s.data}}); err != nil {
+		s.data = backup
+		return err
+	}
+	return nil
+}
+
+// batch は書き込みロックを取得して、現在のデータを複製した作業用のストアを fn に渡す
+// fn が成功した場合のみ作業用のストアのデータに置き換え、その間の変更をまとめて1回で保存する
+// fn の実行中に ctx が終了した場合も、すべての変更を取り消す
+func (s *MemoryStore) batch(ctx context.Context, fn func(tx *MemoryStore) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var changes []storeChange
+	tx := &MemoryStore{data: s.data.clone()}
+	tx.persist = func(c []storeChange) error {
+		for _, change := range c {
+			after := change.after.clone() // 作業用のストアは続けて変更するため、変更した時点のデータを残す
+			changes = append(changes, storeChange{before: change.before, after: &after})
+		}
+		return nil
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	if s.persist != nil {
+		if err := s.persist(changes); err != nil {
+			return err
+		}
+	}
+	s.data = tx.data
+	return nil
+}
+
+// clone はデータの複製を作成する（保持している値は変更しないため、マップの複製だけでよい）
+func (d *storeData) clone() storeData {
+	c := *d
+	c.todos = make(map[uint]todoRecord, len(d.todos))
+	for id, rec := range d.todos {
+		c.todos[id] = rec
+	}
+	c.tags = make(map[uint]domain.Tag, len(d.tags))
+	for id, tag := range d.tags {
+		c.tags[id] = tag
+	}
+	c.audit = slices.Clip(d.audit) // 追加した際に元のデータと配列を共有しないようにする
+	return c
+}
+
+// appendAudit は監査ログを1件記録する（audit が nil の場合は何もしない）
+// 変更したTodoのIDとバージョン、記録した日時は todo と at から設定する（writeAudit と同じ）
+func (d *storeData) appendAudit(audit *domain.AuditEntry, todo domain.Todo, at time.Time) {
+	if audit == nil {
+		return
+	}
+	d.nextAuditID++
+	audit.ID, audit.TodoID, audit.Version, audit.CreatedAt = d.nextAuditID, todo.ID, todo.Version, at
+	d.audit = append(d.audit, *audit)
+}
+
+// appendAuditForIDs は指定したTodoのそれぞれについて、audit と同じ操作・変更内容の監査ログをID順に記録する
+// （writeAuditForIDs と同じ。audit が nil の場合は何もしない）
+func (d *storeData) appendAuditForIDs(audit *domain.AuditEntry, ids []uint, changes map[string]domain.FieldChange, at time.Time) {
+	if audit == nil {
+		return
+	}
+	sorted := slices.Sorted(slices.Values(ids))
+	for _, id := range sorted {
+		entry := *audit
+		entry.Changes = changes
+		d.appendAudit(&entry, d.todos[id].Todo, at)
+		if id == ids[0] {
+			*audit = entry
+		}
+	}
+}
+
+// todo は保持しているTodoをタグ付きで返す
+// 呼び出し元が変更してもストアに影響しないよう、ポインタの項目は複製する
+func (d *storeData) todo(rec todoRecord) domain.Todo {
+	todo := copyTodo(rec.Todo)
+	todo.Tags = make([]domain.Tag, 0, len(rec.TagIDs))
+	for _, id := range rec.TagIDs {
+		if tag, ok := d.tags[id]; ok {
+			todo.Tags = append(todo.Tags, tag)
+		}
+	}
+	return todo
+}
+
+// sortedTodos は保持しているTodoのうちゴミ箱にないものをID順に返す
+func (d *storeData) sortedTodos() []domain.Todo {
+	todos := make([]domain.Todo, 0, len(d.todos))
+	for _, rec := range d.todos {
+		if !rec.IsDeleted() {
+			todos = append(todos, d.todo(rec))
+		}
+	}
+	slices.SortFunc(todos, func(a, b domain.Todo) int { return cmp.Compare(a.ID, b.ID) })
+	return todos
+}
+
+// tagIDFor はTodoに付与するタグのIDを返す
+// 保持していないタグはGORMの関連の保存と同様に作成する
+func (d *storeData) tagIDFor(tag *domain.Tag) uint {
+	if tag.ID == 0 {
+		for _, existing := range d.tags {
+			if existing.Name == tag.Name {
+				tag.ID = existing.ID
+				return tag.ID
+			}
+		}
+		d.nextTagID++
+		tag.ID = d.nextTagID
+	}
+	if _, ok := d.tags[tag.ID]; !ok {
+		d.tags[tag.ID] = *tag
+		d.nextTagID = max(d.nextTagID, tag.ID)
+	}
+	return tag.ID
+}
+
+// copyTodo はポインタの項目を複製したTodoを返す（タグは含めない）
+func copyTodo(todo domain.Todo) domain.Todo {
+	todo.ParentID = copyPtr(todo.ParentID)
+	todo.StartAt = copyPtr(todo.StartAt)
+	todo.DueAt = copyPtr(todo.DueAt)
+	todo.DeletedAt = copyPtr(todo.DeletedAt)
+	todo.Tags = nil
+	return todo
+}
+
+// copyPtr はポインタの指す値を複製する
+func copyPtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+// parseID は文字列のIDを解析する（数値でない場合は存在しないIDとして扱う）
+func parseID(id string) (uint, bool) {
+	n, err := strconv.ParseUint(id, 10, 0)
+	return uint(n), err == nil
+}
+
+// MemoryTodoRepository はMemoryStoreのTodoのデータアクセスを担当する構造体
+type MemoryTodoRepository struct {
+	store *MemoryStore
+	ctx   context.Context // 終了した場合は処理を中断する（WithContext で設定する）
+}
+
+// FindAll は絞り込み条件に一致するTodoを取得するメソッド
+// 並び順とカーソルの扱いは TodoRepository（GORM）と同じ
+func (r *MemoryTodoRepository) FindAll(query domain.TodoQuery) ([]domain.Todo, error) {
+	if query.AsOf != nil {
+		return nil, ErrAsOfNotSupported
+	}
+	var todos []domain.Todo
+	err := r.store.view(r.ctx, func(d *storeData) error {
+		todos = filterTodos(d.sortedTodos(), query)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(todos, func(a, b domain.Todo) int { return compareTodos(a, b, query) })
+	if query.After != nil {
+		after, err := cursorTodo(*query.After)
+		if err != nil {
+			return nil, err
+		}
+		i, _ := slices.BinarySearchFunc(todos, after, func(todo, target domain.Todo) int {
+			if compareTodos(todo, target, query) <= 0 {
+				return -1
+			}
+			return 1
+		})
+		todos = todos[i:]
+	}
+	if query.Limit > 0 && len(todos) > query.Limit {
+		todos = todos[:query.Limit]
+	}
+	return todos, nil
+}
+
+// Count は絞り込み条件に一致するTodoの件数を取得するメソッド
+func (r *MemoryTodoRepository) Count(query domain.TodoQuery) (int64, error) {
+	if query.AsOf != nil {
+		return 0, ErrAsOfNotSupported
+	}
+	var count int64
+	err := r.store.view(r.ctx, func(d *storeData) error {
+		count = int64(len(filterTodos(d.sortedTodos(), query)))
+		return nil
+	})
+	return count, err
+}
+
+// FindByID は指定されたIDのTodoを取得するメソッド（ゴミ箱のTodoは見つからないものとして扱う）
+func (r *MemoryTodoRepository) FindByID(id string) (*domain.Todo, error) {
+	return r.find(id, false)
+}
+
+// find は指定されたIDのTodoを、trashed が true の場合はゴミ箱から、false の場合はゴミ箱以外から取得する
+func (r *MemoryTodoRepository) find(id string, trashed bool) (*domain.Todo, error) {
+	n, ok := parseID(id)
+	if !ok {
+		return nil, nil
+	}
+
+	var todo *domain.Todo
+	err := r.store.view(r.ctx, func(d *storeData) error {
+		if rec, ok := d.todos[n]; ok && rec.IsDeleted() == trashed {
+			t := d.todo(rec)
+			todo = &t
+		}
+		return nil
+	})
+	return todo, err
+}
+
+// Create は新しいTodoを作成するメソッド
+// ID・作成日時・バージョンが未設定の場合は設定する。付与されたタグも関連として保存する
+// audit を指定した場合は、作成したTodoのIDを設定した監査ログも記録する
+func (r *MemoryTodoRepository) Create(todo *domain.Todo, audit *domain.AuditEntry) error {
+	return r.store.update(r.ctx, func(d *storeData) error {
+		if todo.ID == 0 {
+			d.nextTodoID++
+			todo.ID = d.nextTodoID
+		} else if _, ok := d.todos[todo.ID]; ok {
+			return fmt.Errorf("ID %d のTodoは既に存在します", todo.ID)
+		}
+		d.nextTodoID = max(d.nextTodoID, todo.ID)
+		if todo.CreatedAt.IsZero() {
+			todo.CreatedAt = time.Now()
+		}
+		if todo.Version == 0 {
+			todo.Version = 1
+		}
+
+		rec := todoRecord{Todo: copyTodo(*todo)}
+		for i := range todo.Tags {
+			rec.TagIDs = append(rec.TagIDs, d.tagIDFor(&todo.Tags[i]))
+		}
+		d.todos[todo.ID] = rec
+		d.appendAudit(audit, *todo, auditTime())
+		return nil
+	})
+}
+
+// Update は指定されたTodoを更新するメソッド
+// 保存されているバージョンが todo.Version と一致する場合のみ更新してバージョンを1つ進め、一致しない場合（ゴミ箱にある場合を含む）は ErrVersionConflict を返す
+// タグの付け外しは TagRepository で行うため、関連は更新しない。audit を指定した場合は監査ログも記録する
+func (r *MemoryTodoRepository) Update(todo *domain.Todo, audit *domain.AuditEntry) error {
+	err := r.store.update(r.ctx, func(d *storeData) error {
+		rec, ok := d.todos[todo.ID]
+		if !ok || rec.IsDeleted() || rec.Version != todo.Version {
+			return ErrVersionConflict
+		}
+		updated := copyTodo(*todo)
+		updated.Version++
+		d.todos[todo.ID] = todoRecord{Todo: updated, TagIDs: rec.TagIDs}
+		d.appendAudit(audit, updated, auditTime())
+		return nil
+	})
+	if err == nil {
+		todo.Version++
+	}
+	return err
+}
+
+// Delete は指定されたTodoをゴミ箱に移動するメソッド（論理削除）
+// サブタスク（孫以降を含む）も同じ日時でゴミ箱に移動し、バージョンを1つ進める。タグとの関連は復元に備えて残す
+// 指定されたTodoのバージョンが保存されているバージョンと異なる場合は何も変更せずに ErrVersionConflict を返す
+// audit を指定した場合は、ゴミ箱に移動したTodoごとに監査ログを記録する
+func (r *MemoryTodoRepository) Delete(todo *domain.Todo, audit *domain.AuditEntry) error {
+	deletedAt := time.Now().UTC().Truncate(time.Millisecond)
+	err := r.store.update(r.ctx, func(d *storeData) error {
+		if rec, ok := d.todos[todo.ID]; !ok || rec.IsDeleted() || rec.Version != todo.Version {
+			return ErrVersionConflict
+		}
+		ids := d.subtree(todo.ID, nil)
+		for _, id := range ids {
+			rec := d.todos[id]
+			rec.DeletedAt = &deletedAt
+			rec.Version++
+			d.todos[id] = rec
+		}
+		d.appendAuditForIDs(audit, ids, deletedAtChanges(nil, &deletedAt), deletedAt)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	todo.DeletedAt = &deletedAt
+	todo.Version++
+	return nil
+}
+
+// FindTrashed はゴミ箱のTodoを、ゴミ箱に移動した日時の新しい順に取得するメソッド
+func (r *MemoryTodoRepository) FindTrashed() ([]domain.Todo, error) {
+	var todos []domain.Todo
+	err := r.store.view(r.ctx, func(d *storeData) error {
+		for _, rec := range d.todos {
+			if rec.IsDeleted() {
+				todos = append(todos, d.todo(rec))
+			}
+		}
+		return nil
+	})
+	slices.SortFunc(todos, func(a, b domain.Todo) int {
+		return cmp.Or(b.DeletedAt.Compare(*a.DeletedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return todos, err
+}
+
+// FindTrashedByID はゴミ箱にある指定されたIDのTodoを取得するメソッド
+func (r *MemoryTodoRepository) FindTrashedByID(id string) (*domain.Todo, error) {
+	return r.find(id, true)
+}
+
+// Restore はゴミ箱のTodoを元に戻すメソッド
+// 一緒にゴミ箱に移動したサブタスク（ゴミ箱に移動した日時が同じもの）も元に戻し、バージョンを1つ進める
+// 指定されたTodoのバージョンが保存されているバージョンと異なる場合は何も変更せずに ErrVersionConflict を返す
+// audit を指定した場合は、元に戻したTodoごとに監査ログを記録する
+func (r *MemoryTodoRepository) Restore(todo *domain.Todo, audit *domain.AuditEntry) error {
+	err := r.store.update(r.ctx, func(d *storeData) error {
+		rec, ok := d.todos[todo.ID]
+		if !ok || !rec.IsDeleted() || rec.Version != todo.Version {
+			return ErrVersionConflict
+		}
+		ids := d.subtree(todo.ID, rec.DeletedAt)
+		for _, id := range ids {
+			rec := d.todos[id]
+			rec.DeletedAt = nil
+			rec.Version++
+			d.todos[id] = rec
+		}
+		d.appendAuditForIDs(audit, ids, deletedAtChanges(rec.DeletedAt, nil), auditTime())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	todo.DeletedAt = nil
+	todo.Version++
+	return nil
+}
+
+// Purge はゴミ箱に移動した日時が before より前のTodoを完全に削除し、削除した件数を返すメソッド
+// audit を指定した場合は、削除したTodoごとに監査ログを記録する
+func (r *MemoryTodoRepository) Purge(before time.Time, audit *domain.AuditEntry) (int64, error) {
+	var purged int64
+	err := r.store.update(r.ctx, func(d *storeData) error {
+		var ids []uint
+		for id, rec := range d.todos {
+			if rec.IsDeleted() && rec.DeletedAt.Before(before) {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		d.appendAuditForIDs(audit, ids, nil, auditTime())
+		for _, id := range ids {
+			delete(d.todos, id)
+		}
+		purged = int64(len(ids))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
+
+// Batch は fn に渡したリポジトリでの操作をまとめて実行するメソッド
+// fn がエラーを返した場合はすべての操作を取り消す。渡されたリポジトリの Batch は、その中の操作だけを取り消せる（TodoRepository のセーブポイントと同じ）
+func (r *MemoryTodoRepository) Batch(fn func(repo TodoRepositoryInterface) error) error {
+	return r.store.batch(r.ctx, func(tx *MemoryStore) error {
+		return fn(&MemoryTodoRepository{store: tx, ctx: r.ctx})
+	})
+}
+
+// WithContext は ctx が終了した時点で処理を中断するリポジトリを返すメソッド
+// 元のリポジトリは変更しないため、リクエストごとに ctx を設定して使える
+func (r *MemoryTodoRepository) WithContext(ctx context.Context) TodoRepositoryInterface {
+	return &MemoryTodoRepository{store: r.store, ctx: ctx}
+}
+
+// subtree は指定したTodoとその子孫にあたるTodoのIDを返す（descendantIDs と同じ条件）
+// deletedAt が nil の場合はゴミ箱にない子孫を、nil以外の場合はその日時にゴミ箱に移動した子孫を対象とする
+func (d *storeData) subtree(id uint, deletedAt *time.Time) []uint {
+	ids := []uint{id}
+	for i := 0; i < len(ids); i++ {
+		for childID, rec := range d.todos {
+			if rec.ParentID == nil || *rec.ParentID != ids[i] {
+				continue
+			}
+			if deletedAt == nil && !rec.IsDeleted() || deletedAt != nil && rec.IsDeleted() && rec.DeletedAt.Equal(*deletedAt) {
+				ids = append(ids, childID)
+			}
+		}
+	}
+	return ids
+}
+
+// FindAuditLog は条件に一致する監査ログを記録した順に取得するメソッド
+func (r *MemoryTodoRepository) FindAuditLog(query domain.AuditQuery) ([]domain.AuditEntry, error) {
+	entries := []domain.AuditEntry{}
+	err := r.store.view(r.ctx, func(d *storeData) error {
+		for _, entry := range d.audit {
+			if query.Limit > 0 && len(entries) >= query.Limit {
+				break
+			}
+			if matchesAuditQuery(entry, query) {
+				entries = append(entries, entry)
+			}
+		}
+		return nil
+	})
+	return entries, err
+}
+
+// matchesAuditQuery は監査ログが絞り込み条件に一致するかどうかを判定する（FindAuditLog の条件と同じ）
+func matchesAuditQuery(entry domain.AuditEntry, query domain.AuditQuery) bool {
+	if query.TodoID != nil && entry.TodoID != *query.TodoID {
+		return false
+	}
+	if query.Actor != "" && entry.Actor != query.Actor {
+		return false
+	}
+	if query.Since != nil && entry.CreatedAt.Before(*query.Since) {
+		return false
+	}
+	if query.Until != nil && !entry.CreatedAt.Before(*query.Until) {
+		return false
+	}
+	return true
+}
+
+// Search はタイトルと説明をキーワードで検索し、最大 limit 件を返すメソッド
+// 関連度は計算できないため、TodoRepository の部分一致での検索と同じく、タイトルに一致したものを先にして新しい順に並べる
+func (r *MemoryTodoRepository) Search(text string, limit int) ([]domain.SearchResult, error) {
+	terms := strings.Fields(text)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	var todos []domain.Todo
+	err := r.store.view(r.ctx, func(d *storeData) error {
+		for _, todo := range d.sortedTodos() {
+			if containsAllTerms(todo, terms) {
+				todos = append(todos, todo)
+			}
+		}
+		return nil
+	})
+	if err != nil || len(todos) == 0 {
+		return nil, err
+	}
+
+	slices.Reverse(todos)
+	if limit > 0 && len(todos) > limit {
+		todos = todos[:limit]
+	}
+	rows := make([]searchRow, len(todos))
+	byID := make(map[uint]domain.Todo, len(todos))
+	for i, todo := range todos {
+		rows[i] = searchRow{ID: todo.ID, Title: todo.Title, Description: todo.Description}
+		byID[todo.ID] = todo
+	}
+	rankLikeRows(rows, terms)
+
+	results := make([]domain.SearchResult, len(rows))
+	for i, row := range rows {
+		results[i] = domain.SearchResult{Todo: byID[row.ID], Rank: row.Rank, Title: row.Title, Snippet: row.Description}
+	}
+	return results, nil
+}
+
+// filterTodos は絞り込み条件に一致するTodoを返す
+func filterTodos(todos []domain.Todo, query domain.TodoQuery) []domain.Todo {
+	filtered := make([]domain.Todo, 0, len(todos))
+	for _, todo := range todos {
+		if matchesQuery(todo, query) {
+			filtered = append(filtered, todo)
+		}
+	}
+	return filtered
+}
+
+// matchesQuery はTodoが絞り込み条件に一致するかどうかを判定する（applyFilters と同じ条件）
+func matchesQuery(todo domain.Todo, query domain.TodoQuery) bool {
+	if query.ParentID != nil && (todo.ParentID == nil || *todo.ParentID != *query.ParentID) {
+		return false
+	}
+	if query.Overdue && !todo.IsOverdue(query.Now) {
+		return false
+	}
+	if len(query.Tags) > 0 && !hasTags(todo, query.Tags, query.TagMatch) {
+		return false
+	}
+	if query.Done != nil && todo.Done != *query.Done {
+		return false
+	}
+	if query.Search != "" && !containsAllTerms(todo, []string{query.Search}) {
+		return false
+	}
+	return true
+}
+
+// hasTags はTodoに指定したタグが付いているかどうかを判定する
+// TagMatchAll の場合はすべてのタグが付いている場合のみ一致とする
+func hasTags(todo domain.Todo, names []string, match domain.TagMatch) bool {
+	wanted := uniqueStrings(names)
+	found := 0
+	for _, name := range wanted {
+		if slices.ContainsFunc(todo.Tags, func(tag domain.Tag) bool { return tag.Name == name }) {
+			found++
+		}
+	}
+	if match == domain.TagMatchAll {
+		return found == len(wanted)
+	}
+	return found > 0
+}
+
+// containsAllTerms はすべてのキーワードがタイトルか説明に含まれるかどうかを判定する
+func containsAllTerms(todo domain.Todo, terms []string) bool {
+	for _, term := range terms {
+		if !containsAny(todo.Title, []string{term}) && !containsAny(todo.Description, []string{term}) {
+			return false
+		}
+	}
+	return true
+}
+
+// compareTodos は applyOrder と同じ並び順でTodoを比較する
+func compareTodos(a, b domain.Todo, query domain.TodoQuery) int {
+	if query.Sort == domain.SortByDue {
+		// 期限未設定のタスクは並び順に関わらず末尾に置く
+		if c := compareBool(a.DueAt == nil, b.DueAt == nil); c != 0 {
+			return c
+		}
+	}
+
+	var c int
+	switch query.Sort {
+	case domain.SortByPriority:
+		c = cmp.Compare(a.Priority, b.Priority)
+	case domain.SortByCreated:
+		c = a.CreatedAt.Compare(b.CreatedAt)
+	case domain.SortByDue:
+		if a.DueAt != nil && b.DueAt != nil {
+			c = a.DueAt.Compare(*b.DueAt)
+		}
+	case domain.SortByTitle:
+		c = strings.Compare(a.Title, b.Title)
+	}
+	if c == 0 {
+		c = cmp.Compare(a.ID, b.ID)
+	}
+	if query.Desc {
+		return -c
+	}
+	return c
+}
+
+// compareBool は false を true より前として比較する
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// cursorTodo はカーソルが指す位置を、並び替え項目の値だけを持つTodoとして返す
+func cursorTodo(c domain.Cursor) (domain.Todo, error) {
+	value, err := c.SortValue()
+	if err != nil {
+		return domain.Todo{}, err
+	}
+
+	todo := domain.Todo{ID: c.ID}
+	switch v := value.(type) {
+	case int:
+		todo.Priority = domain.Priority(v)
+	case time.Time:
+		if c.Sort == domain.SortByDue {
+			todo.DueAt = &v
+		} else {
+			todo.CreatedAt = v
+		}
+	case string:
+		todo.Title = v
+	}
+	return todo, nil
+}
+
+// MemoryTagRepository はMemoryStoreのTagとタスクへの付与状態のデータアクセスを担当する構造体
+type MemoryTagRepository struct {
+	store *MemoryStore
+	ctx   context.Context // 終了した場合は処理を中断する（WithContext で設定する）
+}
+
+// FindAll はすべてのTagを名前順に取得するメソッド
+func (r *MemoryTagRepository) FindAll() ([]domain.Tag, error) {
+	var tags []domain.Tag
+	err := r.store.view(r.ctx, func(d *storeData) error {
+		tags = make([]domain.Tag, 0, len(d.tags))
+		for _, tag := range d.tags {
+			tags = append(tags, tag)
+		}
+		return nil
+	})
+	slices.SortFunc(tags, func(a, b domain.Tag) int {
+		return cmp.Or(strings.Compare(a.Name, b.Name), cmp.Compare(a.ID, b.ID))
+	})
+	return tags, err
+}
+
+// FindByID は指定されたIDのTagを取得するメソッド
+func (r *MemoryTagRepository) FindByID(id string) (*domain.Tag, error) {
+	n, ok := parseID(id)
+	if !ok {
+		return nil, nil
+	}
+
+	var tag *domain.Tag
+	err := r.store.view(r.ctx, func(d *storeData) error {
+		if t, ok := d.tags[n]; ok {
+			tag = &t
+		}
+		return nil
+	})
+	return tag, err
+}
+
+// FindByName は指定された名前のTagを取得するメソッド
+func (r *MemoryTagRepository) FindByName(name string) (*domain.Tag, error) {
+	var tag *domain.Tag
+	err := r.store.view(r.ctx, func(d *storeData) error {
+		for _, t := range d.tags {
+			if t.Name == name {
+				tag = &t
+				break
+			}
+		}
+		return nil
+	})
+	return tag, err
+}
+
+// Create は新しいTagを作成するメソッド
+func (r *MemoryTagRepository) Create(tag *domain.Tag) error {
+	return r.store.update(r.ctx, func(d *storeData) error {
+		if err := checkTagName(d, tag); err != nil {
+			return err
+		}
+		if tag.ID == 0 {
+			d.nextTagID++
+			tag.ID = d.nextTagID
+		} else if _, ok := d.tags[tag.ID]; ok {
+			return fmt.Errorf("ID %d のタグは既に存在します", tag.ID)
+		}
+		d.nextTagID = max(d.nextTagID, tag.ID)
+		d.tags[tag.ID] = *tag
+		return nil
+	})
+}
+
+// Update は指定されたTagを更新するメソッド
+func (r *MemoryTagRepository) Update(tag *domain.Tag) error {
+	return r.store.update(r.ctx, func(d *storeData) error {
+		if _, ok := d.tags[tag.ID]; !ok {
+			return fmt.Errorf("ID %d のタグが見つかりません", tag.ID)
+		}
+		if err := checkTagName(d, tag); err != nil {
+			return err
+		}
+		d.tags[tag.ID] = *tag
+		return nil
+	})
+}
+
+// Delete は指定されたTagを削除するメソッド
+// タスクとの関連も削除する
+func (r *MemoryTagRepository) Delete(tag *domain.Tag) error {
+	return r.store.update(r.ctx, func(d *storeData) error {
+		delete(d.tags, tag.ID)
+		for id, rec := range d.todos {
+			if slices.Contains(rec.TagIDs, tag.ID) {
+				rec.TagIDs = slices.DeleteFunc(slices.Clone(rec.TagIDs), func(tagID uint) bool { return tagID == tag.ID })
+				d.todos[id] = rec
+			}
+		}
+		return nil
+	})
+}
+
+// Attach はTodoにTagを付与するメソッド
+// 既に付与されている場合は関連を追加しない。Todoの内容が変わるため、バージョンは1つ進める
+func (r *MemoryTagRepository) Attach(todo *domain.Todo, tag *domain.Tag) error {
+	err := r.store.update(r.ctx, func(d *storeData) error {
+		rec, err := findTodoAndTag(d, todo, tag)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(rec.TagIDs, tag.ID) {
+			rec.TagIDs = append(slices.Clone(rec.TagIDs), tag.ID)
+		}
+		rec.Version++
+		d.todos[todo.ID] = rec
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(todo.Tags, func(t domain.Tag) bool { return t.ID == tag.ID }) {
+		todo.Tags = append(todo.Tags, *tag)
+	}
+	todo.Version++
+	return nil
+}
+
+// Detach はTodoからTagを外すメソッド
+// Todoの内容が変わるため、バージョンは1つ進める
+func (r *MemoryTagRepository) Detach(todo *domain.Todo, tag *domain.Tag) error {
+	err := r.store.update(r.ctx, func(d *storeData) error {
+		rec, err := findTodoAndTag(d, todo, tag)
+		if err != nil {
+			return err
+		}
+		rec.TagIDs = slices.DeleteFunc(slices.Clone(rec.TagIDs), func(id uint) bool { return id == tag.ID })
+		rec.Version++
+		d.todos[todo.ID] = rec
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	todo.Tags = slices.DeleteFunc(todo.Tags, func(t domain.Tag) bool { return t.ID == tag.ID })
+	todo.Version++
+	return nil
+}
+
+// WithContext は ctx が終了した時点で処理を中断するリポジトリを返すメソッド
+func (r *MemoryTagRepository) WithContext(ctx context.Context) TagRepositoryInterface {
+	return &MemoryTagRepository{store: r.store, ctx: ctx}
+}
+
+// checkTagName はタグ名が他のタグと重複していないことを確認する
+func checkTagName(d *storeData, tag *domain.Tag) error {
+	for _, existing := range d.tags {
+		if existing.Name == tag.Name && existing.ID != tag.ID {
+			return fmt.Errorf("タグ「%s」は既に存在します", tag.Name)
+		}
+	}
+	return nil
+}
+
+// findTodoAndTag はタグを付け外しするTodoとTagが存在することを確認する
+func findTodoAndTag(d *storeData, todo *domain.Todo, tag *domain.Tag) (todoRecord, error) {
+	rec, ok := d.todos[todo.ID]
+	if !ok {
+		return todoRecord{}, fmt.Errorf("ID %d のTodoが見つかりません", todo.ID)
+	}
+	if _, ok := d.tags[tag.ID]; !ok {
+		return todoRecord{}, fmt.Errorf("ID %d のタグが見つかりません", tag.ID)
+	}
+	return rec, nil
+}
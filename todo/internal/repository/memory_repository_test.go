@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStoreConcurrentAccess(t *testing.T) {
+	store := NewMemoryStore()
+	repo := store.TodoRepository()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			todo := &domain.Todo{Title: "並行して作成"}
+			assert.NoError(t, repo.Create(todo, nil))
+			_, err := repo.FindAll(domain.TodoQuery{Search: "並行"})
+			assert.NoError(t, err)
+			todo.Done = true
+			assert.NoError(t, repo.Update(todo, nil))
+		}()
+	}
+	wg.Wait()
+
+	done := true
+	count, err := repo.Count(domain.TodoQuery{Done: &done})
+	require.NoError(t, err)
+	assert.Equal(t, int64(20), count)
+}
+
+func TestMemoryStoreReturnsCopies(t *testing.T) {
+	repo := NewMemoryStore().TodoRepository()
+	parentID := uint(1)
+	todo := &domain.Todo{Title: "元のタイトル", ParentID: &parentID}
+	createTodos(t, repo, todo)
+
+	// 取得したTodoや作成に使ったTodoを変更しても、保持している内容は変わらない
+	found, err := repo.FindByID("1")
+	require.NoError(t, err)
+	found.Title = "変更"
+	*found.ParentID = 99
+	*todo.ParentID = 99
+
+	found, err = repo.FindByID("1")
+	require.NoError(t, err)
+	assert.Equal(t, "元のタイトル", found.Title)
+	assert.Equal(t, uint(1), *found.ParentID)
+
+	// 数値でないIDは見つからないものとして扱う
+	found, err = repo.FindByID("abc")
+	require.NoError(t, err)
+	assert.Nil(t, found)
+}
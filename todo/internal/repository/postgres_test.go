@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+)
+
+// setupPostgresMockDB はPostgreSQLのダイアレクトでsqlmockに接続する
+// 識別子の引用符（"）とプレースホルダー（$1）がMySQLと異なるため、同じ操作で発行されるクエリを確認する
+func setupPostgresMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, func()) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmockの作成に失敗しました: %v", err)
+	}
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: mockDB}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("gormの初期化に失敗しました: %v", err)
+	}
+
+	return db, mock, func() {
+		mockDB.Close()
+	}
+}
+
+func TestPostgresFindAll(t *testing.T) {
+	done := false
+	due := time.Date(2025, 4, 1, 18, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		query         domain.TodoQuery
+		expectedQuery string
+		expectedArgs  []driver.Value
+	}{
+		{
+			name:          "完了状態と文字列で絞り込み（大文字と小文字を区別しない）",
+			query:         domain.TodoQuery{Done: &done, Search: "Meeting"},
+			expectedQuery: `WHERE deleted_at IS NULL AND done = $1 AND (LOWER(title) LIKE LOWER($2) ESCAPE '!' OR LOWER(description) LIKE LOWER($3) ESCAPE '!') ORDER BY id ASC`,
+			expectedArgs:  []driver.Value{false, "%Meeting%", "%Meeting%"},
+		},
+		{
+			name:          "タグ（すべて一致）で絞り込み",
+			query:         domain.TodoQuery{Tags: []string{"仕事", "急ぎ"}, TagMatch: domain.TagMatchAll},
+			expectedQuery: `WHERE deleted_at IS NULL AND id IN (SELECT todo_tags.todo_id FROM "todo_tags" JOIN tags ON tags.id = todo_tags.tag_id WHERE tags.name IN ($1,$2) GROUP BY "todo_tags"."todo_id" HAVING COUNT(DISTINCT tags.id) = $3) ORDER BY id ASC`,
+			expectedArgs:  []driver.Value{"仕事", "急ぎ", 2},
+		},
+		{
+			name:          "期限順でカーソルの後ろから取得（期限未設定を含む）",
+			query:         domain.TodoQuery{Sort: domain.SortByDue, Limit: 2, After: &domain.Cursor{Sort: domain.SortByDue, ID: 3, Key: due.Format(time.RFC3339Nano)}},
+			expectedQuery: `WHERE deleted_at IS NULL AND (due_at IS NULL OR due_at > $1 OR (due_at = $2 AND id > $3)) ORDER BY due_at IS NULL,due_at ASC,id ASC LIMIT $4`,
+			expectedArgs:  []driver.Value{due, due, 3, 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, cleanup := setupPostgresMockDB(t)
+			defer cleanup()
+
+			// モックの設定
+			rows := sqlmock.NewRows([]string{"id", "title", "done"}).
+				AddRow(4, "Test Todo", false)
+
+			mock.ExpectQuery(`^SELECT \* FROM "todos" ` + regexp.QuoteMeta(tc.expectedQuery) + "$").
+				WithArgs(tc.expectedArgs...).
+				WillReturnRows(rows)
+			mock.ExpectQuery(`^SELECT \* FROM "todo_tags" WHERE "todo_tags"."todo_id" = \$1`).
+				WithArgs(4).
+				WillReturnRows(sqlmock.NewRows([]string{"todo_id", "tag_id"}))
+
+			// テスト実行
+			repo := NewTodoRepository(db)
+			todos, err := repo.FindAll(tc.query)
+
+			// 検証
+			assert.NoError(t, err)
+			assert.Len(t, todos, 1)
+
+			// モックの期待通りに呼ばれたか確認
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("未実行のクエリがあります: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresCreate(t *testing.T) {
+	db, mock, cleanup := setupPostgresMockDB(t)
+	defer cleanup()
+
+	// モックの設定 - PostgreSQLではRETURNINGで採番されたIDを受け取る
+	mock.ExpectBegin()
+	mock.ExpectQuery(`^INSERT INTO "todos" (.+) RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	repo := NewTodoRepository(db)
+
+	todo := &domain.Todo{Title: "New Todo"}
+	err := repo.Create(todo, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), todo.ID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("未実行のクエリがあります: %v", err)
+	}
+}
+
+func TestPostgresDeleteWithSubtasks(t *testing.T) {
+	db, mock, cleanup := setupPostgresMockDB(t)
+	defer cleanup()
+
+	// モックの設定 - 親(1) → 子(2) の階層を辿ってまとめてゴミ箱に移動する
+	mock.ExpectBegin()
+	mock.ExpectQuery(`^SELECT "id" FROM "todos" WHERE parent_id IN \(\$1\) AND deleted_at IS NULL`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectQuery(`^SELECT "id" FROM "todos" WHERE parent_id IN \(\$1\) AND deleted_at IS NULL`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec(`^UPDATE "todos" SET "deleted_at"=\$1,"version"=version \+ 1 WHERE id = \$2 AND version = \$3 AND deleted_at IS NULL`).
+		WithArgs(sqlmock.AnyArg(), 1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`^UPDATE "todos" SET "deleted_at"=\$1,"version"=version \+ 1 WHERE id IN \(\$2\)`).
+		WithArgs(sqlmock.AnyArg(), 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := NewTodoRepository(db)
+
+	todo := &domain.Todo{ID: 1, Version: 3}
+	err := repo.Delete(todo, nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, todo.DeletedAt)
+	assert.Equal(t, uint(4), todo.Version)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("未実行のクエリがあります: %v", err)
+	}
+}
+
+func TestPostgresSearch(t *testing.T) {
+	db, mock, cleanup := setupPostgresMockDB(t)
+	defer cleanup()
+
+	// モックの設定 - 全文検索はSQLiteのみ対応のため、todosテーブルを部分一致で検索する
+	mock.ExpectQuery(`^SELECT id AS id, title, description FROM "todos" WHERE deleted_at IS NULL AND \(LOWER\(title\) LIKE LOWER\(\$1\) ESCAPE '!' OR LOWER\(description\) LIKE LOWER\(\$2\) ESCAPE '!'\) ORDER BY id DESC LIMIT \$3$`).
+		WithArgs("%meeting%", "%meeting%", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description"}).AddRow(1, "Weekly Meeting", ""))
+	mock.ExpectQuery(`^SELECT \* FROM "todos" WHERE id IN \(\$1\)`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(1, "Weekly Meeting"))
+	mock.ExpectQuery(`^SELECT \* FROM "todo_tags" WHERE "todo_tags"."todo_id" = \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"todo_id", "tag_id"}))
+
+	repo := NewTodoRepository(db)
+
+	results, err := repo.Search("meeting", 10)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, "Weekly <mark>Meeting</mark>", results[0].Title)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("未実行のクエリがあります: %v", err)
+	}
+}
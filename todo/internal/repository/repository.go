@@ -1,40 +1,212 @@
 package repository
 
 import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
 	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // TodoRepository はTodoエンティティのデータアクセスを担当する構造体
 type TodoRepository struct {
-    db *gorm.DB
+    db       *gorm.DB
+    fullText bool // FTS5による全文検索を使用するか
 }
 
+// ErrVersionConflict は更新・削除しようとしたTodoのバージョンが保存されているバージョンと異なることを表す
+// 取得した後に他の操作で更新された場合に返す
+var ErrVersionConflict = errors.New("Todoは他の操作で更新されています")
+
+// ErrAsOfNotSupported は変更の履歴を保持しない保存先で、過去の時点の状態（TodoQuery.AsOf）を指定したことを表す
+var ErrAsOfNotSupported = errors.New("過去の時点の状態はイベントストアでのみ取得できます")
+
 // TodoRepositoryInterface はTodoRepositoryのインターフェース
 type TodoRepositoryInterface interface {
-	FindAll() ([]domain.Todo, error)
+	FindAll(query domain.TodoQuery) ([]domain.Todo, error)
+	Count(query domain.TodoQuery) (int64, error)
 	FindByID(id string) (*domain.Todo, error)
-	Create(todo *domain.Todo) error
-	Update(todo *domain.Todo) error
-	Delete(todo *domain.Todo) error
+	Create(todo *domain.Todo, audit *domain.AuditEntry) error
+	Update(todo *domain.Todo, audit *domain.AuditEntry) error
+	Delete(todo *domain.Todo, audit *domain.AuditEntry) error
+	Search(text string, limit int) ([]domain.SearchResult, error)
+	FindTrashed() ([]domain.Todo, error)
+	FindTrashedByID(id string) (*domain.Todo, error)
+	Restore(todo *domain.Todo, audit *domain.AuditEntry) error
+	Purge(before time.Time, audit *domain.AuditEntry) (int64, error)
+	FindAuditLog(query domain.AuditQuery) ([]domain.AuditEntry, error)
+	Batch(fn func(repo TodoRepositoryInterface) error) error
+	WithContext(ctx context.Context) TodoRepositoryInterface
 }
 
+// notDeleted はゴミ箱にないTodoに絞り込む条件
+// ゴミ箱のTodoは FindTrashed / FindTrashedByID 以外では取得しない
+const notDeleted = "deleted_at IS NULL"
+
 // NewTodoRepository はTodoRepositoryのコンストラクタ
-func NewTodoRepository(db *gorm.DB) TodoRepositoryInterface {
-    return &TodoRepository{db: db}
+func NewTodoRepository(db *gorm.DB, opts ...Option) TodoRepositoryInterface {
+    r := &TodoRepository{db: db}
+    for _, opt := range opts {
+        opt(r)
+    }
+    return r
 }
 
-// FindAll はすべてのTodoを取得するメソッド
-func (r *TodoRepository) FindAll() ([]domain.Todo, error) {
+// FindAll は絞り込み条件に一致するTodoを取得するメソッド
+// query.After と query.Limit を指定した場合は、カーソルの後ろから指定件数までを取得する
+func (r *TodoRepository) FindAll(query domain.TodoQuery) ([]domain.Todo, error) {
+	if query.AsOf != nil {
+		return nil, ErrAsOfNotSupported
+	}
+	db := applyFilters(r.db, query)
+	if query.After != nil {
+		var err error
+		if db, err = applyCursor(db, query); err != nil {
+			return nil, err
+		}
+	}
+	db = applyOrder(db, query)
+	if query.Limit > 0 {
+		db = db.Limit(query.Limit)
+	}
+
 	var todos []domain.Todo
-	result := r.db.Find(&todos)
+	result := db.Preload("Tags").Find(&todos)
 	return todos, result.Error
 }
 
-// FindByID は指定されたIDのTodoを取得するメソッド
+// Count は絞り込み条件に一致するTodoの件数を取得するメソッド
+// ページングの指定（query.After と query.Limit）は件数に影響しない
+func (r *TodoRepository) Count(query domain.TodoQuery) (int64, error) {
+	if query.AsOf != nil {
+		return 0, ErrAsOfNotSupported
+	}
+	var count int64
+	result := applyFilters(r.db.Model(&domain.Todo{}), query).Count(&count)
+	return count, result.Error
+}
+
+// 並び替え項目と列名の対応
+// ユーザーの入力を直接ORDER BY句に埋め込まないよう、許可した列のみを使用する
+var sortColumns = map[domain.SortField]string{
+	domain.SortByPriority: "priority",
+	domain.SortByCreated:  "created_at",
+	domain.SortByDue:      "due_at",
+	domain.SortByTitle:    "title",
+}
+
+// applyFilters は絞り込み条件をクエリに反映する（ゴミ箱のTodoは常に除く）
+func applyFilters(db *gorm.DB, query domain.TodoQuery) *gorm.DB {
+	db = db.Where(notDeleted)
+	if query.ParentID != nil {
+		db = db.Where("parent_id = ?", *query.ParentID)
+	}
+	if query.Overdue {
+		db = db.Where("done = ? AND due_at IS NOT NULL AND due_at < ?", false, query.Now.UTC())
+	}
+	if len(query.Tags) > 0 {
+		db = db.Where("id IN (?)", taggedTodoIDs(db, query.Tags, query.TagMatch))
+	}
+	if query.Done != nil {
+		db = db.Where("done = ?", *query.Done)
+	}
+	if query.Search != "" {
+		pattern := "%" + likeEscaper.Replace(query.Search) + "%"
+		db = db.Where(keywordCondition, pattern, pattern)
+	}
+	return db
+}
+
+// keywordCondition はタイトルか説明にキーワードを含むことを表す条件
+// LIKEで大文字と小文字を区別するかがDBごとに異なる（PostgreSQLのみ区別する）ため、両辺を小文字にして比較する
+const keywordCondition = "LOWER(title) LIKE LOWER(?) ESCAPE '!' OR LOWER(description) LIKE LOWER(?) ESCAPE '!'"
+
+// likeEscaper はLIKE句のワイルドカードをエスケープする
+// バックスラッシュの扱いがDBごとに異なるため、エスケープ文字には '!' を使う
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// applyOrder は並び順をクエリに反映する
+func applyOrder(db *gorm.DB, query domain.TodoQuery) *gorm.DB {
+	direction := "ASC"
+	if query.Desc {
+		direction = "DESC"
+	}
+	if column, ok := sortColumns[query.Sort]; ok {
+		if query.Sort == domain.SortByDue {
+			// 期限未設定のタスクは並び順に関わらず末尾に置く
+			db = db.Order("due_at IS NULL")
+		}
+		db = db.Order(column + " " + direction)
+	}
+	// 同じ値のタスクの並びを安定させるため、最後にIDで並び替える
+	return db.Order("id " + direction)
+}
+
+// applyCursor は applyOrder の並び順でカーソルより後ろにあるTodoに絞り込む
+func applyCursor(db *gorm.DB, query domain.TodoQuery) (*gorm.DB, error) {
+	after := query.After
+	value, err := after.SortValue()
+	if err != nil {
+		return nil, err
+	}
+
+	if t, ok := value.(time.Time); ok && query.Sort == domain.SortByDue {
+		// 期限日時はUTCで保存しているため、カーソルの値もUTCにして比較する
+		value = t.UTC()
+	}
+
+	op := ">"
+	if query.Desc {
+		op = "<"
+	}
+	column, ok := sortColumns[query.Sort]
+	switch {
+	case !ok:
+		return db.Where("id "+op+" ?", after.ID), nil
+	case query.Sort == domain.SortByDue && value == nil:
+		// 期限未設定のタスクは末尾にまとまっているため、その中でIDを比較する
+		return db.Where("due_at IS NULL AND id "+op+" ?", after.ID), nil
+	case query.Sort == domain.SortByDue:
+		return db.Where("due_at IS NULL OR due_at "+op+" ? OR (due_at = ? AND id "+op+" ?)", value, value, after.ID), nil
+	default:
+		return db.Where(column+" "+op+" ? OR ("+column+" = ? AND id "+op+" ?)", value, value, after.ID), nil
+	}
+}
+
+// taggedTodoIDs は指定したタグが付いたTodoのIDを取得するサブクエリを作成する
+// TagMatchAll の場合はすべてのタグが付いたTodoのみを対象とする
+func taggedTodoIDs(db *gorm.DB, names []string, match domain.TagMatch) *gorm.DB {
+	sub := db.Session(&gorm.Session{NewDB: true}).
+		Table("todo_tags").
+		Select("todo_tags.todo_id").
+		Joins("JOIN tags ON tags.id = todo_tags.tag_id").
+		Where("tags.name IN ?", names)
+	if match == domain.TagMatchAll {
+		sub = sub.Group("todo_tags.todo_id").Having("COUNT(DISTINCT tags.id) = ?", len(uniqueStrings(names)))
+	}
+	return sub
+}
+
+// uniqueStrings は重複を取り除いた文字列のスライスを返す
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var unique []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
+
+// FindByID は指定されたIDのTodoを取得するメソッド（ゴミ箱のTodoは見つからないものとして扱う）
 func (r *TodoRepository) FindByID(id string) (*domain.Todo, error) {
 	var todo domain.Todo
-	result := r.db.First(&todo, id)
+	result := r.db.Preload("Tags").Where(notDeleted).First(&todo, id)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
 			return nil, nil // レコードが見つからない場合は特別扱い
@@ -45,19 +217,239 @@ func (r *TodoRepository) FindByID(id string) (*domain.Todo, error) {
 }
 
 // Create は新しいTodoを作成するメソッド
-func (r *TodoRepository) Create(todo *domain.Todo) error {
+// audit を指定した場合は、作成したTodoのIDを設定した監査ログを同じトランザクションで記録する
+func (r *TodoRepository) Create(todo *domain.Todo, audit *domain.AuditEntry) error {
+    if todo.Version == 0 {
+        todo.Version = 1
+    }
+    todo.NormalizeSchedule()
+    if r.fullText || audit != nil {
+        return r.db.Transaction(func(tx *gorm.DB) error {
+            if err := tx.Create(todo).Error; err != nil {
+                return err
+            }
+            if r.fullText {
+                if err := syncSearchIndex(tx, todo); err != nil {
+                    return err
+                }
+            }
+            return writeAudit(tx, audit, *todo, auditTime())
+        })
+    }
     result := r.db.Create(todo)
     return result.Error
 }
 
 // Update は指定されたTodoを更新するメソッド
-func (r *TodoRepository) Update(todo *domain.Todo) error {
-    result := r.db.Save(todo)
-    return result.Error
+// 保存されているバージョンが todo.Version と一致する場合のみ更新してバージョンを1つ進め、一致しない場合（ゴミ箱にある場合を含む）は ErrVersionConflict を返す
+// タグの付け外しは TagRepository で行うため、関連は更新しない。audit を指定した場合は監査ログを同じトランザクションで記録する
+func (r *TodoRepository) Update(todo *domain.Todo, audit *domain.AuditEntry) error {
+    if r.fullText || audit != nil {
+        return r.db.Transaction(func(tx *gorm.DB) error {
+            if err := updateVersioned(tx, todo); err != nil {
+                return err
+            }
+            if r.fullText {
+                if err := syncSearchIndex(tx, todo); err != nil {
+                    return err
+                }
+            }
+            return writeAudit(tx, audit, *todo, auditTime())
+        })
+    }
+    return updateVersioned(r.db, todo)
 }
 
-// Delete は指定されたTodoを削除するメソッド
-func (r *TodoRepository) Delete(todo *domain.Todo) error {
-    result := r.db.Delete(todo)
-    return result.Error
+// updateVersioned はバージョンを比較してからTodoを更新する（compare-and-swap）
+// 比較と更新を1つのUPDATE文で行うため、同時に更新されても後から更新した側が失敗する
+func updateVersioned(db *gorm.DB, todo *domain.Todo) error {
+	todo.NormalizeSchedule()
+	updated := *todo
+	updated.Version = todo.Version + 1
+	result := db.Model(&domain.Todo{}).
+		Where("id = ? AND version = ? AND "+notDeleted, todo.ID, todo.Version).
+		Select("*").Omit("id", clause.Associations).
+		Updates(&updated)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	todo.Version = updated.Version
+	return nil
+}
+
+// Delete は指定されたTodoをゴミ箱に移動するメソッド（論理削除）
+// サブタスク（孫以降を含む）も同じ日時でゴミ箱に移動し、バージョンを1つ進める。タグとの関連は復元に備えて残す
+// 指定されたTodoのバージョンが保存されているバージョンと異なる場合は何も変更せずに ErrVersionConflict を返す
+// audit を指定した場合は、ゴミ箱に移動したTodoごとに監査ログを同じトランザクションで記録する
+func (r *TodoRepository) Delete(todo *domain.Todo, audit *domain.AuditEntry) error {
+	// MySQLの datetime(3) に合わせてミリ秒に切り捨てる（復元時に同じ日時で削除したサブタスクを探すため）
+	deletedAt := time.Now().UTC().Truncate(time.Millisecond)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		descendants, err := descendantIDs(tx, todo.ID, nil)
+		if err != nil {
+			return err
+		}
+
+		changes := map[string]interface{}{"deleted_at": deletedAt, "version": gorm.Expr("version + 1")}
+		result := tx.Model(&domain.Todo{}).Where("id = ? AND version = ? AND "+notDeleted, todo.ID, todo.Version).Updates(changes)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
+		}
+		if len(descendants) > 0 {
+			if err := tx.Model(&domain.Todo{}).Where("id IN ?", descendants).Updates(changes).Error; err != nil {
+				return err
+			}
+		}
+		ids := append([]uint{todo.ID}, descendants...)
+		if r.fullText {
+			// ゴミ箱のTodoは検索しないため、検索用のテーブルからは削除する
+			if err := tx.Exec("DELETE FROM "+searchTable+" WHERE rowid IN ?", ids).Error; err != nil {
+				return err
+			}
+		}
+		return writeAuditForIDs(tx, audit, ids, deletedAtChanges(nil, &deletedAt), deletedAt)
+	})
+	if err != nil {
+		return err
+	}
+	todo.DeletedAt = &deletedAt
+	todo.Version++
+	return nil
+}
+
+// FindTrashed はゴミ箱のTodoを、ゴミ箱に移動した日時の新しい順に取得するメソッド
+func (r *TodoRepository) FindTrashed() ([]domain.Todo, error) {
+	var todos []domain.Todo
+	result := r.db.Preload("Tags").Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Order("id").Find(&todos)
+	return todos, result.Error
+}
+
+// FindTrashedByID はゴミ箱にある指定されたIDのTodoを取得するメソッド
+func (r *TodoRepository) FindTrashedByID(id string) (*domain.Todo, error) {
+	var todo domain.Todo
+	result := r.db.Preload("Tags").Where("deleted_at IS NOT NULL").First(&todo, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &todo, nil
+}
+
+// Restore はゴミ箱のTodoを元に戻すメソッド
+// 一緒にゴミ箱に移動したサブタスク（ゴミ箱に移動した日時が同じもの）も元に戻し、バージョンを1つ進める
+// 指定されたTodoのバージョンが保存されているバージョンと異なる場合は何も変更せずに ErrVersionConflict を返す
+// audit を指定した場合は、元に戻したTodoごとに監査ログを同じトランザクションで記録する
+func (r *TodoRepository) Restore(todo *domain.Todo, audit *domain.AuditEntry) error {
+	if todo.DeletedAt == nil {
+		return ErrVersionConflict
+	}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		descendants, err := descendantIDs(tx, todo.ID, todo.DeletedAt)
+		if err != nil {
+			return err
+		}
+
+		changes := map[string]interface{}{"deleted_at": nil, "version": gorm.Expr("version + 1")}
+		result := tx.Model(&domain.Todo{}).Where("id = ? AND version = ? AND deleted_at IS NOT NULL", todo.ID, todo.Version).Updates(changes)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
+		}
+		if len(descendants) > 0 {
+			if err := tx.Model(&domain.Todo{}).Where("id IN ?", descendants).Updates(changes).Error; err != nil {
+				return err
+			}
+		}
+		ids := append([]uint{todo.ID}, descendants...)
+		if r.fullText {
+			if err := tx.Exec("INSERT INTO "+searchTable+" (rowid, title, description) SELECT id, title, description FROM todos WHERE id IN ?", ids).Error; err != nil {
+				return err
+			}
+		}
+		return writeAuditForIDs(tx, audit, ids, deletedAtChanges(todo.DeletedAt, nil), auditTime())
+	})
+	if err != nil {
+		return err
+	}
+	todo.DeletedAt = nil
+	todo.Version++
+	return nil
+}
+
+// Purge はゴミ箱に移動した日時が before より前のTodoを完全に削除し、削除した件数を返すメソッド
+// タグとの関連（todo_tags）も同じトランザクションで削除する。audit を指定した場合は、削除したTodoごとに監査ログを記録する
+func (r *TodoRepository) Purge(before time.Time, audit *domain.AuditEntry) (int64, error) {
+	var purged int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		if err := tx.Model(&domain.Todo{}).Where("deleted_at IS NOT NULL AND deleted_at < ?", before.UTC()).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		if err := writeAuditForIDs(tx, audit, ids, nil, auditTime()); err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM todo_tags WHERE todo_id IN ?", ids).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id IN ?", ids).Delete(&domain.Todo{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
+
+// Batch は fn に渡したリポジトリでの操作を1つのトランザクションで実行するメソッド
+// fn がエラーを返した場合はすべての操作を取り消す。Batch の中で渡されたリポジトリの Batch を呼び出した場合はセーブポイントになり、その中の操作だけを取り消せる
+func (r *TodoRepository) Batch(fn func(repo TodoRepositoryInterface) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		repo := *r
+		repo.db = tx
+		return fn(&repo)
+	})
+}
+
+// WithContext は ctx が終了した時点で処理を中断するリポジトリを返すメソッド
+// 元のリポジトリは変更しないため、リクエストごとに ctx を設定して使える
+func (r *TodoRepository) WithContext(ctx context.Context) TodoRepositoryInterface {
+	repo := *r
+	repo.db = r.db.WithContext(ctx)
+	return &repo
+}
+
+// descendantIDs は指定したTodoの子孫にあたるTodoのIDを階層ごとに取得する
+// deletedAt が nil の場合はゴミ箱にないものを、nil以外の場合はその日時にゴミ箱に移動したものを対象とする
+func descendantIDs(db *gorm.DB, id uint, deletedAt *time.Time) ([]uint, error) {
+	var descendants []uint
+	parents := []uint{id}
+	for len(parents) > 0 {
+		var children []uint
+		query := db.Model(&domain.Todo{}).Where("parent_id IN ?", parents)
+		if deletedAt == nil {
+			query = query.Where(notDeleted)
+		} else {
+			query = query.Where("deleted_at = ?", deletedAt.UTC())
+		}
+		if err := query.Pluck("id", &children).Error; err != nil {
+			return nil, err
+		}
+		descendants = append(descendants, children...)
+		parents = children
+	}
+	return descendants, nil
 }
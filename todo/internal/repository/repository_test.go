@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"database/sql/driver"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
 	"github.com/stretchr/testify/assert"
@@ -32,6 +35,12 @@ func setupMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, func()) {
 	}
 }
 
+// expectTagPreload はタグのPreloadで発行されるクエリの期待値を設定する（タグなし）
+func expectTagPreload(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("^SELECT \\* FROM `todo_tags` WHERE `todo_tags`.`todo_id`").
+		WillReturnRows(sqlmock.NewRows([]string{"todo_id", "tag_id"}))
+}
+
 func TestFindAll(t *testing.T) {
 	db, mock, cleanup := setupMockDB(t)
 	defer cleanup()
@@ -42,12 +51,13 @@ func TestFindAll(t *testing.T) {
 		AddRow(2, "Test Todo 2", true)
 
 	mock.ExpectQuery("^SELECT (.+) FROM `todos`").WillReturnRows(rows)
+	expectTagPreload(mock)
 
 	// テスト対象のリポジトリを作成
 	repo := NewTodoRepository(db)
 
 	// テスト実行
-	todos, err := repo.FindAll()
+	todos, err := repo.FindAll(domain.TodoQuery{})
 
 	// 検証
 	assert.NoError(t, err)
@@ -65,6 +75,250 @@ func TestFindAll(t *testing.T) {
 	}
 }
 
+func TestFindAllOverdue(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	now := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	due := now.Add(-time.Hour)
+
+	// モックの設定
+	rows := sqlmock.NewRows([]string{"id", "title", "done", "due_at"}).
+		AddRow(1, "Overdue Todo", false, due)
+
+	mock.ExpectQuery("^SELECT \\* FROM `todos` WHERE deleted_at IS NULL AND \\(done = \\? AND due_at IS NOT NULL AND due_at < \\?\\)").
+		WithArgs(false, now).
+		WillReturnRows(rows)
+	expectTagPreload(mock)
+
+	// テスト対象のリポジトリを作成
+	repo := NewTodoRepository(db)
+
+	// テスト実行
+	todos, err := repo.FindAll(domain.TodoQuery{Overdue: true, Now: now})
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Len(t, todos, 1)
+	assert.Equal(t, "Overdue Todo", todos[0].Title)
+	assert.True(t, todos[0].IsOverdue(now))
+
+	// モックの期待通りに呼ばれたか確認
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("未実行のクエリがあります: %v", err)
+	}
+}
+
+func TestFindAllSorted(t *testing.T) {
+	testCases := []struct {
+		name          string
+		query         domain.TodoQuery
+		expectedOrder string
+	}{
+		{
+			name:          "優先度の降順",
+			query:         domain.TodoQuery{Sort: domain.SortByPriority, Desc: true},
+			expectedOrder: "WHERE deleted_at IS NULL ORDER BY priority DESC,id DESC",
+		},
+		{
+			name:          "期限の昇順（期限未設定は末尾）",
+			query:         domain.TodoQuery{Sort: domain.SortByDue},
+			expectedOrder: "WHERE deleted_at IS NULL ORDER BY due_at IS NULL,due_at ASC,id ASC",
+		},
+		{
+			name:          "タイトルの昇順",
+			query:         domain.TodoQuery{Sort: domain.SortByTitle},
+			expectedOrder: "WHERE deleted_at IS NULL ORDER BY title ASC,id ASC",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, cleanup := setupMockDB(t)
+			defer cleanup()
+
+			// モックの設定
+			rows := sqlmock.NewRows([]string{"id", "title", "done", "priority"}).
+				AddRow(1, "Test Todo", false, int(domain.PriorityHigh))
+
+			mock.ExpectQuery("^SELECT \\* FROM `todos` " + regexp.QuoteMeta(tc.expectedOrder) + "$").
+				WillReturnRows(rows)
+			expectTagPreload(mock)
+
+			// テスト実行
+			repo := NewTodoRepository(db)
+			todos, err := repo.FindAll(tc.query)
+
+			// 検証
+			assert.NoError(t, err)
+			assert.Len(t, todos, 1)
+			assert.Equal(t, domain.PriorityHigh, todos[0].Priority)
+
+			// モックの期待通りに呼ばれたか確認
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("未実行のクエリがあります: %v", err)
+			}
+		})
+	}
+}
+
+func TestFindAllByTags(t *testing.T) {
+	testCases := []struct {
+		name          string
+		query         domain.TodoQuery
+		expectedWhere string
+		expectedArgs  []driver.Value
+	}{
+		{
+			name:          "いずれかのタグに一致",
+			query:         domain.TodoQuery{Tags: []string{"仕事", "急ぎ"}, TagMatch: domain.TagMatchAny},
+			expectedWhere: "WHERE deleted_at IS NULL AND id IN (SELECT todo_tags.todo_id FROM `todo_tags` JOIN tags ON tags.id = todo_tags.tag_id WHERE tags.name IN (?,?))",
+			expectedArgs:  []driver.Value{"仕事", "急ぎ"},
+		},
+		{
+			name:          "すべてのタグに一致",
+			query:         domain.TodoQuery{Tags: []string{"仕事", "急ぎ", "仕事"}, TagMatch: domain.TagMatchAll},
+			expectedWhere: "WHERE deleted_at IS NULL AND id IN (SELECT todo_tags.todo_id FROM `todo_tags` JOIN tags ON tags.id = todo_tags.tag_id WHERE tags.name IN (?,?,?) GROUP BY `todo_tags`.`todo_id` HAVING COUNT(DISTINCT tags.id) = ?)",
+			expectedArgs:  []driver.Value{"仕事", "急ぎ", "仕事", 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, cleanup := setupMockDB(t)
+			defer cleanup()
+
+			// モックの設定
+			rows := sqlmock.NewRows([]string{"id", "title", "done"}).
+				AddRow(1, "Tagged Todo", false)
+
+			mock.ExpectQuery("^SELECT \\* FROM `todos` " + regexp.QuoteMeta(tc.expectedWhere)).
+				WithArgs(tc.expectedArgs...).
+				WillReturnRows(rows)
+			mock.ExpectQuery("^SELECT \\* FROM `todo_tags` WHERE `todo_tags`.`todo_id`").
+				WillReturnRows(sqlmock.NewRows([]string{"todo_id", "tag_id"}).AddRow(1, 1))
+			mock.ExpectQuery("^SELECT \\* FROM `tags` WHERE `tags`.`id`").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "仕事"))
+
+			// テスト実行
+			repo := NewTodoRepository(db)
+			todos, err := repo.FindAll(tc.query)
+
+			// 検証
+			assert.NoError(t, err)
+			assert.Len(t, todos, 1)
+			assert.Equal(t, []domain.Tag{{ID: 1, Name: "仕事"}}, todos[0].Tags)
+
+			// モックの期待通りに呼ばれたか確認
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("未実行のクエリがあります: %v", err)
+			}
+		})
+	}
+}
+
+func TestFindAllPaged(t *testing.T) {
+	done := false
+	due := time.Date(2025, 4, 1, 18, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		query         domain.TodoQuery
+		expectedQuery string
+		expectedArgs  []driver.Value
+	}{
+		{
+			name:          "完了状態と文字列で絞り込み",
+			query:         domain.TodoQuery{Done: &done, Search: "100%_達成"},
+			expectedQuery: "WHERE deleted_at IS NULL AND done = ? AND (LOWER(title) LIKE LOWER(?) ESCAPE '!' OR LOWER(description) LIKE LOWER(?) ESCAPE '!') ORDER BY id ASC",
+			expectedArgs:  []driver.Value{false, "%100!%!_達成%", "%100!%!_達成%"},
+		},
+		{
+			name:          "ID順でカーソルの後ろから取得",
+			query:         domain.TodoQuery{Limit: 2, After: &domain.Cursor{ID: 3}},
+			expectedQuery: "WHERE deleted_at IS NULL AND id > ? ORDER BY id ASC LIMIT ?",
+			expectedArgs:  []driver.Value{3, 2},
+		},
+		{
+			name:          "優先度の降順でカーソルの後ろから取得",
+			query:         domain.TodoQuery{Sort: domain.SortByPriority, Desc: true, Limit: 2, After: &domain.Cursor{Sort: domain.SortByPriority, Desc: true, ID: 3, Key: "2"}},
+			expectedQuery: "WHERE deleted_at IS NULL AND (priority < ? OR (priority = ? AND id < ?)) ORDER BY priority DESC,id DESC LIMIT ?",
+			expectedArgs:  []driver.Value{2, 2, 3, 2},
+		},
+		{
+			name:          "期限順でカーソルの後ろから取得（期限未設定を含む）",
+			query:         domain.TodoQuery{Sort: domain.SortByDue, Limit: 2, After: &domain.Cursor{Sort: domain.SortByDue, ID: 3, Key: due.Format(time.RFC3339Nano)}},
+			expectedQuery: "WHERE deleted_at IS NULL AND (due_at IS NULL OR due_at > ? OR (due_at = ? AND id > ?)) ORDER BY due_at IS NULL,due_at ASC,id ASC LIMIT ?",
+			expectedArgs:  []driver.Value{due, due, 3, 2},
+		},
+		{
+			name:          "絞り込みとカーソルを組み合わせて取得",
+			query:         domain.TodoQuery{Done: &done, Sort: domain.SortByTitle, Limit: 2, After: &domain.Cursor{Sort: domain.SortByTitle, ID: 3, Key: "牛乳"}},
+			expectedQuery: "WHERE deleted_at IS NULL AND done = ? AND (title > ? OR (title = ? AND id > ?)) ORDER BY title ASC,id ASC LIMIT ?",
+			expectedArgs:  []driver.Value{false, "牛乳", "牛乳", 3, 2},
+		},
+		{
+			name:          "期限未設定のカーソルの後ろから取得",
+			query:         domain.TodoQuery{Sort: domain.SortByDue, Limit: 2, After: &domain.Cursor{Sort: domain.SortByDue, ID: 3}},
+			expectedQuery: "WHERE deleted_at IS NULL AND (due_at IS NULL AND id > ?) ORDER BY due_at IS NULL,due_at ASC,id ASC LIMIT ?",
+			expectedArgs:  []driver.Value{3, 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, cleanup := setupMockDB(t)
+			defer cleanup()
+
+			// モックの設定
+			rows := sqlmock.NewRows([]string{"id", "title", "done"}).
+				AddRow(4, "Test Todo", false)
+
+			mock.ExpectQuery("^SELECT \\* FROM `todos` " + regexp.QuoteMeta(tc.expectedQuery) + "$").
+				WithArgs(tc.expectedArgs...).
+				WillReturnRows(rows)
+			expectTagPreload(mock)
+
+			// テスト実行
+			repo := NewTodoRepository(db)
+			todos, err := repo.FindAll(tc.query)
+
+			// 検証
+			assert.NoError(t, err)
+			assert.Len(t, todos, 1)
+
+			// モックの期待通りに呼ばれたか確認
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("未実行のクエリがあります: %v", err)
+			}
+		})
+	}
+}
+
+func TestCount(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	// モックの設定 - ページングの指定は件数に影響しない
+	done := true
+	mock.ExpectQuery("^" + regexp.QuoteMeta("SELECT count(*) FROM `todos` WHERE deleted_at IS NULL AND done = ?") + "$").
+		WithArgs(true).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+
+	// テスト実行
+	repo := NewTodoRepository(db)
+	count, err := repo.Count(domain.TodoQuery{Done: &done, Limit: 2, After: &domain.Cursor{ID: 3}})
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), count)
+
+	// モックの期待通りに呼ばれたか確認
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("未実行のクエリがあります: %v", err)
+	}
+}
+
 func TestFindByID(t *testing.T) {
 	db, mock, cleanup := setupMockDB(t)
 	defer cleanup()
@@ -73,12 +327,13 @@ func TestFindByID(t *testing.T) {
 	rows := sqlmock.NewRows([]string{"id", "title", "done"}).
 		AddRow(1, "Test Todo", false)
 
-	mock.ExpectQuery("^SELECT \\* FROM `todos` WHERE `todos`.`id` = \\? ORDER BY `todos`.`id` LIMIT \\?").
+	mock.ExpectQuery("^SELECT \\* FROM `todos` WHERE deleted_at IS NULL AND `todos`.`id` = \\? ORDER BY `todos`.`id` LIMIT \\?").
 		WithArgs("1", 1).
 		WillReturnRows(rows)
+	expectTagPreload(mock)
 
 	// モックの設定 - 存在しないID
-	mock.ExpectQuery("^SELECT \\* FROM `todos` WHERE `todos`.`id` = \\? ORDER BY `todos`.`id` LIMIT \\?").
+	mock.ExpectQuery("^SELECT \\* FROM `todos` WHERE deleted_at IS NULL AND `todos`.`id` = \\? ORDER BY `todos`.`id` LIMIT \\?").
 		WithArgs("999", 1).
 		WillReturnError(gorm.ErrRecordNotFound)
 
@@ -123,7 +378,7 @@ func TestCreate(t *testing.T) {
 
 	// テスト実行
 	todo := &domain.Todo{Title: "New Todo", Done: false}
-	err := repo.Create(todo)
+	err := repo.Create(todo, nil)
 
 	// 検証
 	assert.NoError(t, err)
@@ -141,7 +396,7 @@ func TestUpdate(t *testing.T) {
 
 	// モックの設定
 	mock.ExpectBegin()
-	mock.ExpectExec("^UPDATE `todos`").
+	mock.ExpectExec("^UPDATE `todos` SET (.+)`version`=\\?(.+) WHERE id = \\? AND version = \\? AND deleted_at IS NULL$").
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 
@@ -149,11 +404,45 @@ func TestUpdate(t *testing.T) {
 	repo := NewTodoRepository(db)
 
 	// テスト実行
-	todo := &domain.Todo{ID: 1, Title: "Updated Todo", Done: true}
-	err := repo.Update(todo)
+	todo := &domain.Todo{ID: 1, Title: "Updated Todo", Done: true, Version: 1}
+	err := repo.Update(todo, nil)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), todo.Version) // 更新後のバージョンを設定する
+
+	// モックの期待通りに呼ばれたか確認
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("未実行のクエリがあります: %v", err)
+	}
+}
+
+func TestUpdateWithAudit(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	// モックの設定 - 更新と同じトランザクションで監査ログを記録する
+	mock.ExpectBegin()
+	mock.ExpectExec("^UPDATE `todos` SET (.+) WHERE id = \\? AND version = \\? AND deleted_at IS NULL$").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("^INSERT INTO `audit_logs` \\(`todo_id`,`action`,`actor`,`version`,`changes`,`created_at`\\)").
+		WithArgs(1, "update", "alice", 2, `{"done":{"before":false,"after":true}}`, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectCommit()
+
+	// テスト実行
+	repo := NewTodoRepository(db)
+	before := domain.Todo{ID: 1, Title: "Todo", Version: 1}
+	todo := before
+	todo.Done = true
+	audit := &domain.AuditEntry{Action: domain.AuditUpdate, Actor: "alice", Changes: domain.TodoChanges(&before, todo)}
+	err := repo.Update(&todo, audit)
 
 	// 検証
 	assert.NoError(t, err)
+	assert.Equal(t, uint(7), audit.ID)
+	assert.Equal(t, uint(1), audit.TodoID)
+	assert.Equal(t, uint(2), audit.Version)
 
 	// モックの期待通りに呼ばれたか確認
 	if err := mock.ExpectationsWereMet(); err != nil {
@@ -161,22 +450,89 @@ func TestUpdate(t *testing.T) {
 	}
 }
 
+func TestUpdateVersionConflict(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	// モックの設定 - 他の操作でバージョンが進んでいるため、更新される行がない
+	mock.ExpectBegin()
+	mock.ExpectExec("^UPDATE `todos`").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	repo := NewTodoRepository(db)
+
+	todo := &domain.Todo{ID: 1, Title: "Updated Todo", Version: 1}
+	err := repo.Update(todo, nil)
+
+	assert.ErrorIs(t, err, ErrVersionConflict)
+	assert.Equal(t, uint(1), todo.Version)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("未実行のクエリがあります: %v", err)
+	}
+}
+
 func TestDelete(t *testing.T) {
 	db, mock, cleanup := setupMockDB(t)
 	defer cleanup()
 
 	// モックの設定
 	mock.ExpectBegin()
-	mock.ExpectExec("^DELETE FROM `todos`").
+	mock.ExpectQuery("^SELECT `id` FROM `todos` WHERE parent_id IN \\(\\?\\) AND deleted_at IS NULL").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec("^UPDATE `todos` SET `deleted_at`=\\?,`version`=version \\+ 1 WHERE id = \\? AND version = \\? AND deleted_at IS NULL").
+		WithArgs(sqlmock.AnyArg(), 1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// テスト対象のリポジトリを作成
+	repo := NewTodoRepository(db)
+
+	// テスト実行 - 行は削除せずゴミ箱に移動する
+	todo := &domain.Todo{ID: 1, Title: "Test Todo", Done: false, Version: 2}
+	err := repo.Delete(todo, nil)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.NotNil(t, todo.DeletedAt)
+	assert.Equal(t, uint(3), todo.Version)
+
+	// モックの期待通りに呼ばれたか確認
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("未実行のクエリがあります: %v", err)
+	}
+}
+
+func TestDeleteWithSubtasks(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	// モックの設定 - 親(1) → 子(2, 3) → 孫(4) の階層を辿ってまとめてゴミ箱に移動する
+	mock.ExpectBegin()
+	mock.ExpectQuery("^SELECT `id` FROM `todos` WHERE parent_id IN \\(\\?\\)").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2).AddRow(3))
+	mock.ExpectQuery("^SELECT `id` FROM `todos` WHERE parent_id IN \\(\\?,\\?\\)").
+		WithArgs(2, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	mock.ExpectQuery("^SELECT `id` FROM `todos` WHERE parent_id IN \\(\\?\\)").
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec("^UPDATE `todos` SET `deleted_at`=\\?,`version`=version \\+ 1 WHERE id = \\? AND version = \\? AND deleted_at IS NULL").
+		WithArgs(sqlmock.AnyArg(), 1, 1).
 		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("^UPDATE `todos` SET `deleted_at`=\\?,`version`=version \\+ 1 WHERE id IN \\(\\?,\\?,\\?\\)").
+		WithArgs(sqlmock.AnyArg(), 2, 3, 4).
+		WillReturnResult(sqlmock.NewResult(0, 3))
 	mock.ExpectCommit()
 
 	// テスト対象のリポジトリを作成
 	repo := NewTodoRepository(db)
 
 	// テスト実行
-	todo := &domain.Todo{ID: 1, Title: "Test Todo", Done: false}
-	err := repo.Delete(todo)
+	err := repo.Delete(&domain.Todo{ID: 1, Title: "Parent Todo", Version: 1}, nil)
 
 	// 検証
 	assert.NoError(t, err)
@@ -185,4 +541,73 @@ func TestDelete(t *testing.T) {
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("未実行のクエリがあります: %v", err)
 	}
-}
\ No newline at end of file
+}
+
+func TestRestore(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	// モックの設定 - 同じ日時にゴミ箱に移動したサブタスクだけを元に戻す
+	deletedAt := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	mock.ExpectBegin()
+	mock.ExpectQuery("^SELECT `id` FROM `todos` WHERE parent_id IN \\(\\?\\) AND deleted_at = \\?").
+		WithArgs(1, deletedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectQuery("^SELECT `id` FROM `todos` WHERE parent_id IN \\(\\?\\) AND deleted_at = \\?").
+		WithArgs(2, deletedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec("^UPDATE `todos` SET `deleted_at`=\\?,`version`=version \\+ 1 WHERE id = \\? AND version = \\? AND deleted_at IS NOT NULL").
+		WithArgs(nil, 1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("^UPDATE `todos` SET `deleted_at`=\\?,`version`=version \\+ 1 WHERE id IN \\(\\?\\)").
+		WithArgs(nil, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// テスト実行
+	repo := NewTodoRepository(db)
+	todo := &domain.Todo{ID: 1, Title: "Parent Todo", Version: 2, DeletedAt: &deletedAt}
+	err := repo.Restore(todo, nil)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Nil(t, todo.DeletedAt)
+	assert.Equal(t, uint(3), todo.Version)
+
+	// モックの期待通りに呼ばれたか確認
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("未実行のクエリがあります: %v", err)
+	}
+}
+
+func TestPurge(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	// モックの設定 - タグとの関連と一緒に完全に削除する
+	before := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	mock.ExpectBegin()
+	mock.ExpectQuery("^SELECT `id` FROM `todos` WHERE deleted_at IS NOT NULL AND deleted_at < \\?").
+		WithArgs(before).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	mock.ExpectExec("^DELETE FROM todo_tags WHERE todo_id IN \\(\\?,\\?\\)").
+		WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("^DELETE FROM `todos` WHERE id IN \\(\\?,\\?\\)").
+		WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	// テスト実行
+	repo := NewTodoRepository(db)
+	purged, err := repo.Purge(before, nil)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+
+	// モックの期待通りに呼ばれたか確認
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("未実行のクエリがあります: %v", err)
+	}
+}
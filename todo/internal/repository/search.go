@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"gorm.io/gorm"
+)
+
+// searchTable は全文検索用のFTS5仮想テーブル（infrastructure.SetupFullTextSearch で作成する）
+const searchTable = "todos_fts"
+
+// trigramLength はtrigramトークナイザーで索引を使って検索できる最短の文字数
+const trigramLength = 3
+
+// snippetLength はスニペットとして一致箇所の前後から抜き出す文字数の目安
+const snippetLength = 32
+
+// Option はTodoRepositoryの任意設定
+type Option func(*TodoRepository)
+
+// WithFullTextSearch はFTS5による全文検索を有効にする
+// 有効にすると Create / Update / Delete で検索用のテーブルも同じトランザクションで更新する
+func WithFullTextSearch() Option {
+	return func(r *TodoRepository) {
+		r.fullText = true
+	}
+}
+
+// searchRow は検索用のクエリの結果
+type searchRow struct {
+	ID          uint
+	Rank        float64
+	Title       string
+	Description string
+}
+
+// Search はタイトルと説明をキーワードで検索し、関連度の高い順に最大 limit 件を返すメソッド
+// 空白で区切った複数のキーワードはすべてを含むものに一致する
+// 全文検索が無効な場合や、索引を使えない短いキーワードを含む場合は部分一致で検索する
+func (r *TodoRepository) Search(text string, limit int) ([]domain.SearchResult, error) {
+	terms := strings.Fields(text)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	var rows []searchRow
+	var err error
+	if r.fullText && allLongerThan(terms, trigramLength) {
+		rows, err = r.matchSearchIndex(terms, limit)
+	} else {
+		rows, err = r.likeSearch(terms, limit)
+	}
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	var todos []domain.Todo
+	if err := r.db.Preload("Tags").Where("id IN ?", ids).Find(&todos).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]domain.Todo, len(todos))
+	for _, todo := range todos {
+		byID[todo.ID] = todo
+	}
+
+	results := make([]domain.SearchResult, 0, len(rows))
+	for _, row := range rows {
+		todo, ok := byID[row.ID]
+		if !ok {
+			continue // 検索後に削除された
+		}
+		results = append(results, domain.SearchResult{Todo: todo, Rank: row.Rank, Title: row.Title, Snippet: row.Description})
+	}
+	return results, nil
+}
+
+// matchSearchIndex はFTS5の索引でキーワードを検索する
+// タイトルでの一致を説明での一致より重視して、bm25で並び替える
+func (r *TodoRepository) matchSearchIndex(terms []string, limit int) ([]searchRow, error) {
+	var rows []searchRow
+	err := r.db.Raw(
+		"SELECT rowid AS id, bm25("+searchTable+", 10.0, 1.0) AS rank,"+
+			" highlight("+searchTable+", 0, ?, ?) AS title,"+
+			" snippet("+searchTable+", 1, ?, ?, '…', ?) AS description"+
+			" FROM "+searchTable+" WHERE "+searchTable+" MATCH ? ORDER BY rank, rowid LIMIT ?",
+		domain.HighlightStart, domain.HighlightEnd,
+		domain.HighlightStart, domain.HighlightEnd, snippetLength,
+		matchExpression(terms), limit,
+	).Scan(&rows).Error
+	return rows, err
+}
+
+// likeSearch はキーワードを部分一致で検索する
+// 関連度は計算できないため、タイトルに一致したものを先にして新しい順に並べ、一致箇所の強調もここで行う
+func (r *TodoRepository) likeSearch(terms []string, limit int) ([]searchRow, error) {
+	table, id, condition := "todos", "id", keywordCondition
+	if r.fullText {
+		// 検索用のテーブルのLIKEはtrigramの索引で候補を絞り込める（2文字以下のキーワードを除く）
+		// 索引は大文字と小文字を区別しないため、列をLOWERで変換せずにそのまま比較する
+		table, id = searchTable, "rowid"
+		condition = "title LIKE ? ESCAPE '!' OR description LIKE ? ESCAPE '!'"
+	}
+
+	db := r.db.Table(table).Select(id + " AS id, title, description")
+	if !r.fullText {
+		db = db.Where(notDeleted) // 検索用のテーブルにはゴミ箱のTodoを登録しないため、todos を検索する場合のみ絞り込む
+	}
+	for _, term := range terms {
+		pattern := "%" + likeEscaper.Replace(term) + "%"
+		db = db.Where(condition, pattern, pattern)
+	}
+
+	var rows []searchRow
+	if err := db.Order(id + " DESC").Limit(limit).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	rankLikeRows(rows, terms)
+	return rows, nil
+}
+
+// rankLikeRows は部分一致で検索した結果に一致箇所の強調とスニペットを設定し、タイトルに一致したものを先に並べる
+func rankLikeRows(rows []searchRow, terms []string) {
+	for i := range rows {
+		if containsAny(rows[i].Title, terms) {
+			rows[i].Rank = -1
+		}
+		rows[i].Title = highlight(rows[i].Title, terms)
+		rows[i].Description = snippet(rows[i].Description, terms)
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Rank < rows[j].Rank })
+}
+
+// matchExpression はキーワードをFTS5のMATCH式に変換する
+// 演算子として解釈されないよう、各キーワードを二重引用符で囲んだフレーズにする
+func matchExpression(terms []string) string {
+	phrases := make([]string, len(terms))
+	for i, term := range terms {
+		phrases[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+	}
+	return strings.Join(phrases, " ")
+}
+
+// syncSearchIndex は検索用のテーブルのTodoの内容を最新にする
+func syncSearchIndex(tx *gorm.DB, todo *domain.Todo) error {
+	if err := tx.Exec("DELETE FROM "+searchTable+" WHERE rowid = ?", todo.ID).Error; err != nil {
+		return err
+	}
+	return tx.Exec("INSERT INTO "+searchTable+" (rowid, title, description) VALUES (?, ?, ?)", todo.ID, todo.Title, todo.Description).Error
+}
+
+// allLongerThan はすべてのキーワードが n 文字以上かどうかを判定する
+func allLongerThan(terms []string, n int) bool {
+	for _, term := range terms {
+		if utf8.RuneCountInString(term) < n {
+			return false
+		}
+	}
+	return true
+}
+
+// containsAny はいずれかのキーワードを含むかどうかを判定する（ASCIIの大文字と小文字は区別しない）
+func containsAny(s string, terms []string) bool {
+	lower := asciiLower(s)
+	for _, term := range terms {
+		if strings.Contains(lower, asciiLower(term)) {
+			return true
+		}
+	}
+	return false
+}
+
+// highlight はキーワードに一致する箇所を<mark>で囲む（ASCIIの大文字と小文字は区別しない）
+func highlight(s string, terms []string) string {
+	lower := asciiLower(s)
+	marked := make([]bool, len(s))
+	for _, term := range terms {
+		t := asciiLower(term)
+		for i := 0; ; {
+			j := strings.Index(lower[i:], t)
+			if j < 0 {
+				break
+			}
+			for k := i + j; k < i+j+len(t); k++ {
+				marked[k] = true
+			}
+			i += j + 1
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if marked[i] && (i == 0 || !marked[i-1]) {
+			b.WriteString(domain.HighlightStart)
+		}
+		b.WriteByte(s[i])
+		if marked[i] && (i == len(s)-1 || !marked[i+1]) {
+			b.WriteString(domain.HighlightEnd)
+		}
+	}
+	return b.String()
+}
+
+// snippet は最初に一致した箇所の前後を抜き出し、一致箇所を強調する
+func snippet(s string, terms []string) string {
+	lower := asciiLower(s)
+	first := -1
+	for _, term := range terms {
+		if i := strings.Index(lower, asciiLower(term)); i >= 0 && (first < 0 || i < first) {
+			first = i
+		}
+	}
+	if first < 0 {
+		first = 0
+	}
+
+	runes := []rune(s)
+	center := utf8.RuneCountInString(s[:first])
+	start := max(center-snippetLength/4, 0)
+	end := min(start+snippetLength, len(runes))
+
+	text := highlight(string(runes[start:end]), terms)
+	if start > 0 {
+		text = "…" + text
+	}
+	if end < len(runes) {
+		text += "…"
+	}
+	return text
+}
+
+// asciiLower はASCIIの英字のみを小文字にする
+// バイト数が変わらないため、変換後の文字列での位置を元の文字列にそのまま使える
+func asciiLower(s string) string {
+	return strings.Map(func(r rune) rune {
+		if 'A' <= r && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/infrastructure"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestCreateWithFullTextSearch(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	// モックの設定 - Todoの作成と検索用のテーブルの更新を同じトランザクションで行う
+	mock.ExpectBegin()
+	mock.ExpectExec("^INSERT INTO `todos`").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("^DELETE FROM todos_fts WHERE rowid = \\?").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("^INSERT INTO todos_fts \\(rowid, title, description\\) VALUES \\(\\?, \\?, \\?\\)").
+		WithArgs(1, "会議資料の作成", "来週の定例会議").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	repo := NewTodoRepository(db, WithFullTextSearch())
+
+	err := repo.Create(&domain.Todo{Title: "会議資料の作成", Description: "来週の定例会議"}, nil)
+	assert.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("未実行のクエリがあります: %v", err)
+	}
+}
+
+func TestDeleteWithFullTextSearch(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	// ゴミ箱のTodoは検索しないため、検索用のテーブルからは削除する
+	mock.ExpectBegin()
+	mock.ExpectQuery("^SELECT `id` FROM `todos` WHERE parent_id IN \\(\\?\\)").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectQuery("^SELECT `id` FROM `todos` WHERE parent_id IN \\(\\?\\)").
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec("^UPDATE `todos` SET `deleted_at`=\\?,`version`=version \\+ 1 WHERE id = \\? AND version = \\?").
+		WithArgs(sqlmock.AnyArg(), 1, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("^UPDATE `todos` SET `deleted_at`=\\?,`version`=version \\+ 1 WHERE id IN \\(\\?\\)").
+		WithArgs(sqlmock.AnyArg(), 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("^DELETE FROM todos_fts WHERE rowid IN \\(\\?,\\?\\)").
+		WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	repo := NewTodoRepository(db, WithFullTextSearch())
+
+	err := repo.Delete(&domain.Todo{ID: 1, Version: 1}, nil)
+	assert.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("未実行のクエリがあります: %v", err)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	testCases := []struct {
+		name          string
+		fullText      bool
+		text          string
+		expectedQuery string
+		expectedArgs  []interface{}
+		rows          *sqlmock.Rows
+		expected      []domain.SearchResult
+	}{
+		{
+			name:          "3文字以上のキーワードはFTS5の索引で検索",
+			fullText:      true,
+			text:          "会議資料 \"定例\"会",
+			expectedQuery: "^SELECT rowid AS id, bm25\\(todos_fts, 10.0, 1.0\\) AS rank, highlight\\(todos_fts, 0, \\?, \\?\\) AS title, snippet\\(todos_fts, 1, \\?, \\?, '…', \\?\\) AS description FROM todos_fts WHERE todos_fts MATCH \\? ORDER BY rank, rowid LIMIT \\?$",
+			expectedArgs:  []interface{}{"<mark>", "</mark>", "<mark>", "</mark>", 32, `"会議資料" """定例""会"`, 10},
+			rows: sqlmock.NewRows([]string{"id", "rank", "title", "description"}).
+				AddRow(2, -3.5, "<mark>会議資料</mark>", "<mark>\"定例\"会</mark>議"),
+			expected: []domain.SearchResult{
+				{Todo: domain.Todo{ID: 2, Title: "会議資料", Tags: []domain.Tag{}}, Rank: -3.5, Title: "<mark>会議資料</mark>", Snippet: "<mark>\"定例\"会</mark>議"},
+			},
+		},
+		{
+			name:          "短いキーワードは検索用のテーブルを部分一致で検索",
+			fullText:      true,
+			text:          "会議",
+			expectedQuery: "^SELECT rowid AS id, title, description FROM `todos_fts` WHERE title LIKE \\? ESCAPE '!' OR description LIKE \\? ESCAPE '!' ORDER BY rowid DESC LIMIT \\?$",
+			expectedArgs:  []interface{}{"%会議%", "%会議%", 10},
+			rows: sqlmock.NewRows([]string{"id", "title", "description"}).
+				AddRow(3, "資料作成", "会議で使う").
+				AddRow(2, "会議資料", ""),
+			expected: []domain.SearchResult{
+				{Todo: domain.Todo{ID: 2, Title: "会議資料", Tags: []domain.Tag{}}, Rank: -1, Title: "<mark>会議</mark>資料"},
+				{Todo: domain.Todo{ID: 3, Title: "資料作成", Tags: []domain.Tag{}}, Rank: 0, Title: "資料作成", Snippet: "<mark>会議</mark>で使う"},
+			},
+		},
+		{
+			name:          "全文検索が無効な場合はtodosテーブルを部分一致で検索",
+			fullText:      false,
+			text:          "100% 会議資料",
+			expectedQuery: "^SELECT id AS id, title, description FROM `todos` WHERE deleted_at IS NULL AND \\(LOWER\\(title\\) LIKE LOWER\\(\\?\\) ESCAPE '!' OR LOWER\\(description\\) LIKE LOWER\\(\\?\\) ESCAPE '!'\\) AND \\(LOWER\\(title\\) LIKE LOWER\\(\\?\\) ESCAPE '!' OR LOWER\\(description\\) LIKE LOWER\\(\\?\\) ESCAPE '!'\\) ORDER BY id DESC LIMIT \\?$",
+			expectedArgs:  []interface{}{"%100!%%", "%100!%%", "%会議資料%", "%会議資料%", 10},
+			rows:          sqlmock.NewRows([]string{"id", "title", "description"}),
+			expected:      nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, cleanup := setupMockDB(t)
+			defer cleanup()
+
+			args := make([]driver.Value, len(tc.expectedArgs))
+			for i, arg := range tc.expectedArgs {
+				args[i] = arg
+			}
+			mock.ExpectQuery(tc.expectedQuery).WithArgs(args...).WillReturnRows(tc.rows)
+			if tc.expected != nil {
+				todos := sqlmock.NewRows([]string{"id", "title"})
+				for _, r := range tc.expected {
+					todos.AddRow(r.Todo.ID, r.Todo.Title)
+				}
+				mock.ExpectQuery("^SELECT \\* FROM `todos` WHERE id IN").WillReturnRows(todos)
+				expectTagPreload(mock)
+			}
+
+			var opts []Option
+			if tc.fullText {
+				opts = append(opts, WithFullTextSearch())
+			}
+			repo := NewTodoRepository(db, opts...)
+
+			results, err := repo.Search(tc.text, 10)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, results)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("未実行のクエリがあります: %v", err)
+			}
+		})
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	testCases := []struct {
+		name     string
+		text     string
+		terms    []string
+		expected string
+	}{
+		{
+			name:     "短い文章はそのまま強調する",
+			text:     "Go言語の勉強会",
+			terms:    []string{"go", "勉強"},
+			expected: "<mark>Go</mark>言語の<mark>勉強</mark>会",
+		},
+		{
+			name:     "長い文章は一致箇所の前後を抜き出す",
+			text:     "あいうえおかきくけこさしすせそたちつてとなにぬねのはひふへほまみむめもやゆよらりるれろわをん",
+			terms:    []string{"なにぬ"},
+			expected: "…すせそたちつてと<mark>なにぬ</mark>ねのはひふへほまみむめもやゆよらりるれろわ…",
+		},
+		{
+			name:     "一致しない場合は先頭を抜き出す",
+			text:     "あいうえおかきくけこさしすせそたちつてとなにぬねのはひふへほまみむめもやゆよらりるれろわをん",
+			terms:    []string{"会議"},
+			expected: "あいうえおかきくけこさしすせそたちつてとなにぬねのはひふへほまみ…",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, snippet(tc.text, tc.terms))
+		})
+	}
+}
+
+// TestSearchWithSQLite は実際のSQLiteのFTS5で検索する（-tags sqlite_fts5 でビルドした場合のみ実行する）
+func TestSearchWithSQLite(t *testing.T) {
+	db := setupSQLiteDB(t)
+
+	// 全文検索なしで起動していた間に作成したTodoは、SetupFullTextSearch で検索用のテーブルを作り直すと検索できる
+	existing := &domain.Todo{Title: "請求書の送付", Description: "月末までに取引先へ送る"}
+	createTodos(t, NewTodoRepository(db), existing)
+	if err := infrastructure.SetupFullTextSearch(db); err != nil || !infrastructure.HasFullTextSearch(db) {
+		t.Skipf("FTS5を含まないビルドのためスキップします（-tags sqlite_fts5 を付けて実行してください）: %v", err)
+	}
+
+	repo := NewTodoRepository(db, WithFullTextSearch())
+	meeting := &domain.Todo{Title: "定例会議の準備", Description: "議題をまとめる"}
+	printing := &domain.Todo{Title: "資料の印刷", Description: "明日の定例会議で配る資料を印刷して、会議室に持っていく"}
+	report := &domain.Todo{Title: "Weekly Report", Description: "売上をまとめる"}
+	deleted := &domain.Todo{Title: "定例会議の議事録", Description: "先週の分"}
+	createTodos(t, repo, meeting, printing, report, deleted)
+	require.NoError(t, repo.Delete(deleted, nil))
+	report.Title = "Monthly Report"
+	require.NoError(t, repo.Update(report, nil))
+
+	t.Run("作り直した索引で検索できる", func(t *testing.T) {
+		results, err := repo.Search("請求書", 10)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, existing.ID, results[0].Todo.ID)
+	})
+
+	t.Run("タイトルに一致したものをbm25で上位にし、一致箇所を強調する", func(t *testing.T) {
+		results, err := repo.Search("定例会議", 10)
+		require.NoError(t, err)
+		require.Len(t, results, 2) // ゴミ箱のTodoは検索しない
+		assert.Equal(t, meeting.ID, results[0].Todo.ID)
+		assert.Equal(t, printing.ID, results[1].Todo.ID)
+		assert.Less(t, results[0].Rank, results[1].Rank)
+		assert.Less(t, results[1].Rank, 0.0)
+		assert.Equal(t, "<mark>定例会議</mark>の準備", results[0].Title)
+		assert.Equal(t, "資料の印刷", results[1].Title)
+		assert.Contains(t, results[1].Snippet, "<mark>定例会議</mark>")
+	})
+
+	t.Run("trigramで単語の途中にも大文字と小文字を区別せずに一致する", func(t *testing.T) {
+		results, err := repo.Search("eport nthl", 10)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, report.ID, results[0].Todo.ID)
+		assert.Equal(t, "Mo<mark>nthl</mark>y R<mark>eport</mark>", results[0].Title)
+
+		results, err = repo.Search("weekly", 10)
+		require.NoError(t, err)
+		assert.Empty(t, results) // 更新前のタイトルは索引に残らない
+	})
+
+	t.Run("2文字以下のキーワードは部分一致で検索する", func(t *testing.T) {
+		results, err := repo.Search("会議", 10)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, meeting.ID, results[0].Todo.ID)
+		assert.Equal(t, float64(-1), results[0].Rank)
+		assert.Equal(t, printing.ID, results[1].Todo.ID)
+	})
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"gorm.io/gorm"
+)
+
+// TagRepository はTagエンティティとタスクへの付与状態のデータアクセスを担当する構造体
+type TagRepository struct {
+	db *gorm.DB
+}
+
+// TagRepositoryInterface はTagRepositoryのインターフェース
+type TagRepositoryInterface interface {
+	FindAll() ([]domain.Tag, error)
+	FindByID(id string) (*domain.Tag, error)
+	FindByName(name string) (*domain.Tag, error)
+	Create(tag *domain.Tag) error
+	Update(tag *domain.Tag) error
+	Delete(tag *domain.Tag) error
+	Attach(todo *domain.Todo, tag *domain.Tag) error
+	Detach(todo *domain.Todo, tag *domain.Tag) error
+	WithContext(ctx context.Context) TagRepositoryInterface
+}
+
+// NewTagRepository はTagRepositoryのコンストラクタ
+func NewTagRepository(db *gorm.DB) TagRepositoryInterface {
+	return &TagRepository{db: db}
+}
+
+// FindAll はすべてのTagを名前順に取得するメソッド
+func (r *TagRepository) FindAll() ([]domain.Tag, error) {
+	var tags []domain.Tag
+	result := r.db.Order("name").Find(&tags)
+	return tags, result.Error
+}
+
+// FindByID は指定されたIDのTagを取得するメソッド
+func (r *TagRepository) FindByID(id string) (*domain.Tag, error) {
+	var tag domain.Tag
+	result := r.db.First(&tag, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil // レコードが見つからない場合は特別扱い
+		}
+		return nil, result.Error
+	}
+	return &tag, nil
+}
+
+// FindByName は指定された名前のTagを取得するメソッド
+func (r *TagRepository) FindByName(name string) (*domain.Tag, error) {
+	var tag domain.Tag
+	result := r.db.Where("name = ?", name).First(&tag)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil // レコードが見つからない場合は特別扱い
+		}
+		return nil, result.Error
+	}
+	return &tag, nil
+}
+
+// Create は新しいTagを作成するメソッド
+func (r *TagRepository) Create(tag *domain.Tag) error {
+	return r.db.Create(tag).Error
+}
+
+// Update は指定されたTagを更新するメソッド
+func (r *TagRepository) Update(tag *domain.Tag) error {
+	return r.db.Save(tag).Error
+}
+
+// Delete は指定されたTagを削除するメソッド
+// タスクとの関連（todo_tags）も同じトランザクションで削除する
+func (r *TagRepository) Delete(tag *domain.Tag) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM todo_tags WHERE tag_id = ?", tag.ID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(tag).Error
+	})
+}
+
+// Attach はTodoにTagを付与するメソッド
+// 既に付与されている場合は関連を追加しない。Todoの内容が変わるため、バージョンは1つ進める
+func (r *TagRepository) Attach(todo *domain.Todo, tag *domain.Tag) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(todo).Association("Tags").Append(tag); err != nil {
+			return err
+		}
+		return incrementVersion(tx, todo)
+	})
+}
+
+// Detach はTodoからTagを外すメソッド
+// Todoの内容が変わるため、バージョンは1つ進める
+func (r *TagRepository) Detach(todo *domain.Todo, tag *domain.Tag) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(todo).Association("Tags").Delete(tag); err != nil {
+			return err
+		}
+		return incrementVersion(tx, todo)
+	})
+}
+
+// WithContext は ctx が終了した時点で処理を中断するリポジトリを返すメソッド
+func (r *TagRepository) WithContext(ctx context.Context) TagRepositoryInterface {
+	return &TagRepository{db: r.db.WithContext(ctx)}
+}
+
+// incrementVersion はTodoのバージョンを1つ進める（タグの付け外しはバージョンを比較せずに行う）
+func incrementVersion(tx *gorm.DB, todo *domain.Todo) error {
+	err := tx.Model(&domain.Todo{}).Where("id = ?", todo.ID).UpdateColumn("version", gorm.Expr("version + 1")).Error
+	if err != nil {
+		return err
+	}
+	todo.Version++
+	return nil
+}
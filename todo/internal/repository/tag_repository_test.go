@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/stretchr/testify/assert"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestTagFindAll(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	// モックの設定
+	rows := sqlmock.NewRows([]string{"id", "name"}).
+		AddRow(2, "急ぎ").
+		AddRow(1, "仕事")
+
+	mock.ExpectQuery("^SELECT \\* FROM `tags` ORDER BY name").WillReturnRows(rows)
+
+	// テスト実行
+	repo := NewTagRepository(db)
+	tags, err := repo.FindAll()
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Tag{{ID: 2, Name: "急ぎ"}, {ID: 1, Name: "仕事"}}, tags)
+
+	// モックの期待通りに呼ばれたか確認
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("未実行のクエリがあります: %v", err)
+	}
+}
+
+func TestTagFindByName(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	// モックの設定 - 存在する名前
+	mock.ExpectQuery("^SELECT \\* FROM `tags` WHERE name = \\? ORDER BY `tags`.`id` LIMIT \\?").
+		WithArgs("仕事", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "仕事"))
+
+	// モックの設定 - 存在しない名前
+	mock.ExpectQuery("^SELECT \\* FROM `tags` WHERE name = \\? ORDER BY `tags`.`id` LIMIT \\?").
+		WithArgs("趣味", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+	repo := NewTagRepository(db)
+
+	// テスト実行 - 存在する名前
+	tag, err := repo.FindByName("仕事")
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.Tag{ID: 1, Name: "仕事"}, tag)
+
+	// テスト実行 - 存在しない名前
+	tag, err = repo.FindByName("趣味")
+	assert.NoError(t, err) // エラーではなくnilを返す設計
+	assert.Nil(t, tag)
+
+	// モックの期待通りに呼ばれたか確認
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("未実行のクエリがあります: %v", err)
+	}
+}
+
+func TestTagDelete(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	// モックの設定
+	mock.ExpectBegin()
+	mock.ExpectExec("^DELETE FROM todo_tags WHERE tag_id = \\?").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("^DELETE FROM `tags` WHERE `tags`.`id` = \\?").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// テスト実行
+	repo := NewTagRepository(db)
+	err := repo.Delete(&domain.Tag{ID: 1, Name: "仕事"})
+
+	// 検証
+	assert.NoError(t, err)
+
+	// モックの期待通りに呼ばれたか確認
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("未実行のクエリがあります: %v", err)
+	}
+}
+
+func TestTagAttachAndDetach(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	// モックの設定 - 付与
+	mock.ExpectBegin()
+	mock.ExpectExec("^INSERT INTO `tags`").
+		WillReturnResult(sqlmock.NewResult(1, 0))
+	mock.ExpectExec("^INSERT INTO `todo_tags` \\(`todo_id`,`tag_id`\\) VALUES \\(\\?,\\?\\)").
+		WithArgs(1, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("^UPDATE `todos` SET `version`=version \\+ 1 WHERE id = \\?").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// モックの設定 - 解除
+	mock.ExpectBegin()
+	mock.ExpectExec("^DELETE FROM `todo_tags` WHERE `todo_tags`.`todo_id` = \\? AND `todo_tags`.`tag_id` = \\?").
+		WithArgs(1, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("^UPDATE `todos` SET `version`=version \\+ 1 WHERE id = \\?").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := NewTagRepository(db)
+	todo := &domain.Todo{ID: 1, Title: "Test Todo", Version: 1}
+	tag := &domain.Tag{ID: 1, Name: "仕事"}
+
+	// テスト実行 - 付け外しのたびにバージョンが進む
+	assert.NoError(t, repo.Attach(todo, tag))
+	assert.NoError(t, repo.Detach(todo, tag))
+	assert.Equal(t, uint(3), todo.Version)
+
+	// モックの期待通りに呼ばれたか確認
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("未実行のクエリがあります: %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"crypto/rand"
+	"net/http"
+	"time"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/logger"
+)
+
+// RequestIDHeader はリクエストを識別するIDを受け取り、レスポンスで返すヘッダー
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength はクライアントが指定できるリクエストIDの最大の長さ
+const maxRequestIDLength = 128
+
+// withRequestID はリクエストIDを context に保存したリクエストを返し、同じIDをレスポンスのヘッダーに設定する
+// クライアントが X-Request-ID を指定した場合はそれを使い、指定しなかった場合や使えない文字を含む場合は新しく作成する
+func withRequestID(w http.ResponseWriter, r *http.Request) *http.Request {
+	id := r.Header.Get(RequestIDHeader)
+	if !validRequestID(id) {
+		id = rand.Text()
+	}
+	w.Header().Set(RequestIDHeader, id)
+	return r.WithContext(logger.ContextWithRequestID(r.Context(), id))
+}
+
+// validRequestID はクライアントが指定したリクエストIDをそのまま使えるかを判定する
+// ログの行を偽装できないよう、英数字と一部の記号だけを受け付ける
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range []byte(id) {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':', c == '/', c == '+', c == '=':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// log はリクエストIDを付けてログを出力するLoggerを返す
+func (s *TodoServer) log(r *http.Request) *logger.Logger {
+	return s.logger.WithContext(r.Context())
+}
+
+// accessLog はリクエストごとにメソッド・ルート・ステータスコード・レスポンスのサイズ・処理時間・接続元を1行で出力するミドルウェア
+func (s *TodoServer) accessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w}
+		start, panicked := time.Now(), true
+		defer func() {
+			status := rec.status
+			switch {
+			case panicked:
+				status = http.StatusInternalServerError
+			case status == 0:
+				status = http.StatusOK
+			}
+			s.log(r).Infow("access", "method", r.Method, "route", routeTemplate(r), "status", status,
+				"bytes", rec.bytes, "duration", time.Since(start), "remote", r.RemoteAddr)
+		}()
+		next.ServeHTTP(rec, r)
+		panicked = false
+	})
+}
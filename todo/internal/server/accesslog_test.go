@@ -0,0 +1,96 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestID(t *testing.T) {
+	testCases := []struct {
+		name       string
+		requestID  string
+		expectSame bool
+	}{
+		{name: "正常系: 指定したIDを返す", requestID: "0f8fad5b-d9cb-469f-a165-70867728950e", expectSame: true},
+		{name: "正常系: 指定がない場合は作成する"},
+		{name: "異常系: 使えない文字を含む場合は作成し直す", requestID: "abc def"},
+		{name: "異常系: 長すぎる場合は作成し直す", requestID: strings.Repeat("a", maxRequestIDLength+1)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := NewTodoServer(new(MockTodoUseCase))
+			var got string
+			server.router.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+				got = logger.RequestIDFromContext(r.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/echo", nil)
+			if tc.requestID != "" {
+				req.Header.Set(RequestIDHeader, tc.requestID)
+			}
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			// ハンドラーの context とレスポンスのヘッダーに同じIDを設定する
+			id := w.Header().Get(RequestIDHeader)
+			assert.NotEmpty(t, id)
+			assert.Equal(t, id, got)
+			if tc.expectSame {
+				assert.Equal(t, tc.requestID, id)
+			} else {
+				assert.NotEqual(t, tc.requestID, id)
+			}
+		})
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	mockUseCase := new(MockTodoUseCase)
+	mockUseCase.On("DeleteTodoByID", "1", uint(0)).Return(nil)
+	server := NewTodoServer(mockUseCase)
+	var out bytes.Buffer
+	server.logger = logger.New(&out, &out, logger.WithFormat(logger.FormatJSON))
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodDelete, "/todos/1", nil),
+		httptest.NewRequest(http.MethodGet, "/healthz", nil),
+		httptest.NewRequest(http.MethodGet, "/unknown", nil),
+	} {
+		req.Header.Set(RequestIDHeader, "req-"+strings.TrimPrefix(req.URL.Path, "/"))
+		req.RemoteAddr = "192.0.2.1:1234"
+		server.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	var access, received []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry), line)
+		switch entry["msg"] {
+		case "access":
+			assert.Greater(t, entry["duration"], 0.0)
+			delete(entry, "time")
+			delete(entry, "duration")
+			access = append(access, entry)
+		case "DELETE /todos/{id} リクエストを受信しました":
+			received = append(received, entry)
+		}
+	}
+	assert.Equal(t, []map[string]interface{}{
+		{"level": "INFO", "msg": "access", "request_id": "req-todos/1", "method": "DELETE", "route": "/todos/{id}", "status": 204.0, "bytes": 0.0, "remote": "192.0.2.1:1234"},
+		{"level": "INFO", "msg": "access", "request_id": "req-healthz", "method": "GET", "route": "/healthz", "status": 200.0, "bytes": 16.0, "remote": "192.0.2.1:1234"},
+		{"level": "INFO", "msg": "access", "request_id": "req-unknown", "method": "GET", "route": "unmatched", "status": 404.0, "bytes": 19.0, "remote": "192.0.2.1:1234"},
+	}, access)
+
+	// ハンドラーが出力したログにも同じリクエストIDを付ける
+	require.Len(t, received, 1)
+	assert.Equal(t, "req-todos/1", received[0]["request_id"])
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/logger"
+)
+
+// WithAdminToken は管理用エンドポイント（/admin 以下）で要求するトークンを設定する
+// リクエストには Authorization: Bearer <token> を付ける。設定しない場合は管理用エンドポイントを登録しない
+func WithAdminToken(token string) Option {
+	return func(s *TodoServer) {
+		s.adminToken = token
+	}
+}
+
+// LogLevelRequest はログレベルを変更するためのリクエスト
+type LogLevelRequest struct {
+	Component string `json:"component,omitempty"` // server, usecase, repository, gui, client のいずれか（空の場合はルートのレベル）
+	Level     string `json:"level"`               // debug, info, warn, error のいずれか
+}
+
+// LogLevelResponse は変更した後のログレベル
+type LogLevelResponse struct {
+	Level      string            `json:"level"`      // ルートのレベル
+	Components map[string]string `json:"components"` // コンポーネントごとのレベル
+}
+
+// adminRoutes は管理用のルーティングを設定する
+func (s *TodoServer) adminRoutes() {
+	s.router.Handle("/admin/loglevel", s.requireAdmin(http.HandlerFunc(s.setLogLevel))).Methods("PUT")
+}
+
+// requireAdmin は Authorization ヘッダーのトークンが WithAdminToken で設定したものと一致しない場合に 401 を返すミドルウェア
+func (s *TodoServer) requireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) != 1 {
+			s.log(r).Warnw("管理用エンドポイントの認証に失敗しました", "remote", r.RemoteAddr)
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			http.Error(w, "認証に失敗しました", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// setLogLevel はコンポーネントのログレベルを変更する（再起動せずに一時的に DEBUG を出力する場合など）
+func (s *TodoServer) setLogLevel(w http.ResponseWriter, r *http.Request) {
+	var req LogLevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.log(r).Errorf("リクエストボディの解析に失敗しました: %v", err)
+		http.Error(w, "リクエストボディの解析に失敗しました", http.StatusBadRequest)
+		return
+	}
+	if req.Level == "" {
+		s.writeError(w, r, errors.NewInvalidInputError("levelを指定してください"), "")
+		return
+	}
+	level, err := logger.ParseLevel(req.Level)
+	if err != nil {
+		s.writeError(w, r, errors.NewInvalidInputError(err.Error()), "")
+		return
+	}
+	if err := s.logger.SetComponentLevel(req.Component, level); err != nil {
+		s.writeError(w, r, errors.NewInvalidInputError(err.Error()), "")
+		return
+	}
+	s.log(r).Warnw("ログレベルを変更しました", "target", req.Component, "log_level", logger.LevelName(level))
+
+	root, components := s.logger.Levels()
+	resp := LogLevelResponse{Level: logger.LevelName(root), Components: make(map[string]string, len(components))}
+	for name, level := range components {
+		resp.Components[name] = logger.LevelName(level)
+	}
+	s.writeJSON(w, r, http.StatusOK, resp)
+}
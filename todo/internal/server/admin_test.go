@@ -0,0 +1,112 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetLogLevel(t *testing.T) {
+	testCases := []struct {
+		name           string
+		token          string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "正常系: コンポーネントのレベルを変更する",
+			token:          "secret",
+			body:           `{"component": "server", "level": "debug"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"level": "info", "components": {"server": "debug", "usecase": "info", "repository": "info", "gui": "info", "client": "info"}}`,
+		},
+		{
+			name:           "正常系: コンポーネントを指定しない場合はルートのレベルを変更する",
+			token:          "secret",
+			body:           `{"level": "warn"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"level": "warn", "components": {"server": "warn", "usecase": "warn", "repository": "warn", "gui": "warn", "client": "warn"}}`,
+		},
+		{
+			name:           "異常系: トークンが一致しない",
+			token:          "wrong",
+			body:           `{"component": "server", "level": "debug"}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "異常系: トークンがない",
+			body:           `{"component": "server", "level": "debug"}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "異常系: 存在しないコンポーネント",
+			token:          "secret",
+			body:           `{"component": "scheduler", "level": "debug"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "異常系: 存在しないレベル",
+			token:          "secret",
+			body:           `{"component": "server", "level": "verbose"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "異常系: レベルを指定していない",
+			token:          "secret",
+			body:           `{"component": "server"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := NewTodoServer(new(MockTodoUseCase), WithAdminToken("secret"))
+			server.logger = logger.New(io.Discard, io.Discard).Named(logger.ComponentServer)
+
+			req := httptest.NewRequest(http.MethodPut, "/admin/loglevel", strings.NewReader(tc.body))
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestSetLogLevelOutput(t *testing.T) {
+	server := NewTodoServer(new(MockTodoUseCase), WithAdminToken("secret"))
+	var out bytes.Buffer
+	server.logger = logger.New(&out, &out, logger.WithFormat(logger.FormatJSON)).Named(logger.ComponentServer)
+
+	// 変更する前は DEBUG のログを出力しない
+	server.log(httptest.NewRequest(http.MethodGet, "/", nil)).Debug("変更前")
+	req := httptest.NewRequest(http.MethodPut, "/admin/loglevel", strings.NewReader(`{"component": "server", "level": "debug"}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	server.ServeHTTP(httptest.NewRecorder(), req)
+	server.log(httptest.NewRequest(http.MethodGet, "/", nil)).Debug("変更後")
+
+	assert.NotContains(t, out.String(), "変更前")
+	assert.Contains(t, out.String(), `"msg":"変更後"`)
+}
+
+func TestAdminRoutesDisabled(t *testing.T) {
+	// トークンを設定しない場合は管理用エンドポイントを登録しない
+	server := NewTodoServer(new(MockTodoUseCase))
+	req := httptest.NewRequest(http.MethodPut, "/admin/loglevel", strings.NewReader(`{"level": "debug"}`))
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/usecase"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+)
+
+// ActorHeader は変更した利用者を指定するリクエストヘッダー（監査ログに記録する）
+const ActorHeader = "X-User"
+
+// todos はリクエストの利用者を監査ログの操作者とし、リクエストの context を設定したユースケースを返す
+// ハンドラーは s.useCase ではなくこのメソッドの戻り値を使い、リクエストが終了したら処理を中断する
+func (s *TodoServer) todos(r *http.Request) usecase.TodoUseCaseInterface {
+	return s.useCase.WithActor(strings.TrimSpace(r.Header.Get(ActorHeader))).WithContext(r.Context())
+}
+
+// getHistory は指定されたIDのTODOの変更履歴を古い順に返す
+// ゴミ箱にあるTODOや完全に削除したTODOの履歴も返す
+func (s *TodoServer) getHistory(w http.ResponseWriter, r *http.Request) {
+	s.log(r).Info("GET /todos/{id}/history リクエストを受信しました")
+	id := mux.Vars(r)["id"]
+
+	entries, err := s.todos(r).GetHistory(id)
+	if err != nil {
+		s.writeError(w, r, err, "Todoの変更履歴の取得中にエラーが発生しました")
+		return
+	}
+
+	s.writeJSON(w, r, http.StatusOK, entries)
+	s.log(r).Infof("ID %s のTodoの変更履歴を %d 件返却しました", id, len(entries))
+}
+
+// getAuditLog はすべてのTODOの監査ログのうち、クエリパラメータの条件に一致するものを古い順に返す
+func (s *TodoServer) getAuditLog(w http.ResponseWriter, r *http.Request) {
+	s.log(r).Info("GET /audit リクエストを受信しました")
+
+	query, err := parseAuditQuery(r.URL.Query())
+	if err != nil {
+		s.writeError(w, r, err, "クエリパラメータの解析に失敗しました")
+		return
+	}
+
+	entries, err := s.todos(r).GetAuditLog(query)
+	if err != nil {
+		s.writeError(w, r, err, "監査ログの取得中にエラーが発生しました")
+		return
+	}
+
+	s.writeJSON(w, r, http.StatusOK, entries)
+	s.log(r).Infof("%d 件の監査ログを返却しました", len(entries))
+}
+
+// parseAuditQuery はクエリパラメータから監査ログの絞り込み条件を作成する
+// since と until はRFC 3339形式の日時で、since 以降 until より前の監査ログに絞り込む
+func parseAuditQuery(params url.Values) (domain.AuditQuery, error) {
+	query := domain.AuditQuery{Actor: strings.TrimSpace(params.Get("actor"))}
+
+	if v := params.Get("todo_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 0)
+		if err != nil {
+			return domain.AuditQuery{}, errors.NewInvalidInputError("todo_idには整数を指定してください", err)
+		}
+		todoID := uint(id)
+		query.TodoID = &todoID
+	}
+
+	var err error
+	if query.Since, err = parseTimeParam(params, "since"); err != nil {
+		return domain.AuditQuery{}, err
+	}
+	if query.Until, err = parseTimeParam(params, "until"); err != nil {
+		return domain.AuditQuery{}, err
+	}
+
+	if v := params.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			return domain.AuditQuery{}, errors.NewInvalidInputError("limitには1以上の整数を指定してください")
+		}
+		query.Limit = limit
+	}
+	return query, nil
+}
+
+// parseTimeParam はRFC 3339形式の日時のクエリパラメータを解析する（指定がない場合はnilを返す）
+func parseTimeParam(params url.Values, name string) (*time.Time, error) {
+	v := params.Get(name)
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, errors.NewInvalidInputError(name+"にはRFC 3339形式の日時を指定してください（例: 2025-04-01T09:00:00+09:00）", err)
+	}
+	return &t, nil
+}
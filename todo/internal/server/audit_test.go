@@ -0,0 +1,154 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetHistory(t *testing.T) {
+	createdAt := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	history := []domain.AuditEntry{
+		{ID: 1, TodoID: 1, Action: domain.AuditCreate, Actor: "alice", Version: 1, CreatedAt: createdAt,
+			Changes: map[string]domain.FieldChange{"title": {Before: json.RawMessage("null"), After: json.RawMessage(`"Test Todo"`)}}},
+		{ID: 2, TodoID: 1, Action: domain.AuditDelete, Actor: "bob", Version: 2, CreatedAt: createdAt.Add(time.Hour)},
+	}
+
+	testCases := []struct {
+		name           string
+		entries        []domain.AuditEntry
+		err            error
+		expectedStatus int
+	}{
+		{
+			name:           "正常系",
+			entries:        history,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "存在しないTodo",
+			err:            errors.NewNotFoundError("ID 1 のTodoが見つかりません"),
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// モックの設定
+			mockUseCase := new(MockTodoUseCase)
+			mockUseCase.On("GetHistory", "1").Return(tc.entries, tc.err)
+			server := NewTodoServer(mockUseCase)
+
+			// リクエスト実行（ルーター経由）
+			req := httptest.NewRequest(http.MethodGet, "/todos/1/history", nil)
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			// 検証
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.err == nil {
+				var entries []domain.AuditEntry
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+				assert.Equal(t, tc.entries, entries)
+			}
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetAuditLog(t *testing.T) {
+	since := time.Date(2025, 4, 1, 0, 0, 0, 0, time.FixedZone("JST", 9*60*60))
+	until := since.Add(24 * time.Hour)
+	todoID := uint(3)
+
+	testCases := []struct {
+		name           string
+		query          string
+		mockBehavior   func(*MockTodoUseCase)
+		expectedStatus int
+	}{
+		{
+			name:  "正常系: 条件なし",
+			query: "",
+			mockBehavior: func(m *MockTodoUseCase) {
+				m.On("GetAuditLog", domain.AuditQuery{}).Return([]domain.AuditEntry{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "正常系: すべての条件を指定",
+			query: "?since=2025-04-01T00:00:00%2B09:00&until=2025-04-02T00:00:00%2B09:00&actor=alice&todo_id=3&limit=10",
+			mockBehavior: func(m *MockTodoUseCase) {
+				m.On("GetAuditLog", mock.MatchedBy(func(q domain.AuditQuery) bool {
+					return q.Since.Equal(since) && q.Until.Equal(until) && q.Actor == "alice" &&
+						*q.TodoID == todoID && q.Limit == 10
+				})).Return([]domain.AuditEntry{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "異常系: 日時の形式が不正",
+			query:          "?since=2025-04-01",
+			mockBehavior:   func(m *MockTodoUseCase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "異常系: todo_idが数値でない",
+			query:          "?todo_id=abc",
+			mockBehavior:   func(m *MockTodoUseCase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "異常系: 期間が不正",
+			query: "?since=2025-04-02T00:00:00Z&until=2025-04-01T00:00:00Z",
+			mockBehavior: func(m *MockTodoUseCase) {
+				m.On("GetAuditLog", mock.Anything).Return(nil, errors.NewInvalidInputError("since は until より前の日時を指定してください"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// モックの設定
+			mockUseCase := new(MockTodoUseCase)
+			tc.mockBehavior(mockUseCase)
+			server := NewTodoServer(mockUseCase)
+
+			// リクエスト実行（ルーター経由）
+			req := httptest.NewRequest(http.MethodGet, "/audit"+tc.query, nil)
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			// 検証
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWriteHandlersUseActor(t *testing.T) {
+	// モックの設定
+	mockUseCase := new(MockTodoUseCase)
+	mockUseCase.On("CreateTodo", mock.Anything).Return(domain.Todo{ID: 1, Title: "Test Todo", Version: 1}, nil)
+	server := NewTodoServer(mockUseCase)
+
+	// リクエスト実行（ルーター経由）
+	req := httptest.NewRequest(http.MethodPost, "/todos", strings.NewReader(`{"title":"Test Todo"}`))
+	req.Header.Set(ActorHeader, " alice ")
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	// 検証
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "alice", mockUseCase.actor)
+	mockUseCase.AssertExpectations(t)
+}
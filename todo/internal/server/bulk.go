@@ -0,0 +1,148 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/usecase"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+)
+
+// BulkRequest は複数のTODOをまとめて作成・更新・削除するためのリクエスト
+type BulkRequest struct {
+	Mode       string                 `json:"mode,omitempty"` // atomic（既定）または best_effort
+	Operations []BulkOperationRequest `json:"operations"`
+}
+
+// BulkOperationRequest はまとめて実行する1つの操作
+type BulkOperationRequest struct {
+	Action  string             `json:"action"`            // create, update, delete のいずれか
+	ID      uint               `json:"id,omitempty"`      // 更新・削除するTODOのID
+	Version uint               `json:"version,omitempty"` // 指定した場合は、TODOがこのバージョンのときだけ更新・削除する（If-Match と同じ）
+	Todo    *CreateTodoRequest `json:"todo,omitempty"`    // 作成する内容（create の場合）
+	Patch   json.RawMessage    `json:"patch,omitempty"`   // 変更する項目（update の場合。PATCH /todos/{id} と同じ JSON Merge Patch）
+}
+
+// BulkResponse はまとめて実行した操作の結果
+type BulkResponse struct {
+	Committed bool                 `json:"committed"` // 変更を保存したか
+	Results   []BulkResultResponse `json:"results"`   // 操作ごとの結果（指定した操作と同じ順）
+}
+
+// BulkResultResponse は1つの操作の結果
+type BulkResultResponse struct {
+	Status int          `json:"status"`          // 操作を個別のエンドポイントで実行した場合のステータスコード
+	Todo   *domain.Todo `json:"todo,omitempty"`  // 作成・更新したTODO
+	Error  string       `json:"error,omitempty"` // 失敗した場合のエラーメッセージ
+}
+
+// bulkTodos は複数のTODOの作成・更新・削除を1つのトランザクションでまとめて実行し、操作ごとの結果を返す
+// atomic で失敗した操作があった場合は何も保存せず、失敗した操作のステータスコードで結果を返す
+func (s *TodoServer) bulkTodos(w http.ResponseWriter, r *http.Request) {
+	s.log(r).Info("POST /todos/bulk リクエストを受信しました")
+
+	var req BulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.log(r).Errorf("リクエストボディの解析に失敗しました: %v", err)
+		http.Error(w, "リクエストボディの解析に失敗しました", http.StatusBadRequest)
+		return
+	}
+
+	mode, err := usecase.ParseBulkMode(req.Mode)
+	if err != nil {
+		s.writeError(w, r, err, "リクエストボディの解析に失敗しました")
+		return
+	}
+	ops := make([]usecase.BulkOperation, len(req.Operations))
+	for i, op := range req.Operations {
+		if ops[i], err = bulkOperation(op); err != nil {
+			s.writeError(w, r, errors.NewInvalidInputError(fmt.Sprintf("%d 番目の操作: %v", i+1, err)), "リクエストボディの解析に失敗しました")
+			return
+		}
+	}
+
+	result, err := s.todos(r).BulkTodos(ops, mode)
+	if err != nil {
+		s.writeError(w, r, err, "Todoの一括操作中にエラーが発生しました")
+		return
+	}
+
+	status := http.StatusOK
+	resp := BulkResponse{Committed: result.Committed, Results: make([]BulkResultResponse, len(result.Items))}
+	for i, item := range result.Items {
+		resp.Results[i] = s.bulkResult(r, ops[i].Action, item)
+		if !result.Committed && item.Err != nil && !stderrors.Is(item.Err, usecase.ErrBulkAborted) {
+			status = resp.Results[i].Status
+		}
+	}
+	s.writeJSON(w, r, status, resp)
+	s.log(r).Infof("%d 件の操作をまとめて実行しました: mode=%s, committed=%t", len(ops), mode, result.Committed)
+}
+
+// bulkOperation はリクエストの操作をユースケースの操作に変換する
+func bulkOperation(req BulkOperationRequest) (usecase.BulkOperation, error) {
+	op := usecase.BulkOperation{Action: usecase.BulkAction(req.Action), Version: req.Version}
+	switch op.Action {
+	case usecase.BulkCreate:
+		if req.Todo == nil {
+			return op, fmt.Errorf("create には todo を指定してください")
+		}
+		op.Create = usecase.CreateTodoInput{
+			ParentID:    req.Todo.ParentID,
+			Title:       req.Todo.Title,
+			Description: req.Todo.Description,
+			Priority:    req.Todo.Priority,
+			StartAt:     req.Todo.StartAt,
+			DueAt:       req.Todo.DueAt,
+			Recurrence:  req.Todo.Recurrence,
+		}
+		return op, nil
+	case usecase.BulkUpdate, usecase.BulkDelete:
+		if req.ID == 0 {
+			return op, fmt.Errorf("%s には id を指定してください", req.Action)
+		}
+		op.ID = strconv.FormatUint(uint64(req.ID), 10)
+	default:
+		return op, fmt.Errorf("action には create, update, delete のいずれかを指定してください: %s", req.Action)
+	}
+
+	if op.Action == usecase.BulkUpdate {
+		patch, err := parseMergePatch(bytes.NewReader(req.Patch))
+		if err != nil {
+			return op, fmt.Errorf("patch: %w", err)
+		}
+		op.Patch = patch
+	}
+	return op, nil
+}
+
+// bulkResult は1つの操作の結果を、個別のエンドポイントで実行した場合のステータスコードとともに返す
+func (s *TodoServer) bulkResult(r *http.Request, action usecase.BulkAction, item usecase.BulkItemResult) BulkResultResponse {
+	switch {
+	case item.Err == nil && action == usecase.BulkCreate:
+		return BulkResultResponse{Status: http.StatusCreated, Todo: item.Todo}
+	case item.Err == nil && action == usecase.BulkDelete:
+		return BulkResultResponse{Status: http.StatusNoContent}
+	case item.Err == nil:
+		return BulkResultResponse{Status: http.StatusOK, Todo: item.Todo}
+	case stderrors.Is(item.Err, usecase.ErrBulkAborted):
+		return BulkResultResponse{Status: http.StatusFailedDependency, Error: item.Err.Error()}
+	case errors.IsInvalidInput(item.Err):
+		return BulkResultResponse{Status: http.StatusBadRequest, Error: item.Err.Error()}
+	case errors.IsNotFound(item.Err):
+		return BulkResultResponse{Status: http.StatusNotFound, Error: item.Err.Error()}
+	case errors.IsConflict(item.Err):
+		return BulkResultResponse{Status: conflictStatus(item.Err), Error: item.Err.Error()}
+	case stderrors.Is(item.Err, context.DeadlineExceeded), stderrors.Is(item.Err, context.Canceled):
+		s.log(r).Errorf("Todoの一括操作を中断しました: %v", item.Err)
+		return BulkResultResponse{Status: http.StatusServiceUnavailable, Error: "時間内に処理を完了できませんでした"}
+	}
+	s.log(r).Errorf("Todoの一括操作中にエラーが発生しました: %v", item.Err)
+	return BulkResultResponse{Status: http.StatusInternalServerError, Error: "Todoの一括操作中にエラーが発生しました"}
+}
@@ -0,0 +1,156 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/usecase"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkTodos(t *testing.T) {
+	done := true
+	ops := []usecase.BulkOperation{
+		{Action: usecase.BulkCreate, Create: usecase.CreateTodoInput{Title: "買い物"}},
+		{Action: usecase.BulkUpdate, ID: "1", Version: 2, Patch: usecase.TodoPatch{Done: &done}},
+		{Action: usecase.BulkDelete, ID: "2"},
+	}
+	body := `{"operations": [
+		{"action": "create", "todo": {"title": "買い物"}},
+		{"action": "update", "id": 1, "version": 2, "patch": {"done": true}},
+		{"action": "delete", "id": 2}
+	]}`
+
+	testCases := []struct {
+		name             string
+		body             string
+		mockBehavior     func(*MockTodoUseCase)
+		expectedStatus   int
+		expectedStatuses []int
+		expectedActor    string
+	}{
+		{
+			name: "正常系: すべて成功",
+			body: body,
+			mockBehavior: func(m *MockTodoUseCase) {
+				m.On("BulkTodos", ops, usecase.BulkAtomic).Return(usecase.BulkResult{Committed: true, Items: []usecase.BulkItemResult{
+					{Todo: &domain.Todo{ID: 3, Title: "買い物"}},
+					{Todo: &domain.Todo{ID: 1, Done: true, Version: 3}},
+					{},
+				}}, nil)
+			},
+			expectedStatus:   http.StatusOK,
+			expectedStatuses: []int{http.StatusCreated, http.StatusOK, http.StatusNoContent},
+			expectedActor:    "alice",
+		},
+		{
+			name: "正常系: atomic で失敗した操作のステータスコードを返す",
+			body: body,
+			mockBehavior: func(m *MockTodoUseCase) {
+				m.On("BulkTodos", ops, usecase.BulkAtomic).Return(usecase.BulkResult{Items: []usecase.BulkItemResult{
+					{Err: usecase.ErrBulkAborted},
+					{Err: errors.NewConflictError("ID 1 のTodoは他の操作で更新されています（現在のバージョンは 3 です）")},
+					{Err: usecase.ErrBulkAborted},
+				}}, nil)
+			},
+			expectedStatus:   http.StatusPreconditionFailed,
+			expectedStatuses: []int{http.StatusFailedDependency, http.StatusPreconditionFailed, http.StatusFailedDependency},
+			expectedActor:    "alice",
+		},
+		{
+			name: "正常系: 未完了のサブタスクがある場合は409を返す",
+			body: body,
+			mockBehavior: func(m *MockTodoUseCase) {
+				m.On("BulkTodos", ops, usecase.BulkAtomic).Return(usecase.BulkResult{Items: []usecase.BulkItemResult{
+					{Err: usecase.ErrBulkAborted},
+					{Err: errors.NewConflictError("未完了のサブタスクがあるため完了にできません", usecase.ErrIncompleteSubtasks)},
+					{Err: usecase.ErrBulkAborted},
+				}}, nil)
+			},
+			expectedStatus:   http.StatusConflict,
+			expectedStatuses: []int{http.StatusFailedDependency, http.StatusConflict, http.StatusFailedDependency},
+			expectedActor:    "alice",
+		},
+		{
+			name: "正常系: best_effort は一部が失敗しても200を返す",
+			body: strings.Replace(body, `{"operations"`, `{"mode": "best_effort", "operations"`, 1),
+			mockBehavior: func(m *MockTodoUseCase) {
+				m.On("BulkTodos", ops, usecase.BulkBestEffort).Return(usecase.BulkResult{Committed: true, Items: []usecase.BulkItemResult{
+					{Todo: &domain.Todo{ID: 3, Title: "買い物"}},
+					{Err: errors.NewNotFoundError("ID 1 のTodoが見つかりません")},
+					{},
+				}}, nil)
+			},
+			expectedStatus:   http.StatusOK,
+			expectedStatuses: []int{http.StatusCreated, http.StatusNotFound, http.StatusNoContent},
+			expectedActor:    "alice",
+		},
+		{
+			name:           "異常系: 不明なモード",
+			body:           `{"mode": "partial", "operations": [{"action": "delete", "id": 2}]}`,
+			mockBehavior:   func(m *MockTodoUseCase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "異常系: 更新するIDがない",
+			body:           `{"operations": [{"action": "update", "patch": {"done": true}}]}`,
+			mockBehavior:   func(m *MockTodoUseCase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "異常系: 変更できない項目",
+			body:           `{"operations": [{"action": "update", "id": 1, "patch": {"version": 5}}]}`,
+			mockBehavior:   func(m *MockTodoUseCase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "異常系: 作成する内容がない",
+			body:           `{"operations": [{"action": "create"}]}`,
+			mockBehavior:   func(m *MockTodoUseCase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "異常系: 保存に失敗",
+			body: body,
+			mockBehavior: func(m *MockTodoUseCase) {
+				m.On("BulkTodos", ops, usecase.BulkAtomic).Return(usecase.BulkResult{}, errors.NewInternalError("まとめて実行した操作の保存に失敗しました"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedActor:  "alice",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// モックの設定
+			mockUseCase := new(MockTodoUseCase)
+			tc.mockBehavior(mockUseCase)
+			server := NewTodoServer(mockUseCase)
+
+			// リクエスト実行（ルーター経由）
+			req := httptest.NewRequest(http.MethodPost, "/todos/bulk", strings.NewReader(tc.body))
+			req.Header.Set(ActorHeader, "alice")
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			// 検証
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatuses != nil {
+				var resp BulkResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				statuses := make([]int, len(resp.Results))
+				for i, result := range resp.Results {
+					statuses[i] = result.Status
+				}
+				assert.Equal(t, tc.expectedStatuses, statuses)
+			}
+			assert.Equal(t, tc.expectedActor, mockUseCase.actor)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
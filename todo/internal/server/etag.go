@@ -0,0 +1,43 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+)
+
+// etag はTODOのバージョンを表すETag（強いETag。例: "3"）
+func etag(version uint) string {
+	return `"` + strconv.FormatUint(uint64(version), 10) + `"`
+}
+
+// setETag はレスポンスのETagヘッダーにTODOのバージョンを設定する
+func setETag(w http.ResponseWriter, version uint) {
+	w.Header().Set("ETag", etag(version))
+}
+
+// ifMatchVersion はIf-Matchヘッダーから、更新・削除の前提とするTODOのバージョンを取得する
+// ヘッダーがない場合と * の場合は0（バージョンを確認しない）を返す
+// 弱いETagは強い比較で一致しないため、どのバージョンとも一致しないものとして競合エラーにする
+func ifMatchVersion(r *http.Request) (uint, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+	if strings.Contains(value, ",") {
+		return 0, errors.NewInvalidInputError("If-Match には1つのETagを指定してください")
+	}
+	if strings.HasPrefix(value, "W/") {
+		return 0, errors.NewConflictError("If-Match の弱いETagは現在のTodoと一致しません")
+	}
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return 0, errors.NewInvalidInputError(`If-Match にはETagを二重引用符で囲んで指定してください（例: "3"）`)
+	}
+	version, err := strconv.ParseUint(value[1:len(value)-1], 10, 0)
+	if err != nil || version == 0 {
+		return 0, errors.NewConflictError("If-Match のETagは現在のTodoと一致しません")
+	}
+	return uint(version), nil
+}
@@ -0,0 +1,140 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/usecase"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateTodoIfMatch(t *testing.T) {
+	testCases := []struct {
+		name           string
+		ifMatch        string
+		version        uint // ユースケースに渡すバージョン
+		callUseCase    bool
+		err            error
+		expectedStatus int
+	}{
+		{
+			name:           "If-Matchなし",
+			callUseCase:    true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "任意のバージョン",
+			ifMatch:        "*",
+			callUseCase:    true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "バージョンを指定",
+			ifMatch:        `"3"`,
+			version:        3,
+			callUseCase:    true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "バージョンが一致しない",
+			ifMatch:        `"2"`,
+			version:        2,
+			callUseCase:    true,
+			err:            errors.NewConflictError("ID 1 のTodoは他の操作で更新されています（現在のバージョンは 3 です）"),
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:           "弱いETagは一致しない",
+			ifMatch:        `W/"3"`,
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:           "バージョン以外のETagは一致しない",
+			ifMatch:        `"abc"`,
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:           "引用符のないETag",
+			ifMatch:        "3",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "複数のETag",
+			ifMatch:        `"2", "3"`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// モックの設定
+			mockUseCase := new(MockTodoUseCase)
+			if tc.callUseCase {
+				todo := domain.Todo{ID: 1, Title: "Test Todo", Done: true, Version: 4}
+				mockUseCase.On("UpdateTodo", "1", true, tc.version).Return(todo, tc.err)
+			}
+			server := NewTodoServer(mockUseCase)
+
+			// リクエスト実行（ルーター経由）
+			req := httptest.NewRequest(http.MethodPut, "/todos/1", bytes.NewBufferString(`{"done": true}`))
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			// 検証 - 更新後のバージョンをETagで返す
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatus == http.StatusOK {
+				assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+			} else {
+				assert.Empty(t, w.Header().Get("ETag"))
+			}
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestETagOnWrite(t *testing.T) {
+	todo := domain.Todo{ID: 1, Title: "Test Todo", Version: 2}
+	title := "Test Todo"
+
+	mockUseCase := new(MockTodoUseCase)
+	mockUseCase.On("UpdatePriority", "1", "high", uint(1)).Return(todo, nil)
+	mockUseCase.On("UpdateSchedule", "1", (*time.Time)(nil), (*time.Time)(nil), uint(1)).Return(todo, nil)
+	mockUseCase.On("PatchTodo", "1", usecase.TodoPatch{Title: &title}, uint(1)).Return(todo, nil)
+	mockUseCase.On("DeleteTodoByID", "1", uint(2)).Return(errors.NewConflictError("ID 1 のTodoは他の操作で更新されています"))
+	server := NewTodoServer(mockUseCase)
+
+	testCases := []struct {
+		method         string
+		path           string
+		body           string
+		ifMatch        string
+		expectedStatus int
+		expectedETag   string
+	}{
+		{http.MethodPut, "/todos/1/priority", `{"priority": "high"}`, `"1"`, http.StatusOK, `"2"`},
+		{http.MethodPut, "/todos/1/schedule", `{"start_at": null, "due_at": null}`, `"1"`, http.StatusOK, `"2"`},
+		{http.MethodPatch, "/todos/1", `{"title": "Test Todo"}`, `"1"`, http.StatusOK, `"2"`},
+		{http.MethodDelete, "/todos/1", "", `"2"`, http.StatusPreconditionFailed, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, bytes.NewBufferString(tc.body))
+			req.Header.Set("If-Match", tc.ifMatch)
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Equal(t, tc.expectedETag, w.Header().Get("ETag"))
+		})
+	}
+	mockUseCase.AssertExpectations(t)
+}
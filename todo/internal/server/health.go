@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+// readinessCheckTimeout は /readyz で1つの確認にかけられる時間
+const readinessCheckTimeout = 5 * time.Second
+
+// 確認の結果（HealthResponse.Status と HealthResponse.Checks の値）
+const (
+	healthOK          = "ok"
+	healthUnavailable = "unavailable"
+)
+
+// readinessCheck は /readyz で確認する項目
+type readinessCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// WithReadinessCheck は /readyz で確認する項目を追加する
+// check がエラーを返した場合は、リクエストを処理できない状態として 503 を返す（name は結果のキー）
+func WithReadinessCheck(name string, check func(ctx context.Context) error) Option {
+	return func(s *TodoServer) {
+		s.readinessChecks = append(s.readinessChecks, readinessCheck{name: name, check: check})
+	}
+}
+
+// HealthResponse は /healthz と /readyz のレスポンス
+type HealthResponse struct {
+	Status string            `json:"status"`           // ok または unavailable
+	Checks map[string]string `json:"checks,omitempty"` // 項目ごとの結果（ok またはエラーメッセージ）
+}
+
+// VersionResponse は /version のレスポンス
+type VersionResponse struct {
+	Version      string     `json:"version"`                 // モジュールのバージョン（go build でビルドした場合は "(devel)"）
+	GoVersion    string     `json:"go_version"`              // ビルドに使用したGoのバージョン
+	Revision     string     `json:"revision,omitempty"`      // ビルドしたVCSのリビジョン
+	RevisionTime *time.Time `json:"revision_time,omitempty"` // リビジョンをコミットした日時
+	Modified     bool       `json:"modified"`                // コミットしていない変更を含めてビルドしたか
+	StartTime    time.Time  `json:"start_time"`              // サーバーを起動した日時
+}
+
+// healthRoutes は監視用のルーティングを設定する
+func (s *TodoServer) healthRoutes() {
+	s.router.HandleFunc("/healthz", s.healthz).Methods("GET")
+	s.router.HandleFunc("/readyz", s.readyz).Methods("GET")
+	s.router.HandleFunc("/version", s.version).Methods("GET")
+}
+
+// healthz はプロセスが動作していることを返す（保存先などは確認しない）
+func (s *TodoServer) healthz(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, r, http.StatusOK, HealthResponse{Status: healthOK})
+}
+
+// readyz は WithReadinessCheck で追加した項目をすべて確認し、リクエストを処理できる状態かどうかを返す
+// 1つでも失敗した場合は 503 Service Unavailable を返す
+func (s *TodoServer) readyz(w http.ResponseWriter, r *http.Request) {
+	resp := HealthResponse{Status: healthOK, Checks: make(map[string]string, len(s.readinessChecks))}
+	for _, c := range s.readinessChecks {
+		ctx, cancel := context.WithTimeout(r.Context(), readinessCheckTimeout)
+		err := c.check(ctx)
+		cancel()
+		if err != nil {
+			s.log(r).Errorf("リクエストを処理できる状態ではありません（%s）: %v", c.name, err)
+			resp.Status, resp.Checks[c.name] = healthUnavailable, err.Error()
+			continue
+		}
+		resp.Checks[c.name] = healthOK
+	}
+
+	status := http.StatusOK
+	if resp.Status != healthOK {
+		status = http.StatusServiceUnavailable
+	}
+	s.writeJSON(w, r, status, resp)
+}
+
+// version はビルドの情報とサーバーを起動した日時を返す
+func (s *TodoServer) version(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, r, http.StatusOK, s.buildInfo)
+}
+
+// newVersionResponse は実行中のバイナリに埋め込まれたビルドの情報を読み込む
+func newVersionResponse(startTime time.Time) VersionResponse {
+	resp := VersionResponse{StartTime: startTime}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return resp
+	}
+	resp.Version, resp.GoVersion = info.Main.Version, info.GoVersion
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			resp.Revision = setting.Value
+		case "vcs.time":
+			if t, err := time.Parse(time.RFC3339, setting.Value); err == nil {
+				resp.RevisionTime = &t
+			}
+		case "vcs.modified":
+			resp.Modified = setting.Value == "true"
+		}
+	}
+	return resp
+}
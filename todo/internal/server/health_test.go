@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthz(t *testing.T) {
+	// 確認に失敗する項目があっても、プロセスが動作していれば200を返す
+	server := NewTodoServer(new(MockTodoUseCase), WithReadinessCheck("database", func(ctx context.Context) error {
+		return stderrors.New("データベースに接続できません")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status": "ok"}`, w.Body.String())
+}
+
+func TestReadyz(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+
+	testCases := []struct {
+		name           string
+		opts           []Option
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "正常系: 確認する項目がない",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "ok"}`,
+		},
+		{
+			name:           "正常系: すべての項目を確認できた",
+			opts:           []Option{WithReadinessCheck("database", ok), WithReadinessCheck("migrations", ok)},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "ok", "checks": {"database": "ok", "migrations": "ok"}}`,
+		},
+		{
+			name: "異常系: 失敗した項目がある",
+			opts: []Option{
+				WithReadinessCheck("database", ok),
+				WithReadinessCheck("migrations", func(ctx context.Context) error {
+					return stderrors.New("スキーマが最新ではありません（1 件）")
+				}),
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"status": "unavailable", "checks": {"database": "ok", "migrations": "スキーマが最新ではありません（1 件）"}}`,
+		},
+		{
+			name: "異常系: 確認には時間の制限を設定する",
+			opts: []Option{WithReadinessCheck("database", func(ctx context.Context) error {
+				deadline, ok := ctx.Deadline()
+				if !ok || time.Until(deadline) > readinessCheckTimeout {
+					return nil
+				}
+				return context.DeadlineExceeded
+			})},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"status": "unavailable", "checks": {"database": "context deadline exceeded"}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := NewTodoServer(new(MockTodoUseCase), tc.opts...)
+
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.JSONEq(t, tc.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestVersion(t *testing.T) {
+	before := time.Now()
+	server := NewTodoServer(new(MockTodoUseCase))
+
+	req := httptest.NewRequest(http.MethodGet, "/version", nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp VersionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.NotEmpty(t, resp.GoVersion)
+	assert.WithinDuration(t, before, resp.StartTime, time.Second)
+}
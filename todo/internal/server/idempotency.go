@@ -0,0 +1,208 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	stderrors "errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// IdempotencyKeyHeader は同じ操作の再送であることを示すキーを指定するリクエストヘッダー
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader は保存していたレスポンスを返したことを示すレスポンスヘッダー
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// DefaultIdempotencyTTL はIdempotency-Keyとレスポンスを保存しておく期間の既定値
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// maxIdempotencyKeyLength はIdempotency-Keyに指定できる最大の長さ
+const maxIdempotencyKeyLength = 255
+
+// WithIdempotencyTTL はIdempotency-Keyとレスポンスを保存しておく期間を設定する
+// 0以下を指定した場合はIdempotency-Keyを無視し、再送されたリクエストもそのまま処理する
+func WithIdempotencyTTL(ttl time.Duration) Option {
+	return func(s *TodoServer) {
+		s.idempotencyTTL = ttl
+	}
+}
+
+// Idempotency-Key を処理できない理由
+var (
+	errIdempotencyKeyReused     = stderrors.New("Idempotency-Key が異なるリクエストで使用されています")
+	errIdempotencyKeyInProgress = stderrors.New("同じ Idempotency-Key のリクエストを処理中です")
+)
+
+// idempotencyStore はIdempotency-Keyごとに、リクエストの内容と返したレスポンスを一定期間保存する
+// 保存はサーバーのメモリ上で行うため、サーバーを再起動すると保存していた内容は消える
+type idempotencyStore struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	now       func() time.Time // 現在時刻の取得（テスト時に差し替え可能）
+	entries   map[string]*idempotencyEntry
+	nextSweep time.Time // 期限切れのキーを次に削除する日時
+}
+
+// idempotencyEntry は1つのIdempotency-Keyについて保存している内容
+type idempotencyEntry struct {
+	fingerprint [sha256.Size]byte // リクエストのメソッド・パス・操作者・本文のハッシュ
+	expiresAt   time.Time
+	done        bool // レスポンスを保存したか（false の場合は処理中）
+	status      int
+	header      http.Header
+	body        []byte
+}
+
+// newIdempotencyStore はidempotencyStoreを作成する
+func newIdempotencyStore(ttl time.Duration) *idempotencyStore {
+	return &idempotencyStore{ttl: ttl, now: time.Now, entries: make(map[string]*idempotencyEntry)}
+}
+
+// begin はキーの処理を始める
+// 同じ内容のリクエストのレスポンスを保存している場合はそれを返す。nil を返した場合はキーを処理中にしたため、finish か cancel を呼び出すこと
+func (st *idempotencyStore) begin(key string, fingerprint [sha256.Size]byte) (*idempotencyEntry, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	now := st.now()
+	if now.After(st.nextSweep) {
+		for k, entry := range st.entries {
+			if entry.done && now.After(entry.expiresAt) {
+				delete(st.entries, k)
+			}
+		}
+		st.nextSweep = now.Add(time.Minute)
+	}
+
+	if entry, ok := st.entries[key]; ok && !(entry.done && now.After(entry.expiresAt)) {
+		switch {
+		case entry.fingerprint != fingerprint:
+			return nil, errIdempotencyKeyReused
+		case !entry.done:
+			return nil, errIdempotencyKeyInProgress
+		}
+		return entry, nil
+	}
+	st.entries[key] = &idempotencyEntry{fingerprint: fingerprint}
+	return nil, nil
+}
+
+// finish は処理中のキーにレスポンスを保存する
+func (st *idempotencyStore) finish(key string, status int, header http.Header, body []byte) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if entry, ok := st.entries[key]; ok {
+		entry.done, entry.status, entry.header, entry.body = true, status, header, body
+		entry.expiresAt = st.now().Add(st.ttl)
+	}
+}
+
+// cancel は処理中のキーを削除し、同じキーで再送されたリクエストを改めて処理できるようにする
+func (st *idempotencyStore) cancel(key string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	delete(st.entries, key)
+}
+
+// idempotency はIdempotency-Keyを指定したPOSTリクエストを1回だけ処理するミドルウェア
+// 同じキーで同じ内容のリクエストが再送された場合は、処理せずに保存していたレスポンスを返す
+// 同じキーを異なる内容のリクエストに使用した場合は 422、処理中の場合は 409 を返す。5xxのレスポンスは保存せず、再送で処理し直す
+func (s *TodoServer) idempotency(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if r.Method != http.MethodPost || key == "" || s.idempotencyKeys == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			s.log(r).Errorf("Idempotency-Keyが長すぎます: %d 文字", len(key))
+			http.Error(w, "Idempotency-Key は255文字以内にしてください", http.StatusBadRequest)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			s.log(r).Errorf("リクエストボディの読み込みに失敗しました: %v", err)
+			http.Error(w, "リクエストボディの読み込みに失敗しました", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		stored, err := s.idempotencyKeys.begin(key, requestFingerprint(r, body))
+		switch {
+		case stderrors.Is(err, errIdempotencyKeyReused):
+			s.log(r).Errorf("%v: key=%s", err, key)
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		case stderrors.Is(err, errIdempotencyKeyInProgress):
+			s.log(r).Errorf("%v: key=%s", err, key)
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		case stored != nil:
+			for name, values := range stored.header {
+				w.Header()[name] = values
+			}
+			w.Header().Set(IdempotentReplayedHeader, "true")
+			w.WriteHeader(stored.status)
+			w.Write(stored.body)
+			s.log(r).Infof("保存していたレスポンスを返却しました: key=%s, status=%d", key, stored.status)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w}
+		defer func() {
+			// 5xxの場合とハンドラーがパニックした場合は保存しない
+			if rec.status == 0 || rec.status >= http.StatusInternalServerError {
+				s.idempotencyKeys.cancel(key)
+				return
+			}
+			// リクエストIDは再送ごとに異なるため、保存しない
+			header := w.Header().Clone()
+			header.Del(RequestIDHeader)
+			s.idempotencyKeys.finish(key, rec.status, header, rec.body.Bytes())
+		}()
+		next.ServeHTTP(rec, r)
+	})
+}
+
+// requestFingerprint はIdempotency-Keyの再利用を判定するため、リクエストの内容のハッシュを返す
+// 操作者も含め、他の利用者のレスポンスを返さないようにする
+func requestFingerprint(r *http.Request, body []byte) [sha256.Size]byte {
+	h := sha256.New()
+	for _, s := range []string{r.Method, r.URL.RequestURI(), r.Header.Get(ActorHeader)} {
+		io.WriteString(h, s)
+		h.Write([]byte{0})
+	}
+	h.Write(body)
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return sum
+}
+
+// responseRecorder はクライアントに返すレスポンスのステータスコードと本文を記録する
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+// WriteHeader はステータスコードを記録してから書き込む
+func (w *responseRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write は本文を記録してから書き込む
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
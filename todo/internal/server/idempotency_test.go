@@ -0,0 +1,196 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	type request struct {
+		key   string
+		body  string
+		actor string
+	}
+	created := domain.Todo{ID: 1, Title: "買い物", Version: 1}
+
+	testCases := []struct {
+		name             string
+		requests         []request
+		elapsed          time.Duration // 2回目以降のリクエストまでの経過時間
+		ttl              time.Duration
+		mockBehavior     func(*MockTodoUseCase)
+		expectedStatuses []int
+		expectedReplayed []bool
+	}{
+		{
+			name:     "正常系: 同じキーの再送には保存したレスポンスを返す",
+			requests: []request{{key: "k1", body: `{"title": "買い物"}`}, {key: "k1", body: `{"title": "買い物"}`}},
+			ttl:      DefaultIdempotencyTTL,
+			mockBehavior: func(m *MockTodoUseCase) {
+				m.On("CreateTodo", mock.Anything).Return(created, nil).Once()
+			},
+			expectedStatuses: []int{http.StatusCreated, http.StatusCreated},
+			expectedReplayed: []bool{false, true},
+		},
+		{
+			name:     "正常系: 異なるキーはそれぞれ処理する",
+			requests: []request{{key: "k1", body: `{"title": "買い物"}`}, {key: "k2", body: `{"title": "買い物"}`}},
+			ttl:      DefaultIdempotencyTTL,
+			mockBehavior: func(m *MockTodoUseCase) {
+				m.On("CreateTodo", mock.Anything).Return(created, nil).Twice()
+			},
+			expectedStatuses: []int{http.StatusCreated, http.StatusCreated},
+			expectedReplayed: []bool{false, false},
+		},
+		{
+			name:     "正常系: キーがない場合は毎回処理する",
+			requests: []request{{body: `{"title": "買い物"}`}, {body: `{"title": "買い物"}`}},
+			ttl:      DefaultIdempotencyTTL,
+			mockBehavior: func(m *MockTodoUseCase) {
+				m.On("CreateTodo", mock.Anything).Return(created, nil).Twice()
+			},
+			expectedStatuses: []int{http.StatusCreated, http.StatusCreated},
+			expectedReplayed: []bool{false, false},
+		},
+		{
+			name:     "正常系: 4xxのレスポンスも保存する",
+			requests: []request{{key: "k1", body: `{"title": "買い物", "parent_id": 9}`}, {key: "k1", body: `{"title": "買い物", "parent_id": 9}`}},
+			ttl:      DefaultIdempotencyTTL,
+			mockBehavior: func(m *MockTodoUseCase) {
+				m.On("CreateTodo", mock.Anything).Return(domain.Todo{}, errors.NewNotFoundError("ID 9 のTodoが見つかりません")).Once()
+			},
+			expectedStatuses: []int{http.StatusNotFound, http.StatusNotFound},
+			expectedReplayed: []bool{false, true},
+		},
+		{
+			name:     "正常系: 5xxのレスポンスは保存せず、再送で処理し直す",
+			requests: []request{{key: "k1", body: `{"title": "買い物"}`}, {key: "k1", body: `{"title": "買い物"}`}},
+			ttl:      DefaultIdempotencyTTL,
+			mockBehavior: func(m *MockTodoUseCase) {
+				m.On("CreateTodo", mock.Anything).Return(domain.Todo{}, errors.NewInternalError("Todoの作成に失敗しました")).Once()
+				m.On("CreateTodo", mock.Anything).Return(created, nil).Once()
+			},
+			expectedStatuses: []int{http.StatusInternalServerError, http.StatusCreated},
+			expectedReplayed: []bool{false, false},
+		},
+		{
+			name:     "正常系: 保存期間を過ぎたキーは改めて処理する",
+			requests: []request{{key: "k1", body: `{"title": "買い物"}`}, {key: "k1", body: `{"title": "買い物"}`}},
+			elapsed:  time.Hour + time.Second,
+			ttl:      time.Hour,
+			mockBehavior: func(m *MockTodoUseCase) {
+				m.On("CreateTodo", mock.Anything).Return(created, nil).Twice()
+			},
+			expectedStatuses: []int{http.StatusCreated, http.StatusCreated},
+			expectedReplayed: []bool{false, false},
+		},
+		{
+			name:     "正常系: 保存期間が0以下の場合はキーを無視する",
+			requests: []request{{key: "k1", body: `{"title": "買い物"}`}, {key: "k1", body: `{"title": "掃除"}`}},
+			ttl:      0,
+			mockBehavior: func(m *MockTodoUseCase) {
+				m.On("CreateTodo", mock.Anything).Return(created, nil).Twice()
+			},
+			expectedStatuses: []int{http.StatusCreated, http.StatusCreated},
+			expectedReplayed: []bool{false, false},
+		},
+		{
+			name:     "異常系: 同じキーを異なる本文で使用",
+			requests: []request{{key: "k1", body: `{"title": "買い物"}`}, {key: "k1", body: `{"title": "掃除"}`}},
+			ttl:      DefaultIdempotencyTTL,
+			mockBehavior: func(m *MockTodoUseCase) {
+				m.On("CreateTodo", mock.Anything).Return(created, nil).Once()
+			},
+			expectedStatuses: []int{http.StatusCreated, http.StatusUnprocessableEntity},
+			expectedReplayed: []bool{false, false},
+		},
+		{
+			name:     "異常系: 同じキーを異なる操作者で使用",
+			requests: []request{{key: "k1", body: `{"title": "買い物"}`, actor: "alice"}, {key: "k1", body: `{"title": "買い物"}`, actor: "bob"}},
+			ttl:      DefaultIdempotencyTTL,
+			mockBehavior: func(m *MockTodoUseCase) {
+				m.On("CreateTodo", mock.Anything).Return(created, nil).Once()
+			},
+			expectedStatuses: []int{http.StatusCreated, http.StatusUnprocessableEntity},
+			expectedReplayed: []bool{false, false},
+		},
+		{
+			name:             "異常系: キーが長すぎる",
+			requests:         []request{{key: strings.Repeat("k", maxIdempotencyKeyLength+1), body: `{"title": "買い物"}`}},
+			ttl:              DefaultIdempotencyTTL,
+			mockBehavior:     func(m *MockTodoUseCase) {},
+			expectedStatuses: []int{http.StatusBadRequest},
+			expectedReplayed: []bool{false},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// モックの設定
+			mockUseCase := new(MockTodoUseCase)
+			tc.mockBehavior(mockUseCase)
+			server := NewTodoServer(mockUseCase, WithIdempotencyTTL(tc.ttl))
+			now := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+			if server.idempotencyKeys != nil {
+				server.idempotencyKeys.now = func() time.Time { return now }
+			}
+
+			var firstBody string
+			for i, r := range tc.requests {
+				if i > 0 {
+					now = now.Add(tc.elapsed)
+				}
+
+				// リクエスト実行（ルーター経由）
+				req := httptest.NewRequest(http.MethodPost, "/todos", strings.NewReader(r.body))
+				if r.key != "" {
+					req.Header.Set(IdempotencyKeyHeader, r.key)
+				}
+				if r.actor != "" {
+					req.Header.Set(ActorHeader, r.actor)
+				}
+				req.Header.Set(RequestIDHeader, "req-"+strconv.Itoa(i))
+				w := httptest.NewRecorder()
+				server.ServeHTTP(w, req)
+
+				// 検証
+				assert.Equal(t, tc.expectedStatuses[i], w.Code, "%d 回目のリクエスト", i+1)
+				assert.Equal(t, tc.expectedReplayed[i], w.Header().Get(IdempotentReplayedHeader) == "true", "%d 回目のリクエスト", i+1)
+				if i == 0 {
+					firstBody = w.Body.String()
+				} else if tc.expectedReplayed[i] {
+					assert.Equal(t, firstBody, w.Body.String(), "%d 回目のリクエスト", i+1)
+					// リクエストIDは保存したものではなく、再送したリクエストのものを返す
+					assert.Equal(t, []string{"req-" + strconv.Itoa(i)}, w.Header().Values(RequestIDHeader), "%d 回目のリクエスト", i+1)
+				}
+			}
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIdempotencyKeyInProgress(t *testing.T) {
+	// 1回目のリクエストの処理中に同じキーで再送された場合
+	mockUseCase := new(MockTodoUseCase)
+	server := NewTodoServer(mockUseCase)
+	req := httptest.NewRequest(http.MethodPost, "/todos", strings.NewReader(`{"title": "買い物"}`))
+	req.Header.Set(IdempotencyKeyHeader, "k1")
+	stored, err := server.idempotencyKeys.begin("k1", requestFingerprint(req, []byte(`{"title": "買い物"}`)))
+	assert.NoError(t, err)
+	assert.Nil(t, stored)
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
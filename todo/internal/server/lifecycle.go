@@ -0,0 +1,82 @@
+package server
+
+import (
+	"context"
+	stderrors "errors"
+	"log"
+	"net"
+	"net/http"
+	"time"
+)
+
+// http.Server の時間制限の既定値
+// 書き込みの制限はリクエストを処理できる時間（DefaultRequestTimeout）より長くし、処理を中断した場合も503を返せるようにする
+const (
+	DefaultReadTimeout  = 15 * time.Second  // リクエストを読み込める時間
+	DefaultWriteTimeout = 60 * time.Second  // リクエストを読み込んでからレスポンスを書き込み終えるまでの時間
+	DefaultIdleTimeout  = 120 * time.Second // Keep-Alive の接続で次のリクエストを待つ時間
+)
+
+// WithReadTimeout はリクエストを読み込める時間を設定する（0の場合は制限しない）
+func WithReadTimeout(timeout time.Duration) Option {
+	return func(s *TodoServer) {
+		s.httpServer.ReadTimeout = timeout
+	}
+}
+
+// WithWriteTimeout はリクエストを読み込んでからレスポンスを書き込み終えるまでの時間を設定する（0の場合は制限しない）
+func WithWriteTimeout(timeout time.Duration) Option {
+	return func(s *TodoServer) {
+		s.httpServer.WriteTimeout = timeout
+	}
+}
+
+// WithIdleTimeout はKeep-Aliveの接続で次のリクエストを待つ時間を設定する（0の場合は ReadTimeout と同じ）
+func WithIdleTimeout(timeout time.Duration) Option {
+	return func(s *TodoServer) {
+		s.httpServer.IdleTimeout = timeout
+	}
+}
+
+// Start はサーバーを指定されたアドレスで起動し、Shutdown が呼び出されるまでリクエストを処理する
+// 待ち受けを始めた時点で Ready のチャネルを閉じる。Shutdown で停止した場合は nil を返す
+func (s *TodoServer) Start(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	s.addr = ln.Addr().String()
+	close(s.ready)
+
+	log.Printf("Starting server on %s", s.addr)
+	if err := s.httpServer.Serve(ln); !stderrors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Ready はサーバーがリクエストの待ち受けを始めた時点で閉じるチャネルを返す
+func (s *TodoServer) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Addr は待ち受けているアドレスを返す（Ready のチャネルが閉じる前は空文字）
+// ポートに0を指定した場合も、実際に割り当てられたポートを返す
+func (s *TodoServer) Addr() string {
+	select {
+	case <-s.ready:
+		return s.addr
+	default:
+		return ""
+	}
+}
+
+// Shutdown は新しいリクエストの受け付けを止め、処理中のリクエストが終わるのを待ってからサーバーを停止する
+// ctx が終了するまでに終わらなかった場合は残りの接続を閉じ、ctx のエラーを返す
+func (s *TodoServer) Shutdown(ctx context.Context) error {
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		s.httpServer.Close()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerLifecycle(t *testing.T) {
+	// 処理中のリクエストを止めておき、Shutdown がその終了を待つことを確認する
+	started, release := make(chan struct{}), make(chan struct{})
+	mockUseCase := new(MockTodoUseCase)
+	mockUseCase.On("GetTodoPage", mock.Anything).Run(func(mock.Arguments) {
+		close(started)
+		<-release
+	}).Return(usecase.TodoPage{}, nil)
+	server := NewTodoServer(mockUseCase)
+	assert.Empty(t, server.Addr())
+
+	startErr := make(chan error, 1)
+	go func() { startErr <- server.Start("127.0.0.1:0") }()
+	select {
+	case <-server.Ready():
+	case err := <-startErr:
+		t.Fatalf("サーバーを起動できません: %v", err)
+	}
+	url := "http://" + server.Addr() + "/todos"
+
+	respCh := make(chan *http.Response, 1)
+	go func() {
+		resp, err := http.Get(url)
+		assert.NoError(t, err)
+		respCh <- resp
+	}()
+	<-started
+
+	shutdownErr := make(chan error, 1)
+	go func() { shutdownErr <- server.Shutdown(context.Background()) }()
+
+	// Shutdown は処理中のリクエストが終わるまで戻らない
+	select {
+	case err := <-shutdownErr:
+		t.Fatalf("処理中のリクエストを待たずに停止しました: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(release)
+
+	resp := <-respCh
+	require.NotNil(t, resp)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NoError(t, <-shutdownErr)
+	assert.NoError(t, <-startErr)
+
+	// 停止した後は新しいリクエストを受け付けない
+	_, err := http.Get(url)
+	assert.Error(t, err)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestServerShutdownTimeout(t *testing.T) {
+	// 処理中のリクエストが ctx の終了までに終わらない場合は接続を閉じてエラーを返す
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	mockUseCase := new(MockTodoUseCase)
+	mockUseCase.On("GetTodoPage", mock.Anything).Run(func(mock.Arguments) {
+		close(started)
+		<-release
+	}).Return(usecase.TodoPage{}, nil)
+	server := NewTodoServer(mockUseCase)
+
+	go server.Start("127.0.0.1:0")
+	<-server.Ready()
+	go func() {
+		if resp, err := http.Get("http://" + server.Addr() + "/todos"); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, server.Shutdown(ctx), context.DeadlineExceeded)
+}
+
+func TestServerStartError(t *testing.T) {
+	server := NewTodoServer(new(MockTodoUseCase))
+
+	assert.Error(t, server.Start("invalid address"))
+	select {
+	case <-server.Ready():
+		t.Fatal("起動できなかったのに Ready のチャネルが閉じています")
+	default:
+	}
+}
+
+func TestServerTimeoutOptions(t *testing.T) {
+	server := NewTodoServer(new(MockTodoUseCase), WithReadTimeout(5*time.Second), WithWriteTimeout(0))
+
+	assert.Equal(t, 5*time.Second, server.httpServer.ReadTimeout)
+	assert.Equal(t, time.Duration(0), server.httpServer.WriteTimeout)
+	assert.Equal(t, DefaultIdleTimeout, server.httpServer.IdleTimeout)
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// MetricsNamespace はこのアプリケーションが公開するメトリクス名の接頭辞
+const MetricsNamespace = "todo"
+
+// unmatchedRoute はどのルートにも一致しなかったリクエストの route ラベル
+const unmatchedRoute = "unmatched"
+
+// WithMetricsRegistry は /metrics で公開するメトリクスを登録するレジストリを設定する
+// データベースなど、サーバー以外のメトリクスも同じレジストリに登録すると /metrics でまとめて公開できる
+// 未設定の場合はサーバーごとに新しいレジストリを作成する（同じレジストリを複数のサーバーに設定することはできない）
+func WithMetricsRegistry(reg *prometheus.Registry) Option {
+	return func(s *TodoServer) {
+		s.metricsRegistry = reg
+	}
+}
+
+// httpMetrics はHTTPリクエストについて記録するメトリクス
+type httpMetrics struct {
+	requests *prometheus.CounterVec   // ルート・メソッド・ステータスコードごとのリクエスト数
+	duration *prometheus.HistogramVec // ルート・メソッドごとの処理時間
+	inFlight prometheus.Gauge         // 処理中のリクエスト数
+}
+
+// newHTTPMetrics はHTTPリクエストのメトリクスとGoランタイム・プロセスのメトリクスを reg に登録する
+func newHTTPMetrics(reg *prometheus.Registry) *httpMetrics {
+	m := &httpMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "処理したHTTPリクエストの数（ルートのテンプレート・メソッド・ステータスコードごと）",
+		}, []string{"route", "method", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: MetricsNamespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTPリクエストの処理にかかった時間（秒）",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Subsystem: "http",
+			Name:      "requests_in_flight",
+			Help:      "処理中のHTTPリクエストの数",
+		}),
+	}
+	reg.MustRegister(
+		m.requests, m.duration, m.inFlight,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// metricsRoutes はメトリクスを公開するルーティングを設定する
+func (s *TodoServer) metricsRoutes() {
+	s.router.Handle("/metrics", promhttp.HandlerFor(s.metricsRegistry, promhttp.HandlerOpts{})).Methods("GET")
+}
+
+// instrument はリクエスト数・ステータスコード・処理時間を記録するミドルウェア
+// パスの値ごとにメトリクスが増えないよう、route には実際のURLではなくルートのテンプレート（/todos/{id} など）を使う
+func (s *TodoServer) instrument(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, method := routeTemplate(r), metricsMethod(r.Method)
+
+		s.metrics.inFlight.Inc()
+		rec := &statusRecorder{ResponseWriter: w}
+		start, panicked := time.Now(), true
+		defer func() {
+			s.metrics.inFlight.Dec()
+			status := rec.status
+			switch {
+			case panicked:
+				// ハンドラーがパニックした場合はレスポンスを返せないため、500 として記録する
+				status = http.StatusInternalServerError
+			case status == 0:
+				status = http.StatusOK
+			}
+			s.metrics.duration.WithLabelValues(route, method).Observe(time.Since(start).Seconds())
+			s.metrics.requests.WithLabelValues(route, method, strconv.Itoa(status)).Inc()
+		}()
+		next.ServeHTTP(rec, r)
+		panicked = false
+	})
+}
+
+// routeTemplate はリクエストに一致したルートのテンプレートを返す（一致しなかった場合は unmatched）
+func routeTemplate(r *http.Request) string {
+	if current := mux.CurrentRoute(r); current != nil {
+		if tpl, err := current.GetPathTemplate(); err == nil {
+			return tpl
+		}
+	}
+	return unmatchedRoute
+}
+
+// metricsMethod はメトリクスのラベルに使うメソッド名を返す
+// クライアントが任意のメソッドを送ってもメトリクスが増えないよう、標準以外のメソッドは OTHER にまとめる
+func metricsMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodOptions, http.MethodConnect, http.MethodTrace:
+		return method
+	}
+	return "OTHER"
+}
+
+// statusRecorder はクライアントに返したステータスコードと本文のサイズを記録する
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+// WriteHeader はステータスコードを記録してから書き込む
+func (w *statusRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write は本文を書き込み、書き込んだサイズを記録する（WriteHeader を呼び出していない場合は 200 として記録する）
+func (w *statusRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	mockUseCase := new(MockTodoUseCase)
+	mockUseCase.On("DeleteTodoByID", "1", uint(0)).Return(nil)
+	mockUseCase.On("DeleteTodoByID", "2", uint(0)).Return(nil)
+	mockUseCase.On("DeleteTodoByID", "3", uint(0)).Return(errors.NewInternalError("Todoの削除に失敗しました"))
+	reg := prometheus.NewRegistry()
+	server := NewTodoServer(mockUseCase, WithMetricsRegistry(reg))
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodDelete, "/todos/1", nil),
+		httptest.NewRequest(http.MethodDelete, "/todos/2", nil),
+		httptest.NewRequest(http.MethodDelete, "/todos/3", nil),
+		httptest.NewRequest(http.MethodGet, "/unknown", nil),
+		httptest.NewRequest(http.MethodPost, "/healthz", nil),
+		httptest.NewRequest("PURGE", "/todos/1", nil),
+	} {
+		server.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	t.Run("ルートのテンプレートとステータスコードごとに記録する", func(t *testing.T) {
+		assert.Equal(t, 2.0, testutil.ToFloat64(server.metrics.requests.WithLabelValues("/todos/{id}", "DELETE", "204")))
+		assert.Equal(t, 1.0, testutil.ToFloat64(server.metrics.requests.WithLabelValues("/todos/{id}", "DELETE", "500")))
+		assert.Equal(t, 0.0, testutil.ToFloat64(server.metrics.inFlight))
+	})
+
+	t.Run("ルートに一致しないリクエストをまとめて記録する", func(t *testing.T) {
+		assert.Equal(t, 1.0, testutil.ToFloat64(server.metrics.requests.WithLabelValues("unmatched", "GET", "404")))
+		assert.Equal(t, 1.0, testutil.ToFloat64(server.metrics.requests.WithLabelValues("unmatched", "POST", "405")))
+		assert.Equal(t, 1.0, testutil.ToFloat64(server.metrics.requests.WithLabelValues("unmatched", "OTHER", "405")))
+	})
+
+	t.Run("処理時間をルートごとに記録する", func(t *testing.T) {
+		// /todos/{id} の DELETE と、一致しなかった GET・POST・OTHER
+		assert.Equal(t, 4, testutil.CollectAndCount(server.metrics.duration, "todo_http_request_duration_seconds"))
+	})
+
+	t.Run("/metrics でテキスト形式で公開する", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/plain")
+		body := w.Body.String()
+		assert.Contains(t, body, `todo_http_requests_total{code="500",method="DELETE",route="/todos/{id}"} 1`)
+		assert.Contains(t, body, `todo_http_request_duration_seconds_count{method="DELETE",route="/todos/{id}"} 3`)
+		assert.Contains(t, body, "go_goroutines ")
+		assert.NotContains(t, body, `route="/todos/1"`)
+	})
+}
+
+func TestMetricsPanic(t *testing.T) {
+	// ハンドラーがパニックした場合も 500 として記録する
+	server := NewTodoServer(new(MockTodoUseCase))
+	server.router.HandleFunc("/panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("テスト用のパニック")
+	})
+
+	assert.Panics(t, func() {
+		server.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+	})
+	assert.Equal(t, 1.0, testutil.ToFloat64(server.metrics.requests.WithLabelValues("/panic", "GET", "500")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(server.metrics.inFlight))
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+
+	"github.com/gorilla/mux"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/usecase"
+)
+
+// JSON Merge Patch のメディアタイプ（RFC 7396）
+const mergePatchContentType = "application/merge-patch+json"
+
+// patchTodo は指定されたTODOを JSON Merge Patch で部分更新する
+// 対象の項目は title, description, done で、省略した項目は変更しない
+func (s *TodoServer) patchTodo(w http.ResponseWriter, r *http.Request) {
+	s.log(r).Info("PATCH /todos/{id} リクエストを受信しました")
+	id := mux.Vars(r)["id"]
+
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || mediaType != mergePatchContentType && mediaType != "application/json" {
+			s.log(r).Errorf("未対応のContent-Typeです: %s", contentType)
+			http.Error(w, "Content-Type には "+mergePatchContentType+" を指定してください", http.StatusUnsupportedMediaType)
+			return
+		}
+	}
+
+	patch, err := parseMergePatch(r.Body)
+	if err != nil {
+		s.log(r).Errorf("リクエストボディの解析に失敗しました: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		s.writeError(w, r, err, "If-Matchヘッダーの解析に失敗しました")
+		return
+	}
+
+	todo, err := s.todos(r).PatchTodo(id, patch, version)
+	if err != nil {
+		s.writeError(w, r, err, "Todoの更新中にエラーが発生しました")
+		return
+	}
+
+	setETag(w, todo.Version)
+	s.writeJSON(w, r, http.StatusOK, todo)
+	s.log(r).Infow("Todoを部分更新しました", "id", id, "title", todo.Title)
+}
+
+// parseMergePatch はマージパッチのJSONオブジェクトを TodoPatch に変換する
+// null は項目の削除を意味するため、description は空になり、必須の title と done はエラーになる
+func parseMergePatch(body io.Reader) (usecase.TodoPatch, error) {
+	var members map[string]json.RawMessage
+	if err := json.NewDecoder(body).Decode(&members); err != nil || members == nil {
+		return usecase.TodoPatch{}, fmt.Errorf("リクエストボディはJSONオブジェクトで指定してください")
+	}
+
+	names := make([]string, 0, len(members))
+	for name := range members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var patch usecase.TodoPatch
+	for _, name := range names {
+		raw := members[name]
+		isNull := bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+
+		switch name {
+		case "title":
+			var title string
+			if isNull || json.Unmarshal(raw, &title) != nil {
+				return usecase.TodoPatch{}, fmt.Errorf("title は文字列で指定してください")
+			}
+			patch.Title = &title
+		case "description":
+			var description string
+			if !isNull && json.Unmarshal(raw, &description) != nil {
+				return usecase.TodoPatch{}, fmt.Errorf("description は文字列または null で指定してください")
+			}
+			patch.Description = &description
+		case "done":
+			var done bool
+			if isNull || json.Unmarshal(raw, &done) != nil {
+				return usecase.TodoPatch{}, fmt.Errorf("done は true または false で指定してください")
+			}
+			patch.Done = &done
+		default:
+			return usecase.TodoPatch{}, fmt.Errorf("%s は変更できない項目です", name)
+		}
+	}
+	return patch, nil
+}
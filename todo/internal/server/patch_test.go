@@ -0,0 +1,138 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/usecase"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatchTodo(t *testing.T) {
+	title := "新しいタイトル"
+	empty := ""
+	done := true
+
+	testCases := []struct {
+		name           string
+		contentType    string
+		body           string
+		patch          *usecase.TodoPatch // nilの場合はユースケースを呼び出さない
+		todo           domain.Todo
+		err            error
+		expectedStatus int
+	}{
+		{
+			name:           "タイトルだけを変更",
+			contentType:    "application/merge-patch+json",
+			body:           `{"title": "新しいタイトル"}`,
+			patch:          &usecase.TodoPatch{Title: &title},
+			todo:           domain.Todo{ID: 1, Title: title},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "nullで説明を削除し、完了にする",
+			contentType:    "application/json",
+			body:           `{"description": null, "done": true}`,
+			patch:          &usecase.TodoPatch{Description: &empty, Done: &done},
+			todo:           domain.Todo{ID: 1, Title: "Test Todo", Done: true},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Content-Typeなし",
+			body:           `{"done": true}`,
+			patch:          &usecase.TodoPatch{Done: &done},
+			todo:           domain.Todo{ID: 1, Title: "Test Todo", Done: true},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "ユースケースの検証エラー",
+			contentType:    "application/merge-patch+json",
+			body:           `{"title": "新しいタイトル"}`,
+			patch:          &usecase.TodoPatch{Title: &title},
+			err:            errors.NewInvalidInputError("タイトルに不正なSQL構文が含まれています"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "存在しないID",
+			contentType:    "application/merge-patch+json",
+			body:           `{"done": true}`,
+			patch:          &usecase.TodoPatch{Done: &done},
+			err:            errors.NewNotFoundError("ID 1 のTodoが見つかりません"),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "未完了のサブタスクがある（strict）",
+			contentType:    "application/merge-patch+json",
+			body:           `{"done": true}`,
+			patch:          &usecase.TodoPatch{Done: &done},
+			err:            errors.NewConflictError("未完了のサブタスクがあるため完了にできません", usecase.ErrIncompleteSubtasks),
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "タイトルはnullにできない",
+			contentType:    "application/merge-patch+json",
+			body:           `{"title": null}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "doneの型が不正",
+			contentType:    "application/merge-patch+json",
+			body:           `{"done": "yes"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "変更できない項目",
+			contentType:    "application/merge-patch+json",
+			body:           `{"priority": "high"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "オブジェクト以外のボディ",
+			contentType:    "application/merge-patch+json",
+			body:           `["title"]`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "未対応のContent-Type",
+			contentType:    "text/plain",
+			body:           `{"done": true}`,
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// モックの設定
+			mockUseCase := new(MockTodoUseCase)
+			if tc.patch != nil {
+				mockUseCase.On("PatchTodo", "1", *tc.patch, uint(0)).Return(tc.todo, tc.err)
+			}
+			server := NewTodoServer(mockUseCase)
+
+			// リクエスト実行（ルーター経由）
+			req := httptest.NewRequest(http.MethodPatch, "/todos/1", bytes.NewBufferString(tc.body))
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			// 検証
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			if tc.expectedStatus == http.StatusOK {
+				var response domain.Todo
+				json.Unmarshal(w.Body.Bytes(), &response)
+				assert.Equal(t, tc.todo, response)
+			}
+
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package server
+
+import (
+	"net/http"
+)
+
+// searchTodos はタイトルと説明をキーワードで全文検索し、関連度の高い順に返す
+// 一致箇所は title と snippet の中で <mark> で囲んで返す
+func (s *TodoServer) searchTodos(w http.ResponseWriter, r *http.Request) {
+	s.log(r).Info("GET /todos/search リクエストを受信しました")
+	params := r.URL.Query()
+
+	limit, err := parseLimit(params)
+	if err != nil {
+		s.writeError(w, r, err, "クエリパラメータの解析に失敗しました")
+		return
+	}
+
+	results, err := s.todos(r).SearchTodos(params.Get("q"), limit)
+	if err != nil {
+		s.writeError(w, r, err, "Todoの検索中にエラーが発生しました")
+		return
+	}
+
+	s.writeJSON(w, r, http.StatusOK, results)
+	s.log(r).Infof("%d 件の検索結果を返却しました", len(results))
+}
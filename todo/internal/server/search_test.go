@@ -0,0 +1,96 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTodos(t *testing.T) {
+	results := []domain.SearchResult{
+		{Todo: domain.Todo{ID: 1, Title: "会議資料の作成"}, Rank: -2.5, Title: "<mark>会議資料</mark>の作成"},
+	}
+
+	testCases := []struct {
+		name           string
+		url            string
+		text           string
+		limit          int
+		called         bool
+		results        []domain.SearchResult
+		err            error
+		expectedStatus int
+	}{
+		{
+			name:           "正常系",
+			url:            "/todos/search?q=%E4%BC%9A%E8%AD%B0%E8%B3%87%E6%96%99",
+			text:           "会議資料",
+			called:         true,
+			results:        results,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "件数を指定",
+			url:            "/todos/search?q=meeting&limit=5",
+			text:           "meeting",
+			limit:          5,
+			called:         true,
+			results:        []domain.SearchResult{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "キーワードなし",
+			url:            "/todos/search",
+			called:         true,
+			results:        []domain.SearchResult{},
+			err:            errors.NewInvalidInputError("検索キーワードを指定してください"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "無効な件数",
+			url:            "/todos/search?q=meeting&limit=0",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "検索に失敗",
+			url:            "/todos/search?q=meeting",
+			text:           "meeting",
+			called:         true,
+			results:        []domain.SearchResult{},
+			err:            errors.NewInternalError("Todoの検索に失敗しました"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// モックの設定
+			mockUseCase := new(MockTodoUseCase)
+			if tc.called {
+				mockUseCase.On("SearchTodos", tc.text, tc.limit).Return(tc.results, tc.err)
+			}
+			server := NewTodoServer(mockUseCase)
+
+			// リクエスト実行（ルーター経由）
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			// 検証
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			if tc.expectedStatus == http.StatusOK {
+				var response []domain.SearchResult
+				json.Unmarshal(w.Body.Bytes(), &response)
+				assert.Equal(t, tc.results, response)
+			}
+
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
@@ -1,21 +1,64 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	stderrors "errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
 	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/usecase"
 	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
 	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 // TodoServer はHTTPリクエストを処理するサーバー
 type TodoServer struct {
-	router  *mux.Router
-	useCase usecase.TodoUseCaseInterface // インターフェースを使用
-    logger  *logger.Logger   
+	router     *mux.Router
+	useCase    usecase.TodoUseCaseInterface // インターフェースを使用
+	tagUseCase usecase.TagUseCaseInterface  // 未設定の場合はタグ関連のルートを登録しない
+	logger     *logger.Logger
+
+	requestTimeout  time.Duration        // 1つのリクエストを処理できる時間（0以下の場合は制限しない）
+	idempotencyTTL  time.Duration        // Idempotency-Keyとレスポンスを保存しておく期間
+	idempotencyKeys *idempotencyStore    // idempotencyTTL が0以下の場合は nil
+	readinessChecks []readinessCheck     // /readyz で確認する項目
+	buildInfo       VersionResponse      // /version で返すビルドの情報
+	metricsRegistry *prometheus.Registry // /metrics で公開するメトリクスのレジストリ
+	metrics         *httpMetrics         // HTTPリクエストについて記録するメトリクス
+	adminToken      string               // 管理用エンドポイントで要求するトークン（空の場合は登録しない）
+
+	httpServer *http.Server  // Start で使用するサーバー（時間制限は Option で設定する）
+	ready      chan struct{} // 待ち受けを始めた時点で閉じる
+	addr       string        // 待ち受けているアドレス（ready が閉じた後に参照する）
+}
+
+// Option はTodoServerの任意設定
+type Option func(*TodoServer)
+
+// WithTagUseCase はタグ関連のエンドポイントで使用するユースケースを設定する
+func WithTagUseCase(tagUseCase usecase.TagUseCaseInterface) Option {
+	return func(s *TodoServer) {
+		s.tagUseCase = tagUseCase
+	}
+}
+
+// CreateTodoRequest はTODOを作成するためのリクエスト
+type CreateTodoRequest struct {
+	ParentID    *uint      `json:"parent_id,omitempty"`
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	Priority    string     `json:"priority,omitempty"`
+	StartAt     *time.Time `json:"start_at,omitempty"`
+	DueAt       *time.Time `json:"due_at,omitempty"`
+	Recurrence  string     `json:"recurrence,omitempty"`
 }
 
 // UpdateStatusRequest はTODOの完了状態を更新するためのリクエスト
@@ -23,13 +66,44 @@ type UpdateStatusRequest struct {
 	Done bool `json:"done"`
 }
 
+// UpdateScheduleRequest はTODOの開始日時と期限日時を更新するためのリクエスト
+type UpdateScheduleRequest struct {
+	StartAt *time.Time `json:"start_at"`
+	DueAt   *time.Time `json:"due_at"`
+}
+
+// UpdatePriorityRequest はTODOの優先度を更新するためのリクエスト
+type UpdatePriorityRequest struct {
+	Priority string `json:"priority"`
+}
+
 // NewTodoServer は新しいTodoServerインスタンスを作成する
-func NewTodoServer(useCase usecase.TodoUseCaseInterface) *TodoServer {
+func NewTodoServer(useCase usecase.TodoUseCaseInterface, opts ...Option) *TodoServer {
 	s := &TodoServer{
 		router:  mux.NewRouter(),
 		useCase: useCase,
-        logger:  logger.GetLogger(),
+        logger:  logger.GetLogger().Named(logger.ComponentServer),
+		requestTimeout: DefaultRequestTimeout,
+		idempotencyTTL: DefaultIdempotencyTTL,
+		ready:          make(chan struct{}),
+		buildInfo:      newVersionResponse(time.Now()),
+	}
+	s.httpServer = &http.Server{
+		Handler:      s,
+		ReadTimeout:  DefaultReadTimeout,
+		WriteTimeout: DefaultWriteTimeout,
+		IdleTimeout:  DefaultIdleTimeout,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.idempotencyTTL > 0 {
+		s.idempotencyKeys = newIdempotencyStore(s.idempotencyTTL)
+	}
+	if s.metricsRegistry == nil {
+		s.metricsRegistry = prometheus.NewRegistry()
 	}
+	s.metrics = newHTTPMetrics(s.metricsRegistry)
 	s.routes()
 	return s
 }
@@ -38,92 +112,228 @@ func NewTodoServer(useCase usecase.TodoUseCaseInterface) *TodoServer {
 func (s *TodoServer) routes() {
 	s.router.HandleFunc("/todos", s.getTodos).Methods("GET")
 	s.router.HandleFunc("/todos", s.createTodo).Methods("POST")
+	s.router.HandleFunc("/todos/bulk", s.bulkTodos).Methods("POST")
+	s.router.HandleFunc("/todos/search", s.searchTodos).Methods("GET")
+	s.router.HandleFunc("/todos/trash", s.getTrash).Methods("GET")
 	s.router.HandleFunc("/todos/{id}", s.updateTodo).Methods("PUT")
+	s.router.HandleFunc("/todos/{id}", s.patchTodo).Methods("PATCH")
+	s.router.HandleFunc("/todos/{id}/schedule", s.updateSchedule).Methods("PUT")
+	s.router.HandleFunc("/todos/{id}/priority", s.updatePriority).Methods("PUT")
+	s.router.HandleFunc("/todos/{id}/subtasks", s.getSubtasks).Methods("GET")
+	s.router.HandleFunc("/todos/{id}/subtasks", s.createSubtask).Methods("POST")
+	s.router.HandleFunc("/todos/{id}/restore", s.restoreTodo).Methods("POST")
+	s.router.HandleFunc("/todos/{id}/history", s.getHistory).Methods("GET")
+	s.router.HandleFunc("/audit", s.getAuditLog).Methods("GET")
 	s.router.HandleFunc("/todos/{id}", s.deleteTodo).Methods("DELETE")
-}
 
-// Start はサーバーを指定されたアドレスで起動する
-func (s *TodoServer) Start(addr string) error {
-	log.Printf("Starting server on %s", addr)
-	return http.ListenAndServe(addr, s)
+	if s.tagUseCase != nil {
+		s.tagRoutes()
+	}
+	s.healthRoutes()
+	s.metricsRoutes()
+	if s.adminToken != "" {
+		s.adminRoutes()
+	}
+	s.router.Use(s.accessLog, s.instrument, s.withTimeout, s.idempotency)
+
+	// どのルートにも一致しなかったリクエストも、アクセスログとメトリクスに含める
+	s.router.NotFoundHandler = s.accessLog(s.instrument(http.NotFoundHandler()))
+	s.router.MethodNotAllowedHandler = s.accessLog(s.instrument(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	})))
 }
 
 // ServeHTTP はHTTPリクエストを処理する
+// すべてのリクエストにリクエストIDを割り当て、処理中のログとレスポンスのヘッダーに含める
 func (s *TodoServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	s.router.ServeHTTP(w, r)
+	s.router.ServeHTTP(w, withRequestID(w, r))
 }
 
-// getTodos はすべてのTODOを取得する
+// getTodos はクエリパラメータの条件に一致するTODOを取得する
 func (s *TodoServer) getTodos(w http.ResponseWriter, r *http.Request) {
-    s.logger.Info("GET /todos リクエストを受信しました")
-    todos, err := s.useCase.GetTodos()
+    s.log(r).Info("GET /todos リクエストを受信しました")
+    query, err := parseTodoQuery(r)
     if err != nil {
-        s.logger.Errorf("Todoの取得中にエラーが発生しました: %v", err)
-        http.Error(w, "Todoの取得中にエラーが発生しました", http.StatusInternalServerError)
+        s.log(r).Errorf("クエリパラメータの解析に失敗しました: %v", err)
+        http.Error(w, err.Error(), http.StatusBadRequest)
         return
     }
-    
+
+    page, err := s.todos(r).GetTodoPage(query)
+    if err != nil {
+        s.writeError(w, r, err, "Todoの取得中にエラーが発生しました")
+        return
+    }
+
+    // ページングの情報はヘッダーで返し、ボディは従来どおりTodoの配列とする
+    w.Header().Set("X-Total-Count", strconv.FormatInt(page.Total, 10))
+    if page.NextCursor != "" {
+        w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, nextPageURL(r, page.NextCursor)))
+    }
     w.Header().Set("Content-Type", "application/json")
     w.WriteHeader(http.StatusOK)
     
-    if err := json.NewEncoder(w).Encode(todos); err != nil {
-        s.logger.Errorf("Todoのエンコード中にエラーが発生しました: %v", err)
+    if err := json.NewEncoder(w).Encode(page.Todos); err != nil {
+        s.log(r).Errorf("Todoのエンコード中にエラーが発生しました: %v", err)
         http.Error(w, "Todoのエンコード中にエラーが発生しました", http.StatusInternalServerError)
         return
     }
-    s.logger.Infof("%d 件のTodoを返却しました", len(todos))
+    s.log(r).Infof("%d 件のTodoを返却しました（全 %d 件）", len(page.Todos), page.Total)
+}
+
+// nextPageURL は次のページを取得するためのURL（パスとクエリ）を作成する
+// 絞り込みや並び替えの条件はそのまま引き継ぎ、afterだけを置き換える
+func nextPageURL(r *http.Request, cursor string) string {
+	params := r.URL.Query()
+	params.Set("after", cursor)
+	return r.URL.Path + "?" + params.Encode()
+}
+
+// parseTodoQuery はクエリパラメータからTODO一覧の絞り込み条件を作成する
+func parseTodoQuery(r *http.Request) (domain.TodoQuery, error) {
+	var query domain.TodoQuery
+	params := r.URL.Query()
+
+	if v := params.Get("overdue"); v != "" {
+		overdue, err := strconv.ParseBool(v)
+		if err != nil {
+			return domain.TodoQuery{}, errors.NewInvalidInputError("overdueにはtrueまたはfalseを指定してください", err)
+		}
+		query.Overdue = overdue
+	}
+
+	for _, tag := range params["tag"] {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			query.Tags = append(query.Tags, tag)
+		}
+	}
+	tagMatch, err := domain.ParseTagMatch(params.Get("tag_mode"))
+	if err != nil {
+		return domain.TodoQuery{}, errors.NewInvalidInputError("tag_modeにはanyまたはallを指定してください", err)
+	}
+	if len(query.Tags) > 0 {
+		query.TagMatch = tagMatch
+	}
+
+	sort, err := domain.ParseSortField(params.Get("sort"))
+	if err != nil {
+		return domain.TodoQuery{}, errors.NewInvalidInputError("sortには priority, created, due, title のいずれかを指定してください", err)
+	}
+	query.Sort = sort
+
+	switch params.Get("order") {
+	case "", "asc":
+		query.Desc = false
+	case "desc":
+		query.Desc = true
+	default:
+		return domain.TodoQuery{}, errors.NewInvalidInputError("orderにはascまたはdescを指定してください")
+	}
+
+	if v := params.Get("done"); v != "" {
+		done, err := strconv.ParseBool(v)
+		if err != nil {
+			return domain.TodoQuery{}, errors.NewInvalidInputError("doneにはtrueまたはfalseを指定してください", err)
+		}
+		query.Done = &done
+	}
+	query.Search = strings.TrimSpace(params.Get("q"))
+
+	limit, err := parseLimit(params)
+	if err != nil {
+		return domain.TodoQuery{}, err
+	}
+	query.Limit = limit
+	if v := params.Get("after"); v != "" {
+		cursor, err := domain.ParseCursor(v)
+		if err != nil {
+			return domain.TodoQuery{}, errors.NewInvalidInputError("afterには前のページのLinkヘッダーで返されたカーソルを指定してください", err)
+		}
+		query.After = &cursor
+	}
+	if query.AsOf, err = parseTimeParam(params, "as_of"); err != nil {
+		return domain.TodoQuery{}, err
+	}
+	return query, nil
+}
+
+// 1ページで取得できる最大件数
+const maxPageLimit = 100
+
+// parseLimit はクエリパラメータの limit を解析する（未指定の場合は0）
+func parseLimit(params url.Values) (int, error) {
+	v := params.Get("limit")
+	if v == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(v)
+	if err != nil || limit < 1 || limit > maxPageLimit {
+		return 0, errors.NewInvalidInputError(fmt.Sprintf("limitには1から%dまでの整数を指定してください", maxPageLimit))
+	}
+	return limit, nil
 }
 
 // createTodo は新しいTODOを作成する
 func (s *TodoServer) createTodo(w http.ResponseWriter, r *http.Request) {
-    s.logger.Info("POST /todos リクエストを受信しました")
-	var req struct {
-		Title string `json:"title"`
-	}
+    s.log(r).Info("POST /todos リクエストを受信しました")
+	var req CreateTodoRequest
     if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-        s.logger.Errorf("リクエストボディの解析に失敗しました: %v", err)
+        s.log(r).Errorf("リクエストボディの解析に失敗しました: %v", err)
         http.Error(w, "リクエストボディの解析に失敗しました", http.StatusBadRequest)
         return
     }
-    s.logger.Debugf("リクエスト内容: title=%s", req.Title)
+    s.log(r).Debugw("リクエスト内容", "title", req.Title)
 
     if req.Title == "" {
-        s.logger.Error("タイトルは必須です")
+        s.log(r).Error("タイトルは必須です")
         http.Error(w, "タイトルは必須です", http.StatusBadRequest)
         return
     }
 
-    todo, err := s.useCase.CreateTodo(req.Title)
+    todo, err := s.todos(r).CreateTodo(usecase.CreateTodoInput{
+        ParentID:    req.ParentID,
+        Title:       req.Title,
+        Description: req.Description,
+        Priority:    req.Priority,
+        StartAt:     req.StartAt,
+        DueAt:       req.DueAt,
+        Recurrence:  req.Recurrence,
+    })
     if err != nil {
         if errors.IsInvalidInput(err) {
-            s.logger.Errorf("無効な入力です: %v", err)
+            s.log(r).Errorf("無効な入力です: %v", err)
             http.Error(w, err.Error(), http.StatusBadRequest)
             return
         }
-        s.logger.Errorf("Todoの作成中にエラーが発生しました: %v", err)
-        http.Error(w, "Todoの作成中にエラーが発生しました", http.StatusInternalServerError)
+        if errors.IsNotFound(err) {
+            s.log(r).Errorf("親タスクが見つかりません: %v", err)
+            http.Error(w, err.Error(), http.StatusNotFound)
+            return
+        }
+        s.writeError(w, r, err, "Todoの作成中にエラーが発生しました")
         return
     }
     
+    setETag(w, todo.Version)
     w.Header().Set("Content-Type", "application/json")
     w.WriteHeader(http.StatusCreated)
     
     if err := json.NewEncoder(w).Encode(todo); err != nil {
-        s.logger.Errorf("Todoのエンコード中にエラーが発生しました: %v", err)
+        s.log(r).Errorf("Todoのエンコード中にエラーが発生しました: %v", err)
         http.Error(w, "Todoのエンコード中にエラーが発生しました", http.StatusInternalServerError)
         return
     }
-    s.logger.Infof("新しいTodoを作成しました: id=%d, title=%s", todo.ID, todo.Title)
+    s.log(r).Infow("新しいTodoを作成しました", "id", todo.ID, "title", todo.Title)
 }
 
 // updateTodo は指定されたTODOを更新する
 func (s *TodoServer) updateTodo(w http.ResponseWriter, r *http.Request) {
-    s.logger.Info("PUT /todos/{id} リクエストを受信しました")
+    s.log(r).Info("PUT /todos/{id} リクエストを受信しました")
 	vars := mux.Vars(r)
 	id := vars["id"]
 
 	if id == "" {
-        s.logger.Error("IDは必須です")
+        s.log(r).Error("IDは必須です")
         http.Error(w, "IDは必須です", http.StatusBadRequest)
         return
     }
@@ -133,54 +343,239 @@ func (s *TodoServer) updateTodo(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
 		return
 	}
+
+    version, err := ifMatchVersion(r)
+    if err != nil {
+        s.writeError(w, r, err, "If-Matchヘッダーの解析に失敗しました")
+        return
+    }
     
-    todo, err := s.useCase.UpdateTodo(id, req.Done)
+    todo, err := s.todos(r).UpdateTodo(id, req.Done, version)
     if err != nil {
+        if errors.IsInvalidInput(err) {
+            s.log(r).Errorf("無効な入力です: %v", err)
+            http.Error(w, err.Error(), http.StatusBadRequest)
+            return
+        }
         if errors.IsNotFound(err) {
-            s.logger.Errorf("指定されたTodoが見つかりません: %v", err)
+            s.log(r).Errorf("指定されたTodoが見つかりません: %v", err)
             http.Error(w, err.Error(), http.StatusNotFound)
             return
         }
-        s.logger.Errorf("Todoの更新中にエラーが発生しました: %v", err)
-        http.Error(w, "Todoの更新中にエラーが発生しました", http.StatusInternalServerError)
+        if errors.IsConflict(err) {
+            s.log(r).Errorf("Todoが競合しています: %v", err)
+            http.Error(w, err.Error(), conflictStatus(err))
+            return
+        }
+        s.writeError(w, r, err, "Todoの更新中にエラーが発生しました")
         return
     }
     
+    setETag(w, todo.Version)
     w.Header().Set("Content-Type", "application/json")
     w.WriteHeader(http.StatusOK)
     
     if err := json.NewEncoder(w).Encode(todo); err != nil {
-        s.logger.Errorf("Todoのエンコード中にエラーが発生しました: %v", err)
+        s.log(r).Errorf("Todoのエンコード中にエラーが発生しました: %v", err)
         http.Error(w, "Todoのエンコード中にエラーが発生しました", http.StatusInternalServerError)
         return
     }
-    s.logger.Infof("Todoを更新しました: id=%s, title=%s", id, todo.Title)
+    s.log(r).Infow("Todoを更新しました", "id", id, "title", todo.Title)
+}
+
+// updateSchedule は指定されたTODOの開始日時と期限日時を更新する
+func (s *TodoServer) updateSchedule(w http.ResponseWriter, r *http.Request) {
+	s.log(r).Info("PUT /todos/{id}/schedule リクエストを受信しました")
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	if id == "" {
+		s.log(r).Error("IDは必須です")
+		http.Error(w, "IDは必須です", http.StatusBadRequest)
+		return
+	}
+
+	var req UpdateScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.log(r).Errorf("リクエストボディの解析に失敗しました: %v", err)
+		http.Error(w, "リクエストボディの解析に失敗しました", http.StatusBadRequest)
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		s.writeError(w, r, err, "If-Matchヘッダーの解析に失敗しました")
+		return
+	}
+
+	todo, err := s.todos(r).UpdateSchedule(id, req.StartAt, req.DueAt, version)
+	if err != nil {
+		if errors.IsInvalidInput(err) {
+			s.log(r).Errorf("無効な入力です: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.IsNotFound(err) {
+			s.log(r).Errorf("指定されたTodoが見つかりません: %v", err)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.IsConflict(err) {
+			s.log(r).Errorf("Todoのバージョンが一致しません: %v", err)
+			http.Error(w, err.Error(), http.StatusPreconditionFailed)
+			return
+		}
+		s.writeError(w, r, err, "Todoの更新中にエラーが発生しました")
+		return
+	}
+
+	setETag(w, todo.Version)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(todo); err != nil {
+		s.log(r).Errorf("Todoのエンコード中にエラーが発生しました: %v", err)
+		http.Error(w, "Todoのエンコード中にエラーが発生しました", http.StatusInternalServerError)
+		return
+	}
+	s.log(r).Infof("Todoの予定を更新しました: id=%s", id)
+}
+
+// updatePriority は指定されたTODOの優先度を更新する
+func (s *TodoServer) updatePriority(w http.ResponseWriter, r *http.Request) {
+	s.log(r).Info("PUT /todos/{id}/priority リクエストを受信しました")
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	if id == "" {
+		s.log(r).Error("IDは必須です")
+		http.Error(w, "IDは必須です", http.StatusBadRequest)
+		return
+	}
+
+	var req UpdatePriorityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.log(r).Errorf("リクエストボディの解析に失敗しました: %v", err)
+		http.Error(w, "リクエストボディの解析に失敗しました", http.StatusBadRequest)
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		s.writeError(w, r, err, "If-Matchヘッダーの解析に失敗しました")
+		return
+	}
+
+	todo, err := s.todos(r).UpdatePriority(id, req.Priority, version)
+	if err != nil {
+		if errors.IsInvalidInput(err) {
+			s.log(r).Errorf("無効な入力です: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.IsNotFound(err) {
+			s.log(r).Errorf("指定されたTodoが見つかりません: %v", err)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.IsConflict(err) {
+			s.log(r).Errorf("Todoのバージョンが一致しません: %v", err)
+			http.Error(w, err.Error(), http.StatusPreconditionFailed)
+			return
+		}
+		s.writeError(w, r, err, "Todoの更新中にエラーが発生しました")
+		return
+	}
+
+	setETag(w, todo.Version)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(todo); err != nil {
+		s.log(r).Errorf("Todoのエンコード中にエラーが発生しました: %v", err)
+		http.Error(w, "Todoのエンコード中にエラーが発生しました", http.StatusInternalServerError)
+		return
+	}
+	s.log(r).Infof("Todoの優先度を更新しました: id=%s, priority=%s", id, todo.Priority)
 }
 
 // deleteTodo は指定されたTODOを削除する
 func (s *TodoServer) deleteTodo(w http.ResponseWriter, r *http.Request) {
-    s.logger.Info("DELETE /todos/{id} リクエストを受信しました")
+    s.log(r).Info("DELETE /todos/{id} リクエストを受信しました")
 	vars := mux.Vars(r)
 	id := vars["id"]
 
 	if id == "" {
-        s.logger.Error("IDは必須です")
+        s.log(r).Error("IDは必須です")
         http.Error(w, "IDは必須です", http.StatusBadRequest)
         return
     }
     
-    err := s.useCase.DeleteTodoByID(id)
+    version, err := ifMatchVersion(r)
+    if err != nil {
+        s.writeError(w, r, err, "If-Matchヘッダーの解析に失敗しました")
+        return
+    }
+
+    err = s.todos(r).DeleteTodoByID(id, version)
     if err != nil {
         if errors.IsNotFound(err) {
-            s.logger.Errorf("指定されたTodoが見つかりません: %v", err)
+            s.log(r).Errorf("指定されたTodoが見つかりません: %v", err)
             http.Error(w, err.Error(), http.StatusNotFound)
             return
         }
-        s.logger.Errorf("Todoの削除中にエラーが発生しました: %v", err)
-        http.Error(w, "Todoの削除中にエラーが発生しました", http.StatusInternalServerError)
+        if errors.IsConflict(err) {
+            s.log(r).Errorf("Todoのバージョンが一致しません: %v", err)
+            http.Error(w, err.Error(), http.StatusPreconditionFailed)
+            return
+        }
+        s.writeError(w, r, err, "Todoの削除中にエラーが発生しました")
         return
     }
     
     w.WriteHeader(http.StatusNoContent)
-    s.logger.Infof("Todoを削除しました: id=%s", id)
+    s.log(r).Infof("Todoをゴミ箱に移動しました: id=%s", id)
+}
+
+// writeError はユースケースのエラーを種別に応じたステータスコードで返す
+// 競合エラーは conflictStatus のステータスコードを返す
+// リクエストを処理できる時間を過ぎて中断した場合は503を返す
+// 内部エラーの場合は詳細を隠し、messageのみを返す
+func (s *TodoServer) writeError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	switch {
+	case errors.IsInvalidInput(err):
+		s.log(r).Errorf("無効な入力です: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.IsNotFound(err):
+		s.log(r).Errorf("指定されたリソースが見つかりません: %v", err)
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.IsConflict(err):
+		s.log(r).Errorf("Todoが競合しています: %v", err)
+		http.Error(w, err.Error(), conflictStatus(err))
+	case stderrors.Is(err, context.DeadlineExceeded), stderrors.Is(err, context.Canceled):
+		s.log(r).Errorf("%s（処理を中断しました）: %v", message, err)
+		http.Error(w, "時間内に処理を完了できませんでした", http.StatusServiceUnavailable)
+	default:
+		s.log(r).Errorf("%s: %v", message, err)
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
+
+// conflictStatus は競合エラーのステータスコードを返す
+// 未完了のサブタスクがあるなど他のTODOの状態による競合は409、それ以外はIf-Matchで指定したバージョンと一致しなかったものとして412を返す
+func conflictStatus(err error) int {
+	if stderrors.Is(err, usecase.ErrIncompleteSubtasks) {
+		return http.StatusConflict
+	}
+	return http.StatusPreconditionFailed
+}
+
+// writeJSON は値をJSONとしてレスポンスに書き込む
+func (s *TodoServer) writeJSON(w http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.log(r).Errorf("レスポンスのエンコード中にエラーが発生しました: %v", err)
+	}
 }
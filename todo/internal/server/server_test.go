@@ -2,10 +2,12 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
@@ -18,51 +20,272 @@ import (
 // MockTodoUseCase は usecase.TodoUseCaseInterface のモック実装です
 type MockTodoUseCase struct {
 	mock.Mock
+	actor string          // WithActor で設定された操作者
+	ctx   context.Context // WithContext で設定された context
 }
 
 // インターフェースを実装していることを確認
 var _ usecase.TodoUseCaseInterface = (*MockTodoUseCase)(nil)
 
-// GetTodos は全てのTodoを取得するメソッドのモックです
-func (m *MockTodoUseCase) GetTodos() ([]domain.Todo, error) {
-	args := m.Called()
+// GetTodos は条件に一致するTodoを取得するメソッドのモックです
+func (m *MockTodoUseCase) GetTodos(query domain.TodoQuery) ([]domain.Todo, error) {
+	args := m.Called(query)
 	return args.Get(0).([]domain.Todo), args.Error(1)
 }
 
+// GetTodoPage は条件に一致するTodoをページ単位で取得するメソッドのモックです
+func (m *MockTodoUseCase) GetTodoPage(query domain.TodoQuery) (usecase.TodoPage, error) {
+	args := m.Called(query)
+	return args.Get(0).(usecase.TodoPage), args.Error(1)
+}
+
 // CreateTodo は新しいTodoを作成するメソッドのモックです
-func (m *MockTodoUseCase) CreateTodo(title string) (domain.Todo, error) {
-	args := m.Called(title)
+func (m *MockTodoUseCase) CreateTodo(input usecase.CreateTodoInput) (domain.Todo, error) {
+	args := m.Called(input)
 	return args.Get(0).(domain.Todo), args.Error(1)
 }
 
-// DeleteTodoByID はIDを指定してTodoを削除するメソッドのモックです
-func (m *MockTodoUseCase) DeleteTodoByID(id string) error {
+// GetSubtasks はIDを指定してサブタスクを取得するメソッドのモックです
+func (m *MockTodoUseCase) GetSubtasks(id string) ([]domain.Todo, error) {
 	args := m.Called(id)
+	return args.Get(0).([]domain.Todo), args.Error(1)
+}
+
+// UpdatePriority はIDを指定してTodoの優先度を更新するメソッドのモックです
+func (m *MockTodoUseCase) UpdatePriority(id string, priority string, version uint) (domain.Todo, error) {
+	args := m.Called(id, priority, version)
+	return args.Get(0).(domain.Todo), args.Error(1)
+}
+
+// UpdateSchedule はIDを指定してTodoの予定を更新するメソッドのモックです
+func (m *MockTodoUseCase) UpdateSchedule(id string, startAt, dueAt *time.Time, version uint) (domain.Todo, error) {
+	args := m.Called(id, startAt, dueAt, version)
+	return args.Get(0).(domain.Todo), args.Error(1)
+}
+
+// DeleteTodoByID はIDを指定してTodoを削除するメソッドのモックです
+func (m *MockTodoUseCase) DeleteTodoByID(id string, version uint) error {
+	args := m.Called(id, version)
 	return args.Error(0)
 }
 
 // updateTodoByID はIDを指定してTodoを完了状態にするメソッドのモックです
-func (m *MockTodoUseCase) UpdateTodo(id string, done bool) (domain.Todo, error) {
- 	args := m.Called(id, done)
+func (m *MockTodoUseCase) UpdateTodo(id string, done bool, version uint) (domain.Todo, error) {
+ 	args := m.Called(id, done, version)
 	return args.Get(0).(domain.Todo), args.Error(1)
 }
 
+// SearchTodos はキーワードでTodoを検索するメソッドのモックです
+func (m *MockTodoUseCase) SearchTodos(text string, limit int) ([]domain.SearchResult, error) {
+	args := m.Called(text, limit)
+	return args.Get(0).([]domain.SearchResult), args.Error(1)
+}
+
+func (m *MockTodoUseCase) PatchTodo(id string, patch usecase.TodoPatch, version uint) (domain.Todo, error) {
+	args := m.Called(id, patch, version)
+	return args.Get(0).(domain.Todo), args.Error(1)
+}
+
+// GetTrash はゴミ箱のTodoを取得するメソッドのモックです
+func (m *MockTodoUseCase) GetTrash() ([]domain.Todo, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.Todo), args.Error(1)
+}
+
+// RestoreTodo はIDを指定してゴミ箱のTodoを元に戻すメソッドのモックです
+func (m *MockTodoUseCase) RestoreTodo(id string, version uint) (domain.Todo, error) {
+	args := m.Called(id, version)
+	return args.Get(0).(domain.Todo), args.Error(1)
+}
+
+// PurgeTrash は保持期間を過ぎたゴミ箱のTodoを完全に削除するメソッドのモックです
+func (m *MockTodoUseCase) PurgeTrash() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// BulkTodos は複数のTodoをまとめて作成・更新・削除するメソッドのモックです
+func (m *MockTodoUseCase) BulkTodos(ops []usecase.BulkOperation, mode usecase.BulkMode) (usecase.BulkResult, error) {
+	args := m.Called(ops, mode)
+	return args.Get(0).(usecase.BulkResult), args.Error(1)
+}
+
+// GetHistory はIDを指定してTodoの変更履歴を取得するメソッドのモックです
+func (m *MockTodoUseCase) GetHistory(id string) ([]domain.AuditEntry, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.AuditEntry), args.Error(1)
+}
+
+// GetAuditLog は条件に一致する監査ログを取得するメソッドのモックです
+func (m *MockTodoUseCase) GetAuditLog(query domain.AuditQuery) ([]domain.AuditEntry, error) {
+	args := m.Called(query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.AuditEntry), args.Error(1)
+}
+
+// WithActor は操作者を記録して自身を返します（呼び出しの期待値は設定不要です）
+func (m *MockTodoUseCase) WithActor(actor string) usecase.TodoUseCaseInterface {
+	m.actor = actor
+	return m
+}
+
+// WithContext は context を記録して自身を返します（呼び出しの期待値は設定不要です）
+func (m *MockTodoUseCase) WithContext(ctx context.Context) usecase.TodoUseCaseInterface {
+	m.ctx = ctx
+	return m
+}
+
 func TestGetTodos(t *testing.T) {
+    notDone := false
+    cursor := domain.NewCursor(domain.Todo{ID: 5, Title: "資料作成"}, domain.SortByTitle, false)
+    asOf := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+
     // テストケース
     testCases := []struct {
         name        string
+        url         string
+        query       *domain.TodoQuery
         todos       []domain.Todo
         err         error
         expectedStatus int
     }{
         {
             name: "正常系",
+            url: "/todos",
+            query: &domain.TodoQuery{},
             todos: []domain.Todo{{ID: 1, Title: "Test Todo", Done: false}},
             err: nil,
             expectedStatus: http.StatusOK,
         },
+        {
+            name: "期限切れで絞り込み",
+            url: "/todos?overdue=true",
+            query: &domain.TodoQuery{Overdue: true},
+            todos: []domain.Todo{{ID: 2, Title: "Overdue Todo", Done: false}},
+            err: nil,
+            expectedStatus: http.StatusOK,
+        },
+        {
+            name: "優先度の降順で並び替え",
+            url: "/todos?sort=priority&order=desc",
+            query: &domain.TodoQuery{Sort: domain.SortByPriority, Desc: true},
+            todos: []domain.Todo{{ID: 3, Title: "Urgent Todo", Priority: domain.PriorityUrgent}},
+            err: nil,
+            expectedStatus: http.StatusOK,
+        },
+        {
+            name: "不明な並び替え項目",
+            url: "/todos?sort=color",
+            query: nil,
+            todos: nil,
+            err: nil,
+            expectedStatus: http.StatusBadRequest,
+        },
+        {
+            name: "不明な並び順",
+            url: "/todos?sort=due&order=up",
+            query: nil,
+            todos: nil,
+            err: nil,
+            expectedStatus: http.StatusBadRequest,
+        },
+        {
+            name: "タグで絞り込み（すべて一致）",
+            url: "/todos?tag=仕事&tag=急ぎ&tag_mode=all",
+            query: &domain.TodoQuery{Tags: []string{"仕事", "急ぎ"}, TagMatch: domain.TagMatchAll},
+            todos: []domain.Todo{{ID: 4, Title: "Tagged Todo", Tags: []domain.Tag{{ID: 1, Name: "仕事"}, {ID: 2, Name: "急ぎ"}}}},
+            err: nil,
+            expectedStatus: http.StatusOK,
+        },
+        {
+            name: "不明なタグの一致条件",
+            url: "/todos?tag=仕事&tag_mode=some",
+            query: nil,
+            todos: nil,
+            err: nil,
+            expectedStatus: http.StatusBadRequest,
+        },
+        {
+            name: "無効なoverdueパラメータ",
+            url: "/todos?overdue=maybe",
+            query: nil,
+            todos: nil,
+            err: nil,
+            expectedStatus: http.StatusBadRequest,
+        },
+        {
+            name: "完了状態とキーワードで絞り込み",
+            url: "/todos?done=false&q=+%E8%B3%87%E6%96%99+",
+            query: &domain.TodoQuery{Done: &notDone, Search: "資料"},
+            todos: []domain.Todo{{ID: 5, Title: "資料作成", Done: false}},
+            err: nil,
+            expectedStatus: http.StatusOK,
+        },
+        {
+            name: "件数とカーソルを指定",
+            url: "/todos?sort=title&limit=10&after=" + cursor.Encode(),
+            query: &domain.TodoQuery{Sort: domain.SortByTitle, Limit: 10, After: &cursor},
+            todos: []domain.Todo{{ID: 6, Title: "Next Todo"}},
+            err: nil,
+            expectedStatus: http.StatusOK,
+        },
+        {
+            name: "無効なdoneパラメータ",
+            url: "/todos?done=maybe",
+            query: nil,
+            todos: nil,
+            err: nil,
+            expectedStatus: http.StatusBadRequest,
+        },
+        {
+            name: "過去の時点を指定",
+            url: "/todos?as_of=2025-04-01T09:00:00Z",
+            query: &domain.TodoQuery{AsOf: &asOf},
+            todos: []domain.Todo{{ID: 7, Title: "Past Todo"}},
+            err: nil,
+            expectedStatus: http.StatusOK,
+        },
+        {
+            name: "無効なas_ofパラメータ",
+            url: "/todos?as_of=yesterday",
+            query: nil,
+            todos: nil,
+            err: nil,
+            expectedStatus: http.StatusBadRequest,
+        },
+        {
+            name: "件数が上限を超えている",
+            url: "/todos?limit=101",
+            query: nil,
+            todos: nil,
+            err: nil,
+            expectedStatus: http.StatusBadRequest,
+        },
+        {
+            name: "不正なカーソル",
+            url: "/todos?after=not-a-cursor",
+            query: nil,
+            todos: nil,
+            err: nil,
+            expectedStatus: http.StatusBadRequest,
+        },
+        {
+            name: "並び順が異なるカーソル",
+            url: "/todos?after=" + cursor.Encode(),
+            query: &domain.TodoQuery{After: &cursor},
+            todos: []domain.Todo{},
+            err: errors.NewInvalidInputError("カーソルは同じ並び順で取得したものを指定してください"),
+            expectedStatus: http.StatusBadRequest,
+        },
         {
             name: "エラー発生",
+            url: "/todos",
+            query: &domain.TodoQuery{},
             todos: []domain.Todo{},
             err: errors.NewInternalError("データベースエラー"),
             expectedStatus: http.StatusInternalServerError,
@@ -73,18 +296,20 @@ func TestGetTodos(t *testing.T) {
         t.Run(tc.name, func(t *testing.T) {
             // モックの設定
             mockUseCase := new(MockTodoUseCase)
-            mockUseCase.On("GetTodos").Return(tc.todos, tc.err)
+            if tc.query != nil {
+                mockUseCase.On("GetTodoPage", *tc.query).Return(usecase.TodoPage{Todos: tc.todos, Total: int64(len(tc.todos))}, tc.err)
+            }
             server := NewTodoServer(mockUseCase)
 
             // リクエスト実行
-            req := httptest.NewRequest(http.MethodGet, "/todos", nil)
+            req := httptest.NewRequest(http.MethodGet, tc.url, nil)
             w := httptest.NewRecorder()
             server.getTodos(w, req)
 
             // 検証
             assert.Equal(t, tc.expectedStatus, w.Code)
             
-            if tc.err == nil {
+            if tc.err == nil && tc.expectedStatus == http.StatusOK {
                 assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
                 var response []domain.Todo
                 json.Unmarshal(w.Body.Bytes(), &response)
@@ -96,6 +321,46 @@ func TestGetTodos(t *testing.T) {
     }
 }
 
+func TestGetTodosPagingHeaders(t *testing.T) {
+	next := domain.NewCursor(domain.Todo{ID: 2, Priority: domain.PriorityHigh}, domain.SortByPriority, true)
+
+	testCases := []struct {
+		name         string
+		url          string
+		page         usecase.TodoPage
+		expectedLink string
+	}{
+		{
+			name:         "次のページがある場合は条件を引き継いだLinkを返す",
+			url:          "/todos?sort=priority&order=desc&limit=2&tag=仕事",
+			page:         usecase.TodoPage{Todos: []domain.Todo{{ID: 1}, {ID: 2}}, Total: 5, NextCursor: next.Encode()},
+			expectedLink: `</todos?after=` + next.Encode() + `&limit=2&order=desc&sort=priority&tag=%E4%BB%95%E4%BA%8B>; rel="next"`,
+		},
+		{
+			name:         "最後のページではLinkを返さない",
+			url:          "/todos?sort=priority&order=desc&limit=2&after=" + next.Encode(),
+			page:         usecase.TodoPage{Todos: []domain.Todo{{ID: 3}}, Total: 5},
+			expectedLink: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockUseCase := new(MockTodoUseCase)
+			mockUseCase.On("GetTodoPage", mock.Anything).Return(tc.page, nil)
+			server := NewTodoServer(mockUseCase)
+
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "5", w.Header().Get("X-Total-Count"))
+			assert.Equal(t, tc.expectedLink, w.Header().Get("Link"))
+		})
+	}
+}
+
 func TestCreateTodo(t *testing.T) {
     // テストケース
     testCases := []struct {
@@ -143,7 +408,7 @@ func TestCreateTodo(t *testing.T) {
             // CreateTodoが呼ばれる条件だけ期待設定
             if tc.requestBody != "invalid json" &&
             tc.requestBody != `{"title": ""}` {
-                mockUseCase.On("CreateTodo", mock.AnythingOfType("string")).Return(tc.todo, tc.err)
+                mockUseCase.On("CreateTodo", mock.AnythingOfType("usecase.CreateTodoInput")).Return(tc.todo, tc.err)
             }
 
             server := NewTodoServer(mockUseCase)
@@ -201,7 +466,7 @@ func TestDeleteTodo(t *testing.T) {
         t.Run(tc.name, func(t *testing.T) {
             // モックの設定
             mockUseCase := new(MockTodoUseCase)
-            mockUseCase.On("DeleteTodoByID", tc.id).Return(tc.err)
+            mockUseCase.On("DeleteTodoByID", tc.id, uint(0)).Return(tc.err)
             server := NewTodoServer(mockUseCase)
 
             // リクエスト実行
@@ -251,6 +516,22 @@ func TestUpdateTodo(t *testing.T) {
             err: errors.NewInternalError("データベースエラー"),
             expectedStatus: http.StatusInternalServerError,
         },
+        {
+            name: "未完了のサブタスクがある（strict）",
+            id: "1",
+            body: `{"done": true}`,
+            todo: domain.Todo{Done: true},
+            err: errors.NewConflictError("未完了のサブタスクがあるため完了にできません", usecase.ErrIncompleteSubtasks),
+            expectedStatus: http.StatusConflict,
+        },
+        {
+            name: "バージョンの競合",
+            id: "1",
+            body: `{"done": true}`,
+            todo: domain.Todo{Done: true},
+            err: errors.NewConflictError("ID 1 のTodoは他の操作で更新されています"),
+            expectedStatus: http.StatusPreconditionFailed,
+        },
     }
 
     for _, tc := range testCases {
@@ -258,7 +539,7 @@ func TestUpdateTodo(t *testing.T) {
             // モックの設定
             mockUseCase := new(MockTodoUseCase)
             
-            mockUseCase.On("UpdateTodo", tc.id, tc.todo.Done).Return(tc.todo, tc.err)
+            mockUseCase.On("UpdateTodo", tc.id, tc.todo.Done, uint(0)).Return(tc.todo, tc.err)
             server := NewTodoServer(mockUseCase)
 
             // リクエスト実行
@@ -280,4 +561,137 @@ func TestUpdateTodo(t *testing.T) {
             mockUseCase.AssertExpectations(t)
         })
     }
-}
\ No newline at end of file
+}
+
+func TestUpdateSchedule(t *testing.T) {
+    due := time.Date(2025, 4, 1, 18, 0, 0, 0, time.UTC)
+
+    // テストケース
+    testCases := []struct {
+        name        string
+        id          string
+        body        string
+        todo        domain.Todo
+        err         error
+        expectedStatus int
+    }{
+        {
+            name: "正常系",
+            id: "1",
+            body: `{"due_at": "2025-04-01T18:00:00Z"}`,
+            todo: domain.Todo{ID: 1, Title: "Test Todo", DueAt: &due},
+            err: nil,
+            expectedStatus: http.StatusOK,
+        },
+        {
+            name: "期限日時が開始日時より前",
+            id: "1",
+            body: `{"start_at": "2025-04-02T09:00:00Z", "due_at": "2025-04-01T18:00:00Z"}`,
+            todo: domain.Todo{},
+            err: errors.NewInvalidInputError("期限日時は開始日時より後にしてください"),
+            expectedStatus: http.StatusBadRequest,
+        },
+        {
+            name: "存在しないID",
+            id: "999",
+            body: `{"due_at": "2025-04-01T18:00:00Z"}`,
+            todo: domain.Todo{},
+            err: errors.NewNotFoundError("指定されたIDのTODOが見つかりません"),
+            expectedStatus: http.StatusNotFound,
+        },
+    }
+
+    for _, tc := range testCases {
+        t.Run(tc.name, func(t *testing.T) {
+            // モックの設定
+            mockUseCase := new(MockTodoUseCase)
+            mockUseCase.On("UpdateSchedule", tc.id, mock.Anything, mock.Anything, uint(0)).Return(tc.todo, tc.err)
+            server := NewTodoServer(mockUseCase)
+
+            // リクエスト実行
+            req := httptest.NewRequest(http.MethodPut, "/todos/"+tc.id+"/schedule", bytes.NewBufferString(tc.body))
+            req = mux.SetURLVars(req, map[string]string{"id": tc.id})
+            w := httptest.NewRecorder()
+            server.updateSchedule(w, req)
+
+            // 検証
+            assert.Equal(t, tc.expectedStatus, w.Code)
+
+            if tc.err == nil {
+                var response domain.Todo
+                json.Unmarshal(w.Body.Bytes(), &response)
+                assert.True(t, due.Equal(*response.DueAt))
+            }
+
+            mockUseCase.AssertExpectations(t)
+        })
+    }
+}
+
+func TestUpdatePriority(t *testing.T) {
+    // テストケース
+    testCases := []struct {
+        name        string
+        id          string
+        body        string
+        priority    string
+        todo        domain.Todo
+        err         error
+        expectedStatus int
+    }{
+        {
+            name: "正常系",
+            id: "1",
+            body: `{"priority": "high"}`,
+            priority: "high",
+            todo: domain.Todo{ID: 1, Title: "Test Todo", Priority: domain.PriorityHigh},
+            err: nil,
+            expectedStatus: http.StatusOK,
+        },
+        {
+            name: "不明な優先度",
+            id: "1",
+            body: `{"priority": "highest"}`,
+            priority: "highest",
+            todo: domain.Todo{},
+            err: errors.NewInvalidInputError("優先度は none, low, medium, high, urgent のいずれかを指定してください"),
+            expectedStatus: http.StatusBadRequest,
+        },
+        {
+            name: "存在しないID",
+            id: "999",
+            body: `{"priority": "low"}`,
+            priority: "low",
+            todo: domain.Todo{},
+            err: errors.NewNotFoundError("指定されたIDのTODOが見つかりません"),
+            expectedStatus: http.StatusNotFound,
+        },
+    }
+
+    for _, tc := range testCases {
+        t.Run(tc.name, func(t *testing.T) {
+            // モックの設定
+            mockUseCase := new(MockTodoUseCase)
+            mockUseCase.On("UpdatePriority", tc.id, tc.priority, uint(0)).Return(tc.todo, tc.err)
+            server := NewTodoServer(mockUseCase)
+
+            // リクエスト実行
+            req := httptest.NewRequest(http.MethodPut, "/todos/"+tc.id+"/priority", bytes.NewBufferString(tc.body))
+            req = mux.SetURLVars(req, map[string]string{"id": tc.id})
+            w := httptest.NewRecorder()
+            server.updatePriority(w, req)
+
+            // 検証
+            assert.Equal(t, tc.expectedStatus, w.Code)
+
+            if tc.err == nil {
+                var response domain.Todo
+                json.Unmarshal(w.Body.Bytes(), &response)
+                assert.Equal(t, tc.todo, response)
+                assert.Contains(t, w.Body.String(), `"priority":"high"`)
+            }
+
+            mockUseCase.AssertExpectations(t)
+        })
+    }
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/usecase"
+)
+
+// getSubtasks は指定されたTODOのサブタスクを取得する
+func (s *TodoServer) getSubtasks(w http.ResponseWriter, r *http.Request) {
+	s.log(r).Info("GET /todos/{id}/subtasks リクエストを受信しました")
+	id := mux.Vars(r)["id"]
+
+	subtasks, err := s.todos(r).GetSubtasks(id)
+	if err != nil {
+		s.writeError(w, r, err, "サブタスクの取得中にエラーが発生しました")
+		return
+	}
+
+	s.writeJSON(w, r, http.StatusOK, subtasks)
+	s.log(r).Infof("%d 件のサブタスクを返却しました: id=%s", len(subtasks), id)
+}
+
+// createSubtask は指定されたTODOの下にサブタスクを作成する
+func (s *TodoServer) createSubtask(w http.ResponseWriter, r *http.Request) {
+	s.log(r).Info("POST /todos/{id}/subtasks リクエストを受信しました")
+	id := mux.Vars(r)["id"]
+
+	parentID, err := strconv.ParseUint(id, 10, 0)
+	if err != nil {
+		s.log(r).Errorf("IDの形式が正しくありません: %v", err)
+		http.Error(w, "IDの形式が正しくありません", http.StatusBadRequest)
+		return
+	}
+
+	var req CreateTodoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.log(r).Errorf("リクエストボディの解析に失敗しました: %v", err)
+		http.Error(w, "リクエストボディの解析に失敗しました", http.StatusBadRequest)
+		return
+	}
+
+	parent := uint(parentID)
+	todo, err := s.todos(r).CreateTodo(usecase.CreateTodoInput{
+		ParentID:    &parent,
+		Title:       req.Title,
+		Description: req.Description,
+		Priority:    req.Priority,
+		StartAt:     req.StartAt,
+		DueAt:       req.DueAt,
+		Recurrence:  req.Recurrence,
+	})
+	if err != nil {
+		s.writeError(w, r, err, "サブタスクの作成中にエラーが発生しました")
+		return
+	}
+
+	setETag(w, todo.Version)
+	s.writeJSON(w, r, http.StatusCreated, todo)
+	s.log(r).Infof("新しいサブタスクを作成しました: id=%d, parentID=%s", todo.ID, id)
+}
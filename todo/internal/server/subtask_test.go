@@ -0,0 +1,136 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/usecase"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetSubtasks(t *testing.T) {
+	parentID := uint(1)
+
+	testCases := []struct {
+		name           string
+		id             string
+		todos          []domain.Todo
+		err            error
+		expectedStatus int
+	}{
+		{
+			name:           "正常系",
+			id:             "1",
+			todos:          []domain.Todo{{ID: 2, ParentID: &parentID, Title: "Subtask"}},
+			err:            nil,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "存在しない親タスク",
+			id:             "999",
+			todos:          []domain.Todo{},
+			err:            errors.NewNotFoundError("ID 999 のTodoが見つかりません"),
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// モックの設定
+			mockUseCase := new(MockTodoUseCase)
+			mockUseCase.On("GetSubtasks", tc.id).Return(tc.todos, tc.err)
+			server := NewTodoServer(mockUseCase)
+
+			// リクエスト実行（ルーター経由）
+			req := httptest.NewRequest(http.MethodGet, "/todos/"+tc.id+"/subtasks", nil)
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			// 検証
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			if tc.err == nil {
+				var response []domain.Todo
+				json.Unmarshal(w.Body.Bytes(), &response)
+				assert.Equal(t, tc.todos, response)
+			}
+
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateSubtask(t *testing.T) {
+	parentID := uint(1)
+
+	testCases := []struct {
+		name           string
+		id             string
+		body           string
+		todo           domain.Todo
+		err            error
+		callsUseCase   bool
+		expectedStatus int
+	}{
+		{
+			name:           "正常系",
+			id:             "1",
+			body:           `{"title": "Subtask"}`,
+			todo:           domain.Todo{ID: 2, ParentID: &parentID, Title: "Subtask"},
+			err:            nil,
+			callsUseCase:   true,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "存在しない親タスク",
+			id:             "1",
+			body:           `{"title": "Subtask"}`,
+			todo:           domain.Todo{},
+			err:            errors.NewNotFoundError("親タスク ID 1 のTodoが見つかりません"),
+			callsUseCase:   true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "数値でないID",
+			id:             "abc",
+			body:           `{"title": "Subtask"}`,
+			callsUseCase:   false,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// モックの設定
+			mockUseCase := new(MockTodoUseCase)
+			if tc.callsUseCase {
+				mockUseCase.On("CreateTodo", mock.MatchedBy(func(input usecase.CreateTodoInput) bool {
+					return input.ParentID != nil && *input.ParentID == parentID && input.Title == "Subtask"
+				})).Return(tc.todo, tc.err)
+			}
+			server := NewTodoServer(mockUseCase)
+
+			// リクエスト実行（ルーター経由）
+			req := httptest.NewRequest(http.MethodPost, "/todos/"+tc.id+"/subtasks", bytes.NewBufferString(tc.body))
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			// 検証
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			if tc.err == nil && tc.callsUseCase {
+				var response domain.Todo
+				json.Unmarshal(w.Body.Bytes(), &response)
+				assert.Equal(t, tc.todo, response)
+			}
+
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/usecase"
+)
+
+// TagRequest はタグを作成・更新するためのリクエスト
+type TagRequest struct {
+	Name string `json:"name"`
+}
+
+// tagRoutes はタグ関連のルーティングを設定する
+func (s *TodoServer) tagRoutes() {
+	s.router.HandleFunc("/tags", s.getTags).Methods("GET")
+	s.router.HandleFunc("/tags", s.createTag).Methods("POST")
+	s.router.HandleFunc("/tags/{id}", s.updateTag).Methods("PUT")
+	s.router.HandleFunc("/tags/{id}", s.deleteTag).Methods("DELETE")
+	s.router.HandleFunc("/todos/{id}/tags/{tagID}", s.attachTag).Methods("PUT")
+	s.router.HandleFunc("/todos/{id}/tags/{tagID}", s.detachTag).Methods("DELETE")
+}
+
+// tags はリクエストの context を設定したタグのユースケースを返す
+func (s *TodoServer) tags(r *http.Request) usecase.TagUseCaseInterface {
+	return s.tagUseCase.WithContext(r.Context())
+}
+
+// getTags はすべてのタグを取得する
+func (s *TodoServer) getTags(w http.ResponseWriter, r *http.Request) {
+	s.log(r).Info("GET /tags リクエストを受信しました")
+	tags, err := s.tags(r).GetTags()
+	if err != nil {
+		s.writeError(w, r, err, "タグの取得中にエラーが発生しました")
+		return
+	}
+
+	s.writeJSON(w, r, http.StatusOK, tags)
+	s.log(r).Infof("%d 件のタグを返却しました", len(tags))
+}
+
+// createTag は新しいタグを作成する
+func (s *TodoServer) createTag(w http.ResponseWriter, r *http.Request) {
+	s.log(r).Info("POST /tags リクエストを受信しました")
+	var req TagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.log(r).Errorf("リクエストボディの解析に失敗しました: %v", err)
+		http.Error(w, "リクエストボディの解析に失敗しました", http.StatusBadRequest)
+		return
+	}
+
+	tag, err := s.tags(r).CreateTag(req.Name)
+	if err != nil {
+		s.writeError(w, r, err, "タグの作成中にエラーが発生しました")
+		return
+	}
+
+	s.writeJSON(w, r, http.StatusCreated, tag)
+	s.log(r).Infow("新しいタグを作成しました", "id", tag.ID, "name", tag.Name)
+}
+
+// updateTag は指定されたタグの名前を変更する
+func (s *TodoServer) updateTag(w http.ResponseWriter, r *http.Request) {
+	s.log(r).Info("PUT /tags/{id} リクエストを受信しました")
+	id := mux.Vars(r)["id"]
+
+	var req TagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.log(r).Errorf("リクエストボディの解析に失敗しました: %v", err)
+		http.Error(w, "リクエストボディの解析に失敗しました", http.StatusBadRequest)
+		return
+	}
+
+	tag, err := s.tags(r).RenameTag(id, req.Name)
+	if err != nil {
+		s.writeError(w, r, err, "タグの更新中にエラーが発生しました")
+		return
+	}
+
+	s.writeJSON(w, r, http.StatusOK, tag)
+	s.log(r).Infow("タグを更新しました", "id", id, "name", tag.Name)
+}
+
+// deleteTag は指定されたタグを削除する
+func (s *TodoServer) deleteTag(w http.ResponseWriter, r *http.Request) {
+	s.log(r).Info("DELETE /tags/{id} リクエストを受信しました")
+	id := mux.Vars(r)["id"]
+
+	if err := s.tags(r).DeleteTagByID(id); err != nil {
+		s.writeError(w, r, err, "タグの削除中にエラーが発生しました")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	s.log(r).Infof("タグを削除しました: id=%s", id)
+}
+
+// attachTag は指定されたTODOにタグを付与する
+func (s *TodoServer) attachTag(w http.ResponseWriter, r *http.Request) {
+	s.log(r).Info("PUT /todos/{id}/tags/{tagID} リクエストを受信しました")
+	vars := mux.Vars(r)
+
+	todo, err := s.tags(r).AttachTag(vars["id"], vars["tagID"])
+	if err != nil {
+		s.writeError(w, r, err, "タグの付与中にエラーが発生しました")
+		return
+	}
+
+	setETag(w, todo.Version)
+	s.writeJSON(w, r, http.StatusOK, todo)
+	s.log(r).Infof("Todoにタグを付与しました: id=%s, tagID=%s", vars["id"], vars["tagID"])
+}
+
+// detachTag は指定されたTODOからタグを外す
+func (s *TodoServer) detachTag(w http.ResponseWriter, r *http.Request) {
+	s.log(r).Info("DELETE /todos/{id}/tags/{tagID} リクエストを受信しました")
+	vars := mux.Vars(r)
+
+	todo, err := s.tags(r).DetachTag(vars["id"], vars["tagID"])
+	if err != nil {
+		s.writeError(w, r, err, "タグの解除中にエラーが発生しました")
+		return
+	}
+
+	setETag(w, todo.Version)
+	s.writeJSON(w, r, http.StatusOK, todo)
+	s.log(r).Infof("Todoからタグを外しました: id=%s, tagID=%s", vars["id"], vars["tagID"])
+}
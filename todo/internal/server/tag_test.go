@@ -0,0 +1,196 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/usecase"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTagUseCase は usecase.TagUseCaseInterface のモック実装です
+type MockTagUseCase struct {
+	mock.Mock
+}
+
+// インターフェースを実装していることを確認
+var _ usecase.TagUseCaseInterface = (*MockTagUseCase)(nil)
+
+// WithContext は自身を返します（呼び出しの期待値は設定不要です）
+func (m *MockTagUseCase) WithContext(ctx context.Context) usecase.TagUseCaseInterface {
+	return m
+}
+
+// GetTags は全てのタグを取得するメソッドのモックです
+func (m *MockTagUseCase) GetTags() ([]domain.Tag, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.Tag), args.Error(1)
+}
+
+// CreateTag は新しいタグを作成するメソッドのモックです
+func (m *MockTagUseCase) CreateTag(name string) (domain.Tag, error) {
+	args := m.Called(name)
+	return args.Get(0).(domain.Tag), args.Error(1)
+}
+
+// RenameTag はタグの名前を変更するメソッドのモックです
+func (m *MockTagUseCase) RenameTag(id string, name string) (domain.Tag, error) {
+	args := m.Called(id, name)
+	return args.Get(0).(domain.Tag), args.Error(1)
+}
+
+// DeleteTagByID はIDを指定してタグを削除するメソッドのモックです
+func (m *MockTagUseCase) DeleteTagByID(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// AttachTag はTodoにタグを付与するメソッドのモックです
+func (m *MockTagUseCase) AttachTag(todoID string, tagID string) (domain.Todo, error) {
+	args := m.Called(todoID, tagID)
+	return args.Get(0).(domain.Todo), args.Error(1)
+}
+
+// DetachTag はTodoからタグを外すメソッドのモックです
+func (m *MockTagUseCase) DetachTag(todoID string, tagID string) (domain.Todo, error) {
+	args := m.Called(todoID, tagID)
+	return args.Get(0).(domain.Todo), args.Error(1)
+}
+
+func TestTagRoutesRequireTagUseCase(t *testing.T) {
+	// タグのユースケースを設定しない場合はルートが登録されない
+	server := NewTodoServer(new(MockTodoUseCase))
+
+	req := httptest.NewRequest(http.MethodGet, "/tags", nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCreateTag(t *testing.T) {
+	testCases := []struct {
+		name           string
+		body           string
+		tag            domain.Tag
+		err            error
+		expectedStatus int
+	}{
+		{
+			name:           "正常系",
+			body:           `{"name": "仕事"}`,
+			tag:            domain.Tag{ID: 1, Name: "仕事"},
+			err:            nil,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "重複したタグ名",
+			body:           `{"name": "仕事"}`,
+			tag:            domain.Tag{},
+			err:            errors.NewInvalidInputError("タグ「仕事」は既に存在します"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "内部エラー",
+			body:           `{"name": "仕事"}`,
+			tag:            domain.Tag{},
+			err:            errors.NewInternalError("データベースエラー"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// モックの設定
+			mockTagUseCase := new(MockTagUseCase)
+			mockTagUseCase.On("CreateTag", "仕事").Return(tc.tag, tc.err)
+			server := NewTodoServer(new(MockTodoUseCase), WithTagUseCase(mockTagUseCase))
+
+			// リクエスト実行（ルーター経由）
+			req := httptest.NewRequest(http.MethodPost, "/tags", bytes.NewBufferString(tc.body))
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			// 検証
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			if tc.err == nil {
+				var response domain.Tag
+				json.Unmarshal(w.Body.Bytes(), &response)
+				assert.Equal(t, tc.tag, response)
+			}
+
+			mockTagUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAttachAndDetachTag(t *testing.T) {
+	tag := domain.Tag{ID: 2, Name: "急ぎ"}
+
+	testCases := []struct {
+		name           string
+		method         string
+		mockMethod     string
+		todo           domain.Todo
+		err            error
+		expectedStatus int
+	}{
+		{
+			name:           "タグの付与",
+			method:         http.MethodPut,
+			mockMethod:     "AttachTag",
+			todo:           domain.Todo{ID: 1, Title: "Test Todo", Tags: []domain.Tag{tag}},
+			err:            nil,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "タグの解除",
+			method:         http.MethodDelete,
+			mockMethod:     "DetachTag",
+			todo:           domain.Todo{ID: 1, Title: "Test Todo"},
+			err:            nil,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "存在しないタグ",
+			method:         http.MethodPut,
+			mockMethod:     "AttachTag",
+			todo:           domain.Todo{},
+			err:            errors.NewNotFoundError("ID 2 のタグが見つかりません"),
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// モックの設定
+			mockTagUseCase := new(MockTagUseCase)
+			mockTagUseCase.On(tc.mockMethod, "1", "2").Return(tc.todo, tc.err)
+			server := NewTodoServer(new(MockTodoUseCase), WithTagUseCase(mockTagUseCase))
+
+			// リクエスト実行（ルーター経由）
+			req := httptest.NewRequest(tc.method, "/todos/1/tags/2", nil)
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			// 検証
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			if tc.err == nil {
+				var response domain.Todo
+				json.Unmarshal(w.Body.Bytes(), &response)
+				assert.Equal(t, tc.todo, response)
+			}
+
+			mockTagUseCase.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// DefaultRequestTimeout は1つのリクエストを処理できる時間の既定値
+const DefaultRequestTimeout = 30 * time.Second
+
+// WithRequestTimeout は1つのリクエストを処理できる時間を設定する
+// 時間内に終わらなかった場合はデータベースへの問い合わせなどを中断し、503 Service Unavailable を返す。0以下を指定した場合は制限しない
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(s *TodoServer) {
+		s.requestTimeout = timeout
+	}
+}
+
+// withTimeout はリクエストの context に処理できる時間の制限を設定するミドルウェア
+// クライアントが接続を切った場合も context が終了するため、処理を中断する
+func (s *TodoServer) withTimeout(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.requestTimeout <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout)
+		defer cancel()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/usecase"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRequestTimeout(t *testing.T) {
+	testCases := []struct {
+		name             string
+		timeout          time.Duration
+		err              error
+		expectedStatus   int
+		expectedDeadline bool
+	}{
+		{
+			name:             "正常系: リクエストの context に処理できる時間を設定する",
+			timeout:          DefaultRequestTimeout,
+			expectedStatus:   http.StatusOK,
+			expectedDeadline: true,
+		},
+		{
+			name:             "正常系: 0の場合は制限しない",
+			timeout:          0,
+			expectedStatus:   http.StatusOK,
+			expectedDeadline: false,
+		},
+		{
+			name:             "異常系: 時間内に終わらなかった場合は503",
+			timeout:          DefaultRequestTimeout,
+			err:              errors.NewInternalError("Todoの取得に失敗しました", context.DeadlineExceeded),
+			expectedStatus:   http.StatusServiceUnavailable,
+			expectedDeadline: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// モックの設定
+			mockUseCase := new(MockTodoUseCase)
+			mockUseCase.On("GetTodoPage", mock.Anything).Return(usecase.TodoPage{}, tc.err)
+			server := NewTodoServer(mockUseCase, WithRequestTimeout(tc.timeout))
+
+			// リクエスト実行（ルーター経由）
+			req := httptest.NewRequest(http.MethodGet, "/todos", nil)
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			// 検証
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			deadline, ok := mockUseCase.ctx.Deadline()
+			assert.Equal(t, tc.expectedDeadline, ok)
+			if ok {
+				assert.WithinDuration(t, time.Now().Add(tc.timeout), deadline, time.Second)
+			}
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// getTrash はゴミ箱のTODOを、ゴミ箱に移動した日時の新しい順に返す
+func (s *TodoServer) getTrash(w http.ResponseWriter, r *http.Request) {
+	s.log(r).Info("GET /todos/trash リクエストを受信しました")
+
+	todos, err := s.todos(r).GetTrash()
+	if err != nil {
+		s.writeError(w, r, err, "ゴミ箱のTodoの取得中にエラーが発生しました")
+		return
+	}
+
+	s.writeJSON(w, r, http.StatusOK, todos)
+	s.log(r).Infof("ゴミ箱の %d 件のTodoを返却しました", len(todos))
+}
+
+// restoreTodo はゴミ箱のTODOを元に戻し、元に戻したTODOを返す
+// If-Matchヘッダーを指定した場合は、ゴミ箱のTODOがそのバージョンのときだけ元に戻す
+func (s *TodoServer) restoreTodo(w http.ResponseWriter, r *http.Request) {
+	s.log(r).Info("POST /todos/{id}/restore リクエストを受信しました")
+	id := mux.Vars(r)["id"]
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		s.writeError(w, r, err, "If-Matchヘッダーの解析に失敗しました")
+		return
+	}
+
+	todo, err := s.todos(r).RestoreTodo(id, version)
+	if err != nil {
+		s.writeError(w, r, err, "Todoの復元中にエラーが発生しました")
+		return
+	}
+
+	setETag(w, todo.Version)
+	s.writeJSON(w, r, http.StatusOK, todo)
+	s.log(r).Infof("Todoをゴミ箱から元に戻しました: id=%s", id)
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTrash(t *testing.T) {
+	deletedAt := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	trashed := []domain.Todo{{ID: 1, Title: "Test Todo", Version: 2, DeletedAt: &deletedAt}}
+
+	// モックの設定 - /todos/{id} ではなくゴミ箱の一覧として扱う
+	mockUseCase := new(MockTodoUseCase)
+	mockUseCase.On("GetTrash").Return(trashed, nil)
+	server := NewTodoServer(mockUseCase)
+
+	// リクエスト実行（ルーター経由）
+	req := httptest.NewRequest(http.MethodGet, "/todos/trash", nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	// 検証
+	assert.Equal(t, http.StatusOK, w.Code)
+	var todos []domain.Todo
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &todos))
+	assert.Equal(t, trashed, todos)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestRestoreTodo(t *testing.T) {
+	testCases := []struct {
+		name           string
+		ifMatch        string
+		version        uint
+		err            error
+		expectedStatus int
+		expectedETag   string
+	}{
+		{
+			name:           "正常系",
+			ifMatch:        `"2"`,
+			version:        2,
+			expectedStatus: http.StatusOK,
+			expectedETag:   `"3"`,
+		},
+		{
+			name:           "ゴミ箱にない",
+			err:            errors.NewNotFoundError("ID 1 のTodoはゴミ箱にありません"),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "バージョンが一致しない",
+			ifMatch:        `"1"`,
+			version:        1,
+			err:            errors.NewConflictError("ID 1 のTodoは他の操作で更新されています（現在のバージョンは 2 です）"),
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:           "親タスクがゴミ箱にある",
+			err:            errors.NewInvalidInputError("親タスク（ID 5）がゴミ箱にあるため元に戻せません。先に親タスクを元に戻してください"),
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// モックの設定
+			mockUseCase := new(MockTodoUseCase)
+			mockUseCase.On("RestoreTodo", "1", tc.version).Return(domain.Todo{ID: 1, Title: "Test Todo", Version: 3}, tc.err)
+			server := NewTodoServer(mockUseCase)
+
+			// リクエスト実行（ルーター経由）
+			req := httptest.NewRequest(http.MethodPost, "/todos/1/restore", nil)
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			// 検証
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Equal(t, tc.expectedETag, w.Header().Get("ETag"))
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+)
+
+// AnonymousActor は操作者が指定されていない変更を監査ログに記録する際の操作者
+const AnonymousActor = "anonymous"
+
+// 監査ログを取得する件数
+const (
+	DefaultAuditLimit = 100  // 件数の指定がない場合
+	MaxAuditLimit     = 1000 // 指定できる最大の件数
+)
+
+// WithActor は変更を監査ログに記録する際の操作者を設定したユースケースを返す
+// 元のユースケースは変更しないため、リクエストごとに操作者を設定して使える
+func (uc *TodoUseCase) WithActor(actor string) TodoUseCaseInterface {
+	c := *uc
+	c.actor = actor
+	return &c
+}
+
+// audit は監査ログに記録する内容を作成する
+// 変更したTODOのIDとバージョン、記録した日時は保存時にリポジトリが設定する
+func (uc *TodoUseCase) audit(action domain.AuditAction, changes map[string]domain.FieldChange) *domain.AuditEntry {
+	actor := uc.actor
+	if actor == "" {
+		actor = AnonymousActor
+	}
+	return &domain.AuditEntry{Action: action, Actor: actor, Changes: changes}
+}
+
+// GetHistory は指定されたIDのTODOの変更履歴を記録した順に取得するメソッド
+// ゴミ箱にあるTODOや完全に削除したTODOの履歴も取得できる
+func (uc *TodoUseCase) GetHistory(id string) ([]domain.AuditEntry, error) {
+	n, err := strconv.ParseUint(id, 10, 0)
+	if err != nil {
+		return nil, errors.NewNotFoundError(fmt.Sprintf("ID %s のTodoが見つかりません", id))
+	}
+	todoID := uint(n)
+
+	entries, err := uc.repo.FindAuditLog(domain.AuditQuery{TodoID: &todoID})
+	if err != nil {
+		return nil, errors.NewInternalError(fmt.Sprintf("ID %s のTodoの変更履歴の取得に失敗しました", id), err)
+	}
+	if len(entries) == 0 {
+		// 履歴を記録する前に作成したTODOの場合は空の履歴を返す
+		if err := uc.ensureExists(id); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// ensureExists は指定されたIDのTODOがゴミ箱を含めて存在することを確認する
+func (uc *TodoUseCase) ensureExists(id string) error {
+	todo, err := uc.repo.FindByID(id)
+	if err == nil && todo == nil {
+		todo, err = uc.repo.FindTrashedByID(id)
+	}
+	if err != nil {
+		return errors.NewInternalError(fmt.Sprintf("ID %s のTodoの検索に失敗しました", id), err)
+	}
+	if todo == nil {
+		return errors.NewNotFoundError(fmt.Sprintf("ID %s のTodoが見つかりません", id))
+	}
+	return nil
+}
+
+// GetAuditLog はすべてのTODOの監査ログのうち、条件に一致するものを記録した順に取得するメソッド
+// 件数の指定がない場合は DefaultAuditLimit 件まで取得する
+func (uc *TodoUseCase) GetAuditLog(query domain.AuditQuery) ([]domain.AuditEntry, error) {
+	if query.Since != nil && query.Until != nil && !query.Since.Before(*query.Until) {
+		return nil, errors.NewInvalidInputError("since は until より前の日時を指定してください")
+	}
+	if query.Limit < 0 || query.Limit > MaxAuditLimit {
+		return nil, errors.NewInvalidInputError(fmt.Sprintf("limit は1から%dの範囲で指定してください", MaxAuditLimit))
+	}
+	if query.Limit == 0 {
+		query.Limit = DefaultAuditLimit
+	}
+
+	entries, err := uc.repo.FindAuditLog(query)
+	if err != nil {
+		return nil, errors.NewInternalError("監査ログの取得に失敗しました", err)
+	}
+	return entries, nil
+}
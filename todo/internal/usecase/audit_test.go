@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	appErrors "github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWithActor(t *testing.T) {
+	mockRepo := new(MockTodoRepository)
+	mockRepo.On("Batch").Return(nil)
+	mockRepo.On("FindByID", "1").Return(&domain.Todo{ID: 1, Title: "Todo 1", Version: 1}, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(audit *domain.AuditEntry) bool {
+		return audit.Action == domain.AuditUpdate && audit.Actor == "alice" &&
+			len(audit.Changes) == 1 &&
+			string(audit.Changes["done"].Before) == "false" && string(audit.Changes["done"].After) == "true"
+	})).Return(nil)
+	uc := NewTodoUseCase(mockRepo)
+
+	_, err := uc.WithActor("alice").UpdateTodo("1", true, 1)
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+
+	// 元のユースケースの操作者は変わらない
+	assert.Equal(t, AnonymousActor, uc.(*TodoUseCase).audit(domain.AuditCreate, nil).Actor)
+}
+
+func TestCreateTodoRecordsAudit(t *testing.T) {
+	mockRepo := new(MockTodoRepository)
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(audit *domain.AuditEntry) bool {
+		title, ok := audit.Changes["title"]
+		return audit.Action == domain.AuditCreate && audit.Actor == AnonymousActor &&
+			ok && string(title.Before) == "null" && string(title.After) == `"Todo 1"`
+	})).Return(nil)
+	uc := NewTodoUseCase(mockRepo)
+
+	_, err := uc.CreateTodo(CreateTodoInput{Title: "Todo 1"})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetHistory(t *testing.T) {
+	todoID := uint(1)
+	history := []domain.AuditEntry{
+		{ID: 1, TodoID: 1, Action: domain.AuditCreate, Actor: "alice", Version: 1,
+			Changes: map[string]domain.FieldChange{"title": {Before: json.RawMessage("null"), After: json.RawMessage(`"Todo 1"`)}}},
+	}
+
+	testCases := []struct {
+		name          string
+		id            string
+		mockBehavior  func(*MockTodoRepository)
+		expected      []domain.AuditEntry
+		expectedError error
+	}{
+		{
+			name: "正常系: 変更履歴を取得する",
+			id:   "1",
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindAuditLog", domain.AuditQuery{TodoID: &todoID}).Return(history, nil)
+			},
+			expected: history,
+		},
+		{
+			name: "正常系: 履歴のないゴミ箱のTodo",
+			id:   "1",
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindAuditLog", domain.AuditQuery{TodoID: &todoID}).Return([]domain.AuditEntry{}, nil)
+				repo.On("FindByID", "1").Return(nil, nil)
+				repo.On("FindTrashedByID", "1").Return(&domain.Todo{ID: 1}, nil)
+			},
+			expected: []domain.AuditEntry{},
+		},
+		{
+			name: "異常系: 存在しないTodo",
+			id:   "1",
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindAuditLog", domain.AuditQuery{TodoID: &todoID}).Return([]domain.AuditEntry{}, nil)
+				repo.On("FindByID", "1").Return(nil, nil)
+				repo.On("FindTrashedByID", "1").Return(nil, nil)
+			},
+			expectedError: appErrors.NewNotFoundError("ID 1 のTodoが見つかりません"),
+		},
+		{
+			name:          "異常系: 数値でないID",
+			id:            "abc",
+			mockBehavior:  func(repo *MockTodoRepository) {},
+			expectedError: appErrors.NewNotFoundError("ID abc のTodoが見つかりません"),
+		},
+		{
+			name: "異常系: データベースエラー",
+			id:   "1",
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindAuditLog", mock.Anything).Return(nil, errors.New("database error"))
+			},
+			expectedError: appErrors.NewInternalError("ID 1 のTodoの変更履歴の取得に失敗しました", errors.New("database error")),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockTodoRepository)
+			tc.mockBehavior(mockRepo)
+			uc := NewTodoUseCase(mockRepo)
+
+			entries, err := uc.GetHistory(tc.id)
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, entries)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetAuditLog(t *testing.T) {
+	since := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	until := since.Add(24 * time.Hour)
+
+	testCases := []struct {
+		name          string
+		query         domain.AuditQuery
+		mockBehavior  func(*MockTodoRepository)
+		expectedError error
+	}{
+		{
+			name:  "正常系: 件数の指定がない場合は既定の件数まで取得する",
+			query: domain.AuditQuery{Since: &since, Until: &until},
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindAuditLog", domain.AuditQuery{Since: &since, Until: &until, Limit: DefaultAuditLimit}).Return([]domain.AuditEntry{}, nil)
+			},
+		},
+		{
+			name:  "正常系: 操作者と件数を指定する",
+			query: domain.AuditQuery{Actor: "alice", Limit: 10},
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindAuditLog", domain.AuditQuery{Actor: "alice", Limit: 10}).Return([]domain.AuditEntry{}, nil)
+			},
+		},
+		{
+			name:          "異常系: since が until 以降",
+			query:         domain.AuditQuery{Since: &until, Until: &since},
+			mockBehavior:  func(repo *MockTodoRepository) {},
+			expectedError: appErrors.NewInvalidInputError("since は until より前の日時を指定してください"),
+		},
+		{
+			name:          "異常系: 件数が多すぎる",
+			query:         domain.AuditQuery{Limit: MaxAuditLimit + 1},
+			mockBehavior:  func(repo *MockTodoRepository) {},
+			expectedError: appErrors.NewInvalidInputError("limit は1から1000の範囲で指定してください"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockTodoRepository)
+			tc.mockBehavior(mockRepo)
+			uc := NewTodoUseCase(mockRepo)
+
+			_, err := uc.GetAuditLog(tc.query)
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
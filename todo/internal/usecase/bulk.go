@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	stderrors "errors"
+	"fmt"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/repository"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+)
+
+// MaxBulkOperations は1回でまとめて実行できる操作の最大数
+const MaxBulkOperations = 1000
+
+// BulkAction はまとめて実行する操作の種類
+type BulkAction string
+
+// まとめて実行できる操作を定義
+const (
+	BulkCreate BulkAction = "create" // 作成（CreateTodo と同じ）
+	BulkUpdate BulkAction = "update" // 部分更新（PatchTodo と同じ）
+	BulkDelete BulkAction = "delete" // ゴミ箱に移動（DeleteTodoByID と同じ）
+)
+
+// BulkMode は一部の操作が失敗した場合の扱い
+type BulkMode string
+
+// 一部の操作が失敗した場合の扱いを定義
+const (
+	BulkAtomic     BulkMode = "atomic"      // 1つでも失敗した場合はすべての操作を取り消す
+	BulkBestEffort BulkMode = "best_effort" // 失敗した操作だけを取り消し、残りの操作は保存する
+)
+
+// ParseBulkMode は文字列から一部の操作が失敗した場合の扱いを取得する（空の場合は atomic）
+func ParseBulkMode(s string) (BulkMode, error) {
+	switch m := BulkMode(s); m {
+	case "":
+		return BulkAtomic, nil
+	case BulkAtomic, BulkBestEffort:
+		return m, nil
+	}
+	return BulkAtomic, errors.NewInvalidInputError(fmt.Sprintf("mode には atomic または best_effort を指定してください: %s", s))
+}
+
+// BulkOperation はまとめて実行する1つの操作
+type BulkOperation struct {
+	Action  BulkAction      // 操作の種類
+	ID      string          // 更新・削除するTODOのID
+	Version uint            // 0以外の場合は、TODOのバージョンと一致する場合のみ更新・削除する
+	Create  CreateTodoInput // 作成する内容（create の場合）
+	Patch   TodoPatch       // 更新する内容（update の場合）
+}
+
+// BulkResult はまとめて実行した操作の結果
+type BulkResult struct {
+	Committed bool             // 変更を保存したか（atomic で失敗した操作があった場合は false）
+	Items     []BulkItemResult // 操作ごとの結果（指定した操作と同じ順）
+}
+
+// BulkItemResult は1つの操作の結果
+type BulkItemResult struct {
+	Todo *domain.Todo // 作成・更新したTODO（削除した場合と失敗した場合は nil）
+	Err  error        // 失敗した場合のエラー
+}
+
+// ErrBulkAborted は atomic で他の操作が失敗したため、その操作を保存しなかったことを表す
+var ErrBulkAborted = stderrors.New("他の操作が失敗したため保存していません")
+
+// errBulkFailed は atomic で失敗した操作があり、トランザクションを取り消すことを表す
+var errBulkFailed = stderrors.New("失敗した操作があります")
+
+// BulkTodos は複数のTODOの作成・更新・削除を1つのトランザクションでまとめて実行するメソッド
+// 各操作は CreateTodo・PatchTodo・DeleteTodoByID と同じ規則で指定した順に実行し、操作ごとの結果を返す
+// 操作の失敗は結果に含め、操作の指定が誤っている場合と保存に失敗した場合のみエラーを返す
+func (uc *TodoUseCase) BulkTodos(ops []BulkOperation, mode BulkMode) (BulkResult, error) {
+	if len(ops) == 0 {
+		return BulkResult{}, errors.NewInvalidInputError("操作を1つ以上指定してください")
+	}
+	if len(ops) > MaxBulkOperations {
+		return BulkResult{}, errors.NewInvalidInputError(fmt.Sprintf("操作は%d件以内にしてください", MaxBulkOperations))
+	}
+	for i, op := range ops {
+		switch op.Action {
+		case BulkCreate, BulkUpdate, BulkDelete:
+		default:
+			return BulkResult{}, errors.NewInvalidInputError(fmt.Sprintf("%d 番目の操作の種類には create, update, delete のいずれかを指定してください: %s", i+1, op.Action))
+		}
+	}
+	if mode != BulkAtomic && mode != BulkBestEffort {
+		return BulkResult{}, errors.NewInvalidInputError(fmt.Sprintf("mode には atomic または best_effort を指定してください: %s", mode))
+	}
+
+	items := make([]BulkItemResult, len(ops))
+	err := uc.repo.Batch(func(repo repository.TodoRepositoryInterface) error {
+		for i, op := range ops {
+			if mode == BulkAtomic {
+				todo, err := uc.withRepo(repo).runBulkOperation(op)
+				items[i] = BulkItemResult{Todo: todo, Err: err}
+				if err != nil {
+					return errBulkFailed
+				}
+				continue
+			}
+
+			// 操作ごとに入れ子の Batch（セーブポイント）で実行し、失敗した操作の途中までの変更だけを取り消す
+			err := repo.Batch(func(sp repository.TodoRepositoryInterface) error {
+				todo, err := uc.withRepo(sp).runBulkOperation(op)
+				items[i] = BulkItemResult{Todo: todo, Err: err}
+				return err
+			})
+			if err != nil && items[i].Err == nil {
+				return err // セーブポイントの作成・解放に失敗した場合
+			}
+		}
+		return nil
+	})
+
+	switch {
+	case err == nil:
+		return BulkResult{Committed: true, Items: items}, nil
+	case stderrors.Is(err, errBulkFailed):
+		for i := range items {
+			if items[i].Err == nil {
+				items[i] = BulkItemResult{Err: ErrBulkAborted}
+			}
+		}
+		return BulkResult{Items: items}, nil
+	}
+	return BulkResult{}, errors.NewInternalError("まとめて実行した操作の保存に失敗しました", err)
+}
+
+// runBulkOperation は1つの操作を実行し、作成・更新したTODOを返す
+func (uc *TodoUseCase) runBulkOperation(op BulkOperation) (*domain.Todo, error) {
+	var todo domain.Todo
+	var err error
+	switch op.Action {
+	case BulkCreate:
+		todo, err = uc.CreateTodo(op.Create)
+	case BulkUpdate:
+		todo, err = uc.PatchTodo(op.ID, op.Patch, op.Version)
+	default:
+		return nil, uc.DeleteTodoByID(op.ID, op.Version)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &todo, nil
+}
+
+// withRepo は指定したリポジトリ（Batch に渡されたもの）で操作するユースケースを返す
+func (uc *TodoUseCase) withRepo(repo repository.TodoRepositoryInterface) *TodoUseCase {
+	c := *uc
+	c.repo = repo
+	return &c
+}
@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	appErrors "github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBulkTodos(t *testing.T) {
+	completeAll := []BulkOperation{
+		{Action: BulkUpdate, ID: "1", Patch: TodoPatch{Done: boolPtr(true)}},
+		{Action: BulkUpdate, ID: "999", Patch: TodoPatch{Done: boolPtr(true)}},
+		{Action: BulkDelete, ID: "2", Version: 3},
+	}
+
+	testCases := []struct {
+		name              string
+		ops               []BulkOperation
+		mode              BulkMode
+		mockBehavior      func(*MockTodoRepository)
+		expectedCommitted bool
+		expectedTodos     []*domain.Todo
+		expectedErrors    []error
+		expectedError     error
+	}{
+		{
+			name: "正常系: 作成・更新・削除をまとめて実行",
+			ops: []BulkOperation{
+				{Action: BulkCreate, Create: CreateTodoInput{Title: "買い物"}},
+				{Action: BulkUpdate, ID: "1", Patch: TodoPatch{Done: boolPtr(true)}},
+				{Action: BulkDelete, ID: "2", Version: 3},
+			},
+			mode: BulkAtomic,
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("Batch").Return(nil).Times(2) // 全体と更新の操作
+				repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+				repo.On("FindByID", "1").Return(&domain.Todo{ID: 1, Title: "掃除"}, nil)
+				repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+				repo.On("FindByID", "2").Return(&domain.Todo{ID: 2, Version: 3}, nil)
+				repo.On("Delete", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedCommitted: true,
+			expectedTodos:     []*domain.Todo{{Title: "買い物", Priority: domain.PriorityNone}, {ID: 1, Title: "掃除", Done: true}, nil},
+			expectedErrors:    []error{nil, nil, nil},
+		},
+		{
+			name: "正常系: atomic で失敗した場合はすべて保存しない",
+			ops:  completeAll,
+			mode: BulkAtomic,
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("Batch").Return(nil).Times(3) // 全体と更新の操作（失敗した操作で中断する）
+				repo.On("FindByID", "1").Return(&domain.Todo{ID: 1, Title: "掃除"}, nil)
+				repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+				repo.On("FindByID", "999").Return(nil, nil)
+			},
+			expectedCommitted: false,
+			expectedTodos:     []*domain.Todo{nil, nil, nil},
+			expectedErrors:    []error{ErrBulkAborted, appErrors.NewNotFoundError("ID 999 のTodoが見つかりません"), ErrBulkAborted},
+		},
+		{
+			name: "正常系: best_effort では失敗した操作だけを取り消す",
+			ops:  completeAll,
+			mode: BulkBestEffort,
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("Batch").Return(nil).Times(6) // 全体と操作ごとのセーブポイント、更新の操作
+				repo.On("FindByID", "1").Return(&domain.Todo{ID: 1, Title: "掃除"}, nil)
+				repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+				repo.On("FindByID", "999").Return(nil, nil)
+				repo.On("FindByID", "2").Return(&domain.Todo{ID: 2, Version: 4}, nil)
+			},
+			expectedCommitted: true,
+			expectedTodos:     []*domain.Todo{{ID: 1, Title: "掃除", Done: true}, nil, nil},
+			expectedErrors: []error{
+				nil,
+				appErrors.NewNotFoundError("ID 999 のTodoが見つかりません"),
+				appErrors.NewConflictError("ID 2 のTodoは他の操作で更新されています（現在のバージョンは 4 です）"),
+			},
+		},
+		{
+			name:          "異常系: 操作がない",
+			mode:          BulkAtomic,
+			mockBehavior:  func(repo *MockTodoRepository) {},
+			expectedError: appErrors.NewInvalidInputError("操作を1つ以上指定してください"),
+		},
+		{
+			name:          "異常系: 不明な操作",
+			ops:           []BulkOperation{{Action: BulkCreate}, {Action: "archive", ID: "1"}},
+			mode:          BulkAtomic,
+			mockBehavior:  func(repo *MockTodoRepository) {},
+			expectedError: appErrors.NewInvalidInputError("2 番目の操作の種類には create, update, delete のいずれかを指定してください: archive"),
+		},
+		{
+			name:          "異常系: 不明なモード",
+			ops:           completeAll,
+			mode:          "partial",
+			mockBehavior:  func(repo *MockTodoRepository) {},
+			expectedError: appErrors.NewInvalidInputError("mode には atomic または best_effort を指定してください: partial"),
+		},
+		{
+			name: "異常系: 保存に失敗",
+			ops:  completeAll,
+			mode: BulkBestEffort,
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("Batch").Return(errors.New("database error"))
+			},
+			expectedError: appErrors.NewInternalError("まとめて実行した操作の保存に失敗しました", errors.New("database error")),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockTodoRepository)
+			tc.mockBehavior(mockRepo)
+
+			uc := NewTodoUseCase(mockRepo)
+
+			result, err := uc.BulkTodos(tc.ops, tc.mode)
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedCommitted, result.Committed)
+				assert.Len(t, result.Items, len(tc.ops))
+				for i, item := range result.Items {
+					assert.Equal(t, tc.expectedTodos[i], item.Todo, "%d 番目の操作", i+1)
+					if tc.expectedErrors[i] == nil {
+						assert.NoError(t, item.Err, "%d 番目の操作", i+1)
+					} else {
+						assert.EqualError(t, item.Err, tc.expectedErrors[i].Error(), "%d 番目の操作", i+1)
+					}
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
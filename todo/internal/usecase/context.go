@@ -0,0 +1,17 @@
+package usecase
+
+import "context"
+
+// WithContext は ctx が終了した時点でリポジトリの処理を中断するユースケースを返す
+// 元のユースケースは変更しないため、リクエストごとに ctx を設定して使える（WithActor と同じ）
+func (uc *TodoUseCase) WithContext(ctx context.Context) TodoUseCaseInterface {
+	c := *uc
+	c.repo = uc.repo.WithContext(ctx)
+	c.log = uc.log.WithContext(ctx)
+	return &c
+}
+
+// WithContext は ctx が終了した時点でリポジトリの処理を中断するユースケースを返す
+func (uc *TagUseCase) WithContext(ctx context.Context) TagUseCaseInterface {
+	return &TagUseCase{todoRepo: uc.todoRepo.WithContext(ctx), tagRepo: uc.tagRepo.WithContext(ctx)}
+}
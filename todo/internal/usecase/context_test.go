@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+
+	mockRepo := new(MockTodoRepository)
+	mockRepo.On("FindAll", mock.Anything).Return([]domain.Todo(nil), ctx.Err())
+	uc := NewTodoUseCase(mockRepo)
+
+	// ctx はリポジトリに渡り、中断したことは errors.Is で判定できる
+	_, err := uc.WithContext(ctx).GetTodos(domain.TodoQuery{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, ctx, mockRepo.ctx)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWithContextLog(t *testing.T) {
+	mockRepo := new(MockTodoRepository)
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	uc := NewTodoUseCase(mockRepo).(*TodoUseCase)
+	var out bytes.Buffer
+	root := logger.New(&out, &out, logger.WithFormat(logger.FormatJSON))
+	uc.log = root.Named(logger.ComponentUseCase)
+	ctx := logger.ContextWithRequestID(context.Background(), "req-1")
+
+	// コンポーネント usecase のレベルを変更するまでは DEBUG のログを出力しない
+	_, err := uc.WithContext(ctx).CreateTodo(CreateTodoInput{Title: "買い物"})
+	require.NoError(t, err)
+	assert.Empty(t, out.String())
+
+	require.NoError(t, root.SetComponentLevel(logger.ComponentUseCase, logger.DEBUG))
+	_, err = uc.WithContext(ctx).CreateTodo(CreateTodoInput{Title: "買い物"})
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), `"msg":"Todoを作成しました"`)
+	assert.Contains(t, out.String(), `"component":"usecase"`)
+	assert.Contains(t, out.String(), `"request_id":"req-1"`)
+}
@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"strings"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/utils"
+)
+
+// TodoPatch はTODOの部分更新の内容（nilの項目は変更しない）
+type TodoPatch struct {
+	Title       *string // タイトル
+	Description *string // 説明（空文字で削除）
+	Done        *bool   // 完了状態
+}
+
+// PatchTodo は指定されたIDのTODOのうち、パッチで指定された項目だけを更新するメソッド
+func (uc *TodoUseCase) PatchTodo(id string, patch TodoPatch, version uint) (domain.Todo, error) {
+	var title string
+	if patch.Title != nil {
+		title = strings.TrimSpace(*patch.Title)
+		if valid, msg := utils.ValidateTitle(title); !valid {
+			return domain.Todo{}, errors.NewInvalidInputError(msg)
+		}
+	}
+	if patch.Description != nil {
+		if valid, msg := utils.ValidateDescription(*patch.Description); !valid {
+			return domain.Todo{}, errors.NewInvalidInputError(msg)
+		}
+	}
+
+	return uc.updateInBatch(id, func(uc *TodoUseCase) (domain.Todo, error) {
+		todo, err := uc.findTodoForWrite(id, version)
+		if err != nil {
+			return domain.Todo{}, err
+		}
+		before := *todo
+
+		if patch.Title != nil {
+			todo.Title = title
+		}
+		if patch.Description != nil {
+			todo.Description = *patch.Description
+		}
+
+		var next *domain.Todo
+		if patch.Done != nil {
+			if next, err = uc.changeDone(todo, *patch.Done); err != nil {
+				return domain.Todo{}, err
+			}
+		}
+
+		if err := uc.repo.Update(todo, uc.audit(domain.AuditUpdate, domain.TodoChanges(&before, *todo))); err != nil {
+			return domain.Todo{}, persistError(id, "更新", err)
+		}
+
+		if err := uc.createNextOccurrence(id, next); err != nil {
+			return domain.Todo{}, err
+		}
+		return *todo, nil
+	})
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	appErrors "github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func strPtr(s string) *string { return &s }
+func boolPtr(b bool) *bool    { return &b }
+
+func TestPatchTodo(t *testing.T) {
+	testCases := []struct {
+		name          string
+		id            string
+		patch         TodoPatch
+		mockBehavior  func(*MockTodoRepository)
+		expectedTodo  domain.Todo
+		expectedError error
+	}{
+		{
+			name:  "正常系: タイトルだけを変更",
+			id:    "1",
+			patch: TodoPatch{Title: strPtr("  誤字を直したタイトル ")},
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindByID", "1").Return(&domain.Todo{ID: 1, Title: "誤字のあるタイトル", Description: "メモ"}, nil)
+				repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedTodo:  domain.Todo{ID: 1, Title: "誤字を直したタイトル", Description: "メモ"},
+			expectedError: nil,
+		},
+		{
+			name:  "正常系: 説明を削除して完了にする",
+			id:    "1",
+			patch: TodoPatch{Description: strPtr(""), Done: boolPtr(true)},
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindByID", "1").Return(&domain.Todo{ID: 1, Title: "タイトル", Description: "メモ"}, nil)
+				repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedTodo:  domain.Todo{ID: 1, Title: "タイトル", Done: true},
+			expectedError: nil,
+		},
+		{
+			name:          "異常系: 空のタイトル",
+			id:            "1",
+			patch:         TodoPatch{Title: strPtr("   ")},
+			mockBehavior:  func(repo *MockTodoRepository) {},
+			expectedTodo:  domain.Todo{},
+			expectedError: appErrors.NewInvalidInputError("タイトルは空にできません"),
+		},
+		{
+			name:          "異常系: 不正なSQL構文を含むタイトル",
+			id:            "1",
+			patch:         TodoPatch{Title: strPtr("DROP TABLE todos")},
+			mockBehavior:  func(repo *MockTodoRepository) {},
+			expectedTodo:  domain.Todo{},
+			expectedError: appErrors.NewInvalidInputError("タイトルに不正なSQL構文が含まれています"),
+		},
+		{
+			name:          "異常系: スクリプトを含む説明",
+			id:            "1",
+			patch:         TodoPatch{Description: strPtr("<script>alert(1)</script>")},
+			mockBehavior:  func(repo *MockTodoRepository) {},
+			expectedTodo:  domain.Todo{},
+			expectedError: appErrors.NewInvalidInputError("説明に不正なスクリプトが含まれています"),
+		},
+		{
+			name:  "異常系: 存在しないID",
+			id:    "999",
+			patch: TodoPatch{Done: boolPtr(true)},
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindByID", "999").Return(nil, nil)
+			},
+			expectedTodo:  domain.Todo{},
+			expectedError: appErrors.NewNotFoundError("ID 999 のTodoが見つかりません"),
+		},
+		{
+			name:  "異常系: 更新に失敗",
+			id:    "1",
+			patch: TodoPatch{Title: strPtr("新しいタイトル")},
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindByID", "1").Return(&domain.Todo{ID: 1, Title: "タイトル"}, nil)
+				repo.On("Update", mock.Anything, mock.Anything).Return(errors.New("database error"))
+			},
+			expectedTodo:  domain.Todo{},
+			expectedError: appErrors.NewInternalError("ID 1 のTodoの更新に失敗しました", errors.New("database error")),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockTodoRepository)
+			mockRepo.On("Batch").Return(nil).Maybe() // 入力が不正な場合は呼び出さない
+			tc.mockBehavior(mockRepo)
+
+			uc := NewTodoUseCase(mockRepo)
+
+			todo, err := uc.PatchTodo(tc.id, tc.patch, 0)
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedTodo, todo)
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
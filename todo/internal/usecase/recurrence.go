@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/rrule"
+)
+
+// WithLocation は繰り返しルールを展開する際のタイムゾーンを設定する
+// 既定は time.Local で、曜日や時刻はこのタイムゾーンの壁時計時刻で判定する
+func WithLocation(loc *time.Location) Option {
+	return func(uc *TodoUseCase) {
+		if loc != nil {
+			uc.location = loc
+		}
+	}
+}
+
+// normalizeRecurrence は繰り返しルールを検証し、正規化した文字列を返す
+// 繰り返しの起点となる開始日時または期限日時のどちらかが必要
+func normalizeRecurrence(recurrence string, startAt, dueAt *time.Time) (string, error) {
+	if strings.TrimSpace(recurrence) == "" {
+		return "", nil
+	}
+
+	rule, err := rrule.Parse(recurrence)
+	if err != nil {
+		return "", errors.NewInvalidInputError(fmt.Sprintf("繰り返しルールが不正です: %v", err), err)
+	}
+	if startAt == nil && dueAt == nil {
+		return "", errors.NewInvalidInputError("繰り返しを設定するには開始日時または期限日時が必要です")
+	}
+	return rule.String(), nil
+}
+
+// nextOccurrence は繰り返しTODOの次回分を作成用に組み立てる
+// 期限日時（未設定なら開始日時）を起点に次の発生日時を求め、開始日時と期限日時の間隔は保つ
+// 繰り返しが終了している場合は nil を返す
+func (uc *TodoUseCase) nextOccurrence(todo *domain.Todo) (*domain.Todo, error) {
+	rule, err := rrule.Parse(todo.Recurrence)
+	if err != nil {
+		return nil, errors.NewInternalError(fmt.Sprintf("ID %d のTodoの繰り返しルールが不正です", todo.ID), err)
+	}
+
+	anchor := todo.DueAt
+	if anchor == nil {
+		anchor = todo.StartAt
+	}
+	if anchor == nil {
+		return nil, nil
+	}
+
+	next, rest, ok := rule.Advance(anchor.In(uc.location))
+	if !ok {
+		return nil, nil
+	}
+
+	occurrence := &domain.Todo{
+		ParentID:    todo.ParentID,
+		Title:       todo.Title,
+		Description: todo.Description,
+		Priority:    todo.Priority,
+		Recurrence:  rest.String(),
+		Tags:        todo.Tags,
+	}
+	switch {
+	case todo.DueAt != nil && todo.StartAt != nil:
+		startAt := next.Add(-todo.DueAt.Sub(*todo.StartAt))
+		occurrence.StartAt = &startAt
+		occurrence.DueAt = &next
+	case todo.DueAt != nil:
+		occurrence.DueAt = &next
+	default:
+		occurrence.StartAt = &next
+	}
+	occurrence.NormalizeSchedule()
+	return occurrence, nil
+}
+
+// createNextOccurrence は繰り返しTODOの次回分を保存する（next が nil の場合は何もしない）
+func (uc *TodoUseCase) createNextOccurrence(id string, next *domain.Todo) error {
+	if next == nil {
+		return nil
+	}
+	if err := uc.repo.Create(next, uc.audit(domain.AuditCreate, domain.TodoChanges(nil, *next))); err != nil {
+		return errors.NewInternalError(fmt.Sprintf("ID %s の次回のTodoの作成に失敗しました", id), err)
+	}
+	uc.log.Debugw("繰り返しTodoの次回分を作成しました", "id", id, "next_id", next.ID, "start_at", next.StartAt, "due_at", next.DueAt)
+	return nil
+}
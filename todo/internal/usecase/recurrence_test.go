@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	appErrors "github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var jst = time.FixedZone("JST", 9*60*60)
+
+func TestCreateTodoWithRecurrence(t *testing.T) {
+	dueAt := time.Date(2025, 4, 4, 8, 0, 0, 0, jst)
+
+	testCases := []struct {
+		name               string
+		input              CreateTodoInput
+		mockBehavior       func(*MockTodoRepository)
+		expectedRecurrence string
+		expectedError      error
+	}{
+		{
+			name:  "正常系: ルールを正規化して保存",
+			input: CreateTodoInput{Title: "ゴミ出し", DueAt: &dueAt, Recurrence: "rrule:freq=weekly;byday=mo,th"},
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("Create", mock.MatchedBy(func(todo *domain.Todo) bool {
+					return todo.Recurrence == "FREQ=WEEKLY;BYDAY=MO,TH"
+				}), mock.Anything).Return(nil)
+			},
+			expectedRecurrence: "FREQ=WEEKLY;BYDAY=MO,TH",
+		},
+		{
+			name:          "異常系: 不正なルール",
+			input:         CreateTodoInput{Title: "ゴミ出し", DueAt: &dueAt, Recurrence: "FREQ=HOURLY"},
+			mockBehavior:  func(repo *MockTodoRepository) {},
+			expectedError: appErrors.NewInvalidInputError("繰り返しルールが不正です: 未対応の FREQ です: HOURLY"),
+		},
+		{
+			name:          "異常系: 起点となる日時がない",
+			input:         CreateTodoInput{Title: "ゴミ出し", Recurrence: "FREQ=DAILY"},
+			mockBehavior:  func(repo *MockTodoRepository) {},
+			expectedError: appErrors.NewInvalidInputError("繰り返しを設定するには開始日時または期限日時が必要です"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockTodoRepository)
+			tc.mockBehavior(mockRepo)
+
+			uc := NewTodoUseCase(mockRepo)
+
+			todo, err := uc.CreateTodo(tc.input)
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedRecurrence, todo.Recurrence)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateTodoCreatesNextOccurrence(t *testing.T) {
+	// 金曜日 8:00 (JST) はUTCでは木曜日なので、JSTで展開しないと次の平日を誤る
+	dueAt := time.Date(2025, 4, 3, 23, 0, 0, 0, time.UTC)
+	startAt := dueAt.Add(-2 * time.Hour)
+	nextDue := time.Date(2025, 4, 7, 8, 0, 0, 0, jst)
+	tags := []domain.Tag{{ID: 1, Name: "家事"}}
+
+	testCases := []struct {
+		name         string
+		todo         *domain.Todo
+		done         bool
+		mockBehavior func(*MockTodoRepository)
+	}{
+		{
+			name: "次の平日のTodoを作成し、繰り返し設定を引き継ぐ",
+			todo: &domain.Todo{ID: 1, Title: "日報", Description: "作業内容と明日の予定", Priority: domain.PriorityHigh, StartAt: &startAt, DueAt: &dueAt, Recurrence: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", Tags: tags},
+			done: true,
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("Update", mock.MatchedBy(func(todo *domain.Todo) bool {
+					return todo.ID == 1 && todo.Done && todo.Recurrence == ""
+				}), mock.Anything).Return(nil)
+				repo.On("Create", mock.MatchedBy(func(todo *domain.Todo) bool {
+					return todo.Title == "日報" && todo.Description == "作業内容と明日の予定" && !todo.Done &&
+						todo.Priority == domain.PriorityHigh &&
+						todo.DueAt.Equal(nextDue) &&
+						todo.StartAt.Equal(nextDue.Add(-2*time.Hour)) &&
+						todo.Recurrence == "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR" &&
+						len(todo.Tags) == 1
+				}), mock.Anything).Return(nil)
+			},
+		},
+		{
+			name: "COUNTを使い切ったら次回を作成しない",
+			todo: &domain.Todo{ID: 1, Title: "日報", DueAt: &dueAt, Recurrence: "FREQ=DAILY;COUNT=1"},
+			done: true,
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("Update", mock.MatchedBy(func(todo *domain.Todo) bool {
+					return todo.ID == 1 && todo.Done
+				}), mock.Anything).Return(nil)
+			},
+		},
+		{
+			name: "未完了に戻す場合は次回を作成しない",
+			todo: &domain.Todo{ID: 1, Title: "日報", Done: true, DueAt: &dueAt, Recurrence: "FREQ=DAILY"},
+			done: false,
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("Update", mock.MatchedBy(func(todo *domain.Todo) bool {
+					return todo.ID == 1 && !todo.Done && todo.Recurrence == "FREQ=DAILY"
+				}), mock.Anything).Return(nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockTodoRepository)
+			mockRepo.On("Batch").Return(nil)
+			mockRepo.On("FindByID", "1").Return(tc.todo, nil)
+			tc.mockBehavior(mockRepo)
+
+			uc := NewTodoUseCase(mockRepo, WithLocation(jst))
+
+			todo, err := uc.UpdateTodo("1", tc.done, 0)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.done, todo.Done)
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+)
+
+// DefaultSearchLimit は件数を指定しなかった場合の検索結果の件数
+const DefaultSearchLimit = 20
+
+// maxSearchTextLength は検索キーワードの最大文字数
+const maxSearchTextLength = 100
+
+// SearchTodos はタイトルと説明をキーワードで全文検索し、関連度の高い順に返すメソッド
+// 空白で区切った複数のキーワードはすべてを含むTODOに一致する
+func (uc *TodoUseCase) SearchTodos(text string, limit int) ([]domain.SearchResult, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, errors.NewInvalidInputError("検索キーワードを指定してください")
+	}
+	if utf8.RuneCountInString(text) > maxSearchTextLength {
+		return nil, errors.NewInvalidInputError("検索キーワードは100文字以内にしてください")
+	}
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+
+	results, err := uc.repo.Search(text, limit)
+	if err != nil {
+		return nil, errors.NewInternalError("Todoの検索に失敗しました", err)
+	}
+	if results == nil {
+		results = []domain.SearchResult{}
+	}
+	return results, nil
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	appErrors "github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTodos(t *testing.T) {
+	results := []domain.SearchResult{
+		{Todo: domain.Todo{ID: 1, Title: "会議資料の作成"}, Rank: -2.5, Title: "<mark>会議資料</mark>の作成"},
+	}
+
+	testCases := []struct {
+		name            string
+		text            string
+		limit           int
+		mockBehavior    func(*MockTodoRepository)
+		expectedResults []domain.SearchResult
+		expectedError   error
+	}{
+		{
+			name:  "正常系: 前後の空白を除いて検索",
+			text:  "  会議資料 ",
+			limit: 10,
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("Search", "会議資料", 10).Return(results, nil)
+			},
+			expectedResults: results,
+		},
+		{
+			name:  "正常系: 件数の指定がない場合は既定の件数",
+			text:  "会議資料",
+			limit: 0,
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("Search", "会議資料", DefaultSearchLimit).Return(nil, nil)
+			},
+			expectedResults: []domain.SearchResult{},
+		},
+		{
+			name:          "異常系: キーワードが空",
+			text:          "   ",
+			mockBehavior:  func(repo *MockTodoRepository) {},
+			expectedError: appErrors.NewInvalidInputError("検索キーワードを指定してください"),
+		},
+		{
+			name:          "異常系: キーワードが長すぎる",
+			text:          strings.Repeat("あ", 101),
+			mockBehavior:  func(repo *MockTodoRepository) {},
+			expectedError: appErrors.NewInvalidInputError("検索キーワードは100文字以内にしてください"),
+		},
+		{
+			name:  "異常系: 検索に失敗",
+			text:  "会議資料",
+			limit: 10,
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("Search", "会議資料", 10).Return(nil, errors.New("database error"))
+			},
+			expectedError: appErrors.NewInternalError("Todoの検索に失敗しました", errors.New("database error")),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockTodoRepository)
+			tc.mockBehavior(mockRepo)
+
+			uc := NewTodoUseCase(mockRepo)
+
+			got, err := uc.SearchTodos(tc.text, tc.limit)
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedResults, got)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
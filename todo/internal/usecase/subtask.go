@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	stderrors "errors"
+	"fmt"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/repository"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+)
+
+// CompletionRule は親タスクを完了にする際のサブタスクの扱い
+type CompletionRule string
+
+// 完了ルールを定義
+const (
+	CompletionRuleNone    CompletionRule = "none"    // 親とサブタスクの完了状態を連動させない
+	CompletionRuleCascade CompletionRule = "cascade" // 親を完了にするとサブタスク（孫以降を含む）も完了にする
+	CompletionRuleStrict  CompletionRule = "strict"  // 未完了のサブタスクがある間は親を完了にできない
+)
+
+// ErrIncompleteSubtasks は strict の完了ルールで、未完了のサブタスクがあるため親を完了にできないことを表す
+// 入力ではなく他のTODOの状態による競合のため、競合エラーの原因として返す
+var ErrIncompleteSubtasks = stderrors.New("未完了のサブタスクがあります")
+
+// ParseCompletionRule は文字列から完了ルールを取得する
+func ParseCompletionRule(s string) (CompletionRule, error) {
+	switch r := CompletionRule(s); r {
+	case CompletionRuleNone, CompletionRuleCascade, CompletionRuleStrict:
+		return r, nil
+	}
+	return CompletionRuleNone, fmt.Errorf("不明な完了ルールです: %s", s)
+}
+
+// WithCompletionRule は親タスクを完了にする際のルールを設定する
+func WithCompletionRule(rule CompletionRule) Option {
+	return func(uc *TodoUseCase) {
+		uc.completionRule = rule
+	}
+}
+
+// GetSubtasks は指定されたIDのTODOのサブタスクを取得するメソッド
+func (uc *TodoUseCase) GetSubtasks(id string) ([]domain.Todo, error) {
+	parent, err := uc.repo.FindByID(id)
+	if err != nil {
+		return nil, errors.NewInternalError(fmt.Sprintf("ID %s のTodoの検索に失敗しました", id), err)
+	}
+	if parent == nil {
+		return nil, errors.NewNotFoundError(fmt.Sprintf("ID %s のTodoが見つかりません", id))
+	}
+
+	subtasks, err := uc.repo.FindAll(domain.TodoQuery{ParentID: &parent.ID})
+	if err != nil {
+		return nil, errors.NewInternalError(fmt.Sprintf("ID %s のサブタスクの取得に失敗しました", id), err)
+	}
+	return subtasks, nil
+}
+
+// applyCompletionRule は親タスクを完了にする前に完了ルールを適用する
+func (uc *TodoUseCase) applyCompletionRule(parent *domain.Todo) error {
+	switch uc.completionRule {
+	case CompletionRuleStrict:
+		subtasks, err := uc.repo.FindAll(domain.TodoQuery{ParentID: &parent.ID})
+		if err != nil {
+			return errors.NewInternalError(fmt.Sprintf("ID %d のサブタスクの取得に失敗しました", parent.ID), err)
+		}
+		for _, subtask := range subtasks {
+			if !subtask.Done {
+				uc.log.Debugw("未完了のサブタスクがあるため完了にしません", "id", parent.ID, "subtask_id", subtask.ID)
+				return errors.NewConflictError("未完了のサブタスクがあるため完了にできません", ErrIncompleteSubtasks)
+			}
+		}
+	case CompletionRuleCascade:
+		return uc.completeSubtasks(parent.ID)
+	}
+	return nil
+}
+
+// completeSubtasks は指定したTODOのサブタスクを孫以降も含めて完了にする
+func (uc *TodoUseCase) completeSubtasks(parentID uint) error {
+	subtasks, err := uc.repo.FindAll(domain.TodoQuery{ParentID: &parentID})
+	if err != nil {
+		return errors.NewInternalError(fmt.Sprintf("ID %d のサブタスクの取得に失敗しました", parentID), err)
+	}
+	for i := range subtasks {
+		subtask := &subtasks[i]
+		if err := uc.completeSubtasks(subtask.ID); err != nil {
+			return err
+		}
+		if subtask.Done {
+			continue
+		}
+		before := *subtask
+		subtask.Done = true
+		uc.log.Debugw("親タスクに合わせてサブタスクを完了にします", "id", parentID, "subtask_id", subtask.ID)
+		if err := uc.repo.Update(subtask, uc.audit(domain.AuditUpdate, domain.TodoChanges(&before, *subtask))); err != nil {
+			if stderrors.Is(err, repository.ErrVersionConflict) {
+				return errors.NewConflictError(fmt.Sprintf("ID %d のサブタスクは他の操作で更新されています", subtask.ID), err)
+			}
+			return errors.NewInternalError(fmt.Sprintf("ID %d のサブタスクの更新に失敗しました", subtask.ID), err)
+		}
+	}
+	return nil
+}
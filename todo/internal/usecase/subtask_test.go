@@ -0,0 +1,271 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/repository"
+	appErrors "github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// parentQuery は指定した親タスクのサブタスクを取得する条件を返す
+func parentQuery(id uint) domain.TodoQuery {
+	return domain.TodoQuery{ParentID: &id}
+}
+
+func TestParseCompletionRule(t *testing.T) {
+	for _, s := range []string{"none", "cascade", "strict"} {
+		rule, err := ParseCompletionRule(s)
+		assert.NoError(t, err)
+		assert.Equal(t, CompletionRule(s), rule)
+	}
+
+	_, err := ParseCompletionRule("auto")
+	assert.Error(t, err)
+}
+
+func TestCreateSubtask(t *testing.T) {
+	parentID := uint(1)
+	missingID := uint(999)
+
+	testCases := []struct {
+		name          string
+		input         CreateTodoInput
+		mockBehavior  func(*MockTodoRepository)
+		expectedError error
+	}{
+		{
+			name:  "正常系: 親タスクの下に作成",
+			input: CreateTodoInput{Title: "資料を集める", ParentID: &parentID},
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindByID", "1").Return(&domain.Todo{ID: 1, Title: "レポート"}, nil)
+				repo.On("Create", mock.MatchedBy(func(todo *domain.Todo) bool {
+					return todo.ParentID != nil && *todo.ParentID == 1
+				}), mock.Anything).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:  "異常系: 親タスクが存在しない",
+			input: CreateTodoInput{Title: "資料を集める", ParentID: &missingID},
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindByID", "999").Return(nil, nil)
+			},
+			expectedError: appErrors.NewNotFoundError("親タスク ID 999 のTodoが見つかりません"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockTodoRepository)
+			tc.mockBehavior(mockRepo)
+
+			uc := NewTodoUseCase(mockRepo)
+
+			todo, err := uc.CreateTodo(tc.input)
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.input.ParentID, todo.ParentID)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetSubtasks(t *testing.T) {
+	parentID := uint(1)
+	subtasks := []domain.Todo{{ID: 2, ParentID: &parentID, Title: "資料を集める"}}
+
+	testCases := []struct {
+		name          string
+		id            string
+		mockBehavior  func(*MockTodoRepository)
+		expectedTodos []domain.Todo
+		expectedError error
+	}{
+		{
+			name: "正常系: サブタスクを取得",
+			id:   "1",
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindByID", "1").Return(&domain.Todo{ID: 1, Title: "レポート"}, nil)
+				repo.On("FindAll", parentQuery(1)).Return(subtasks, nil)
+			},
+			expectedTodos: subtasks,
+			expectedError: nil,
+		},
+		{
+			name: "異常系: 親タスクが存在しない",
+			id:   "999",
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindByID", "999").Return(nil, nil)
+			},
+			expectedError: appErrors.NewNotFoundError("ID 999 のTodoが見つかりません"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockTodoRepository)
+			tc.mockBehavior(mockRepo)
+
+			uc := NewTodoUseCase(mockRepo)
+
+			todos, err := uc.GetSubtasks(tc.id)
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedTodos, todos)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateTodoWithCompletionRule(t *testing.T) {
+	parentID := uint(1)
+	childID := uint(2)
+
+	testCases := []struct {
+		name          string
+		rule          CompletionRule
+		mockBehavior  func(*MockTodoRepository)
+		expectedError error
+	}{
+		{
+			name: "none: サブタスクを確認せずに完了",
+			rule: CompletionRuleNone,
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindByID", "1").Return(&domain.Todo{ID: 1, Title: "親"}, nil)
+				repo.On("Update", mock.MatchedBy(func(todo *domain.Todo) bool {
+					return todo.ID == 1 && todo.Done
+				}), mock.Anything).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "strict: 未完了のサブタスクがあると完了にできない",
+			rule: CompletionRuleStrict,
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindByID", "1").Return(&domain.Todo{ID: 1, Title: "親"}, nil)
+				repo.On("FindAll", parentQuery(1)).Return([]domain.Todo{
+					{ID: 2, ParentID: &parentID, Title: "子1", Done: true},
+					{ID: 3, ParentID: &parentID, Title: "子2", Done: false},
+				}, nil)
+			},
+			expectedError: appErrors.NewConflictError("未完了のサブタスクがあるため完了にできません", ErrIncompleteSubtasks),
+		},
+		{
+			name: "strict: すべてのサブタスクが完了していれば完了にできる",
+			rule: CompletionRuleStrict,
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindByID", "1").Return(&domain.Todo{ID: 1, Title: "親"}, nil)
+				repo.On("FindAll", parentQuery(1)).Return([]domain.Todo{
+					{ID: 2, ParentID: &parentID, Title: "子1", Done: true},
+				}, nil)
+				repo.On("Update", mock.MatchedBy(func(todo *domain.Todo) bool {
+					return todo.ID == 1 && todo.Done
+				}), mock.Anything).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "cascade: 子と孫のタスクも完了にする",
+			rule: CompletionRuleCascade,
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindByID", "1").Return(&domain.Todo{ID: 1, Title: "親"}, nil)
+				repo.On("FindAll", parentQuery(1)).Return([]domain.Todo{
+					{ID: 2, ParentID: &parentID, Title: "子", Done: false},
+				}, nil)
+				repo.On("FindAll", parentQuery(2)).Return([]domain.Todo{
+					{ID: 3, ParentID: &childID, Title: "孫", Done: false},
+				}, nil)
+				repo.On("FindAll", parentQuery(3)).Return([]domain.Todo{}, nil)
+				repo.On("Update", mock.MatchedBy(func(todo *domain.Todo) bool {
+					return todo.ID == 3 && todo.Done
+				}), mock.Anything).Return(nil).Once()
+				repo.On("Update", mock.MatchedBy(func(todo *domain.Todo) bool {
+					return todo.ID == 2 && todo.Done
+				}), mock.Anything).Return(nil).Once()
+				repo.On("Update", mock.MatchedBy(func(todo *domain.Todo) bool {
+					return todo.ID == 1 && todo.Done
+				}), mock.Anything).Return(nil).Once()
+			},
+			expectedError: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockTodoRepository)
+			mockRepo.On("Batch").Return(nil)
+			tc.mockBehavior(mockRepo)
+
+			uc := NewTodoUseCase(mockRepo, WithCompletionRule(tc.rule))
+
+			todo, err := uc.UpdateTodo("1", true, 0)
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, todo.Done)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// conflictRepository は指定したIDのTodoの更新を競合させるリポジトリ（Batch の中でも同様）
+type conflictRepository struct {
+	repository.TodoRepositoryInterface
+	id uint
+}
+
+func (r *conflictRepository) Update(todo *domain.Todo, audit *domain.AuditEntry) error {
+	if todo.ID == r.id {
+		return repository.ErrVersionConflict
+	}
+	return r.TodoRepositoryInterface.Update(todo, audit)
+}
+
+func (r *conflictRepository) Batch(fn func(repo repository.TodoRepositoryInterface) error) error {
+	return r.TodoRepositoryInterface.Batch(func(tx repository.TodoRepositoryInterface) error {
+		return fn(&conflictRepository{TodoRepositoryInterface: tx, id: r.id})
+	})
+}
+
+func TestUpdateTodoCascadeRollback(t *testing.T) {
+	repo := repository.NewMemoryStore().TodoRepository()
+	parent := &domain.Todo{Title: "親"}
+	require.NoError(t, repo.Create(parent, nil))
+	child := &domain.Todo{ParentID: &parent.ID, Title: "子"}
+	require.NoError(t, repo.Create(child, nil))
+	grandchild := &domain.Todo{ParentID: &child.ID, Title: "孫"}
+	require.NoError(t, repo.Create(grandchild, nil))
+
+	// 親の更新が競合した場合は、先に完了にしたサブタスクも元に戻す
+	uc := NewTodoUseCase(&conflictRepository{TodoRepositoryInterface: repo, id: parent.ID}, WithCompletionRule(CompletionRuleCascade))
+	_, err := uc.UpdateTodo("1", true, 0)
+	assert.True(t, appErrors.IsConflict(err))
+	_, err = uc.PatchTodo("1", TodoPatch{Done: boolPtr(true)}, 0)
+	assert.True(t, appErrors.IsConflict(err))
+
+	todos, err := repo.FindAll(domain.TodoQuery{})
+	require.NoError(t, err)
+	require.Len(t, todos, 3)
+	for _, todo := range todos {
+		assert.False(t, todo.Done, todo.Title)
+		assert.Equal(t, uint(1), todo.Version, todo.Title)
+	}
+}
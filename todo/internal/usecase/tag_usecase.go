@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/repository"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+)
+
+// タグ名の最大文字数
+const maxTagNameLength = 30
+
+// TagUseCaseInterface はTagのビジネスロジックを定義するインターフェース
+type TagUseCaseInterface interface {
+	GetTags() ([]domain.Tag, error)
+	CreateTag(name string) (domain.Tag, error)
+	RenameTag(id string, name string) (domain.Tag, error)
+	DeleteTagByID(id string) error
+	AttachTag(todoID string, tagID string) (domain.Todo, error)
+	DetachTag(todoID string, tagID string) (domain.Todo, error)
+	WithContext(ctx context.Context) TagUseCaseInterface
+}
+
+// TagUseCase は TagUseCaseInterface を実装する構造体
+type TagUseCase struct {
+	todoRepo repository.TodoRepositoryInterface
+	tagRepo  repository.TagRepositoryInterface
+}
+
+// NewTagUseCase は新しいTagUseCaseインスタンスを作成する関数
+func NewTagUseCase(todoRepo repository.TodoRepositoryInterface, tagRepo repository.TagRepositoryInterface) TagUseCaseInterface {
+	return &TagUseCase{todoRepo: todoRepo, tagRepo: tagRepo}
+}
+
+// GetTags はすべてのタグを取得するメソッド
+func (uc *TagUseCase) GetTags() ([]domain.Tag, error) {
+	tags, err := uc.tagRepo.FindAll()
+	if err != nil {
+		return nil, errors.NewInternalError("タグの取得に失敗しました", err)
+	}
+	return tags, nil
+}
+
+// CreateTag は新しいタグを作成するメソッド
+func (uc *TagUseCase) CreateTag(name string) (domain.Tag, error) {
+	name, err := uc.validateName(name)
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	tag := domain.Tag{Name: name}
+	if err := uc.tagRepo.Create(&tag); err != nil {
+		return domain.Tag{}, errors.NewInternalError("タグの作成に失敗しました", err)
+	}
+	return tag, nil
+}
+
+// RenameTag は指定されたIDのタグの名前を変更するメソッド
+func (uc *TagUseCase) RenameTag(id string, name string) (domain.Tag, error) {
+	tag, err := uc.findTag(id)
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	if strings.TrimSpace(name) == tag.Name {
+		return *tag, nil
+	}
+
+	name, err = uc.validateName(name)
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	tag.Name = name
+	if err := uc.tagRepo.Update(tag); err != nil {
+		return domain.Tag{}, errors.NewInternalError(fmt.Sprintf("ID %s のタグの更新に失敗しました", id), err)
+	}
+	return *tag, nil
+}
+
+// DeleteTagByID は指定されたIDのタグを削除するメソッド
+// タグが付与されていたタスク自体は削除しない
+func (uc *TagUseCase) DeleteTagByID(id string) error {
+	tag, err := uc.findTag(id)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.tagRepo.Delete(tag); err != nil {
+		return errors.NewInternalError(fmt.Sprintf("ID %s のタグの削除に失敗しました", id), err)
+	}
+	return nil
+}
+
+// AttachTag は指定されたTODOにタグを付与し、付与後のTODOを返すメソッド
+func (uc *TagUseCase) AttachTag(todoID string, tagID string) (domain.Todo, error) {
+	todo, tag, err := uc.findTodoAndTag(todoID, tagID)
+	if err != nil {
+		return domain.Todo{}, err
+	}
+
+	if err := uc.tagRepo.Attach(todo, tag); err != nil {
+		return domain.Todo{}, errors.NewInternalError(fmt.Sprintf("ID %s のTodoへのタグの付与に失敗しました", todoID), err)
+	}
+	return uc.reloadTodo(todoID)
+}
+
+// DetachTag は指定されたTODOからタグを外し、解除後のTODOを返すメソッド
+func (uc *TagUseCase) DetachTag(todoID string, tagID string) (domain.Todo, error) {
+	todo, tag, err := uc.findTodoAndTag(todoID, tagID)
+	if err != nil {
+		return domain.Todo{}, err
+	}
+
+	if err := uc.tagRepo.Detach(todo, tag); err != nil {
+		return domain.Todo{}, errors.NewInternalError(fmt.Sprintf("ID %s のTodoからのタグの解除に失敗しました", todoID), err)
+	}
+	return uc.reloadTodo(todoID)
+}
+
+// validateName はタグ名を検証し、前後の空白を取り除いた名前を返す
+func (uc *TagUseCase) validateName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.NewInvalidInputError("タグ名は必須です")
+	}
+	if utf8.RuneCountInString(name) > maxTagNameLength {
+		return "", errors.NewInvalidInputError(fmt.Sprintf("タグ名は%d文字以内にしてください", maxTagNameLength))
+	}
+
+	existing, err := uc.tagRepo.FindByName(name)
+	if err != nil {
+		return "", errors.NewInternalError("タグの検索に失敗しました", err)
+	}
+	if existing != nil {
+		return "", errors.NewInvalidInputError(fmt.Sprintf("タグ「%s」は既に存在します", name))
+	}
+	return name, nil
+}
+
+// findTag は指定されたIDのタグを取得する
+func (uc *TagUseCase) findTag(id string) (*domain.Tag, error) {
+	tag, err := uc.tagRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.NewInternalError(fmt.Sprintf("ID %s のタグの検索に失敗しました", id), err)
+	}
+	if tag == nil {
+		return nil, errors.NewNotFoundError(fmt.Sprintf("ID %s のタグが見つかりません", id))
+	}
+	return tag, nil
+}
+
+// findTodoAndTag は指定されたIDのTODOとタグを取得する
+func (uc *TagUseCase) findTodoAndTag(todoID string, tagID string) (*domain.Todo, *domain.Tag, error) {
+	todo, err := uc.todoRepo.FindByID(todoID)
+	if err != nil {
+		return nil, nil, errors.NewInternalError(fmt.Sprintf("ID %s のTodoの検索に失敗しました", todoID), err)
+	}
+	if todo == nil {
+		return nil, nil, errors.NewNotFoundError(fmt.Sprintf("ID %s のTodoが見つかりません", todoID))
+	}
+
+	tag, err := uc.findTag(tagID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return todo, tag, nil
+}
+
+// reloadTodo はタグの付け外し後のTODOを取得し直す
+func (uc *TagUseCase) reloadTodo(todoID string) (domain.Todo, error) {
+	todo, err := uc.todoRepo.FindByID(todoID)
+	if err != nil {
+		return domain.Todo{}, errors.NewInternalError(fmt.Sprintf("ID %s のTodoの検索に失敗しました", todoID), err)
+	}
+	if todo == nil {
+		return domain.Todo{}, errors.NewNotFoundError(fmt.Sprintf("ID %s のTodoが見つかりません", todoID))
+	}
+	return *todo, nil
+}
@@ -0,0 +1,264 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/repository"
+	appErrors "github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTagRepository struct {
+	mock.Mock
+}
+
+var _ repository.TagRepositoryInterface = (*MockTagRepository)(nil) // インターフェース適合を保証
+
+func (m *MockTagRepository) FindAll() ([]domain.Tag, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) FindByID(id string) (*domain.Tag, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) FindByName(name string) (*domain.Tag, error) {
+	args := m.Called(name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) Create(tag *domain.Tag) error {
+	args := m.Called(tag)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) Update(tag *domain.Tag) error {
+	args := m.Called(tag)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) Delete(tag *domain.Tag) error {
+	args := m.Called(tag)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) Attach(todo *domain.Todo, tag *domain.Tag) error {
+	args := m.Called(todo, tag)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) Detach(todo *domain.Todo, tag *domain.Tag) error {
+	args := m.Called(todo, tag)
+	return args.Error(0)
+}
+
+// WithContext は自身を返します（呼び出しの期待値は設定不要です）
+func (m *MockTagRepository) WithContext(ctx context.Context) repository.TagRepositoryInterface {
+	return m
+}
+
+func TestCreateTag(t *testing.T) {
+	testCases := []struct {
+		name          string
+		inputName     string
+		mockBehavior  func(*MockTagRepository)
+		expectedTag   domain.Tag
+		expectedError error
+	}{
+		{
+			name:      "正常系: 前後の空白を除いて作成",
+			inputName: "  仕事 ",
+			mockBehavior: func(repo *MockTagRepository) {
+				repo.On("FindByName", "仕事").Return(nil, nil)
+				repo.On("Create", mock.MatchedBy(func(tag *domain.Tag) bool {
+					return tag.Name == "仕事"
+				})).Return(nil)
+			},
+			expectedTag:   domain.Tag{Name: "仕事"},
+			expectedError: nil,
+		},
+		{
+			name:      "異常系: 空のタグ名",
+			inputName: "   ",
+			mockBehavior: func(repo *MockTagRepository) {
+				// FindByName は呼ばれない想定
+			},
+			expectedError: appErrors.NewInvalidInputError("タグ名は必須です"),
+		},
+		{
+			name:      "異常系: タグ名が長すぎる",
+			inputName: strings.Repeat("あ", 31),
+			mockBehavior: func(repo *MockTagRepository) {
+				// FindByName は呼ばれない想定
+			},
+			expectedError: appErrors.NewInvalidInputError("タグ名は30文字以内にしてください"),
+		},
+		{
+			name:      "異常系: 既に存在するタグ名",
+			inputName: "仕事",
+			mockBehavior: func(repo *MockTagRepository) {
+				repo.On("FindByName", "仕事").Return(&domain.Tag{ID: 1, Name: "仕事"}, nil)
+			},
+			expectedError: appErrors.NewInvalidInputError("タグ「仕事」は既に存在します"),
+		},
+		{
+			name:      "異常系: リポジトリエラー",
+			inputName: "仕事",
+			mockBehavior: func(repo *MockTagRepository) {
+				repo.On("FindByName", "仕事").Return(nil, nil)
+				repo.On("Create", mock.Anything).Return(errors.New("データベースエラー"))
+			},
+			expectedError: appErrors.NewInternalError("タグの作成に失敗しました", errors.New("データベースエラー")),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockTagRepo := new(MockTagRepository)
+			tc.mockBehavior(mockTagRepo)
+
+			uc := NewTagUseCase(new(MockTodoRepository), mockTagRepo)
+
+			tag, err := uc.CreateTag(tc.inputName)
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedTag, tag)
+			}
+
+			mockTagRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAttachTag(t *testing.T) {
+	tag := &domain.Tag{ID: 2, Name: "急ぎ"}
+
+	testCases := []struct {
+		name          string
+		todoID        string
+		tagID         string
+		mockBehavior  func(*MockTodoRepository, *MockTagRepository)
+		expectedTags  []domain.Tag
+		expectedError error
+	}{
+		{
+			name:   "正常系: タグを付与して再取得したTodoを返す",
+			todoID: "1",
+			tagID:  "2",
+			mockBehavior: func(todoRepo *MockTodoRepository, tagRepo *MockTagRepository) {
+				todoRepo.On("FindByID", "1").Return(&domain.Todo{ID: 1, Title: "Todo 1"}, nil).Once()
+				tagRepo.On("FindByID", "2").Return(tag, nil)
+				tagRepo.On("Attach", mock.MatchedBy(func(todo *domain.Todo) bool { return todo.ID == 1 }), tag).Return(nil)
+				todoRepo.On("FindByID", "1").Return(&domain.Todo{ID: 1, Title: "Todo 1", Tags: []domain.Tag{*tag}}, nil).Once()
+			},
+			expectedTags:  []domain.Tag{*tag},
+			expectedError: nil,
+		},
+		{
+			name:   "異常系: 存在しないTodo",
+			todoID: "999",
+			tagID:  "2",
+			mockBehavior: func(todoRepo *MockTodoRepository, tagRepo *MockTagRepository) {
+				todoRepo.On("FindByID", "999").Return(nil, nil)
+			},
+			expectedError: appErrors.NewNotFoundError("ID 999 のTodoが見つかりません"),
+		},
+		{
+			name:   "異常系: 存在しないタグ",
+			todoID: "1",
+			tagID:  "999",
+			mockBehavior: func(todoRepo *MockTodoRepository, tagRepo *MockTagRepository) {
+				todoRepo.On("FindByID", "1").Return(&domain.Todo{ID: 1, Title: "Todo 1"}, nil)
+				tagRepo.On("FindByID", "999").Return(nil, nil)
+			},
+			expectedError: appErrors.NewNotFoundError("ID 999 のタグが見つかりません"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockTodoRepo := new(MockTodoRepository)
+			mockTagRepo := new(MockTagRepository)
+			tc.mockBehavior(mockTodoRepo, mockTagRepo)
+
+			uc := NewTagUseCase(mockTodoRepo, mockTagRepo)
+
+			todo, err := uc.AttachTag(tc.todoID, tc.tagID)
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedTags, todo.Tags)
+			}
+
+			mockTodoRepo.AssertExpectations(t)
+			mockTagRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteTagByID(t *testing.T) {
+	testCases := []struct {
+		name          string
+		id            string
+		mockBehavior  func(*MockTagRepository)
+		expectedError error
+	}{
+		{
+			name: "正常系: 存在するIDでタグを削除",
+			id:   "1",
+			mockBehavior: func(repo *MockTagRepository) {
+				repo.On("FindByID", "1").Return(&domain.Tag{ID: 1, Name: "仕事"}, nil)
+				repo.On("Delete", mock.MatchedBy(func(tag *domain.Tag) bool { return tag.ID == 1 })).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "異常系: 存在しないIDでタグを削除",
+			id:   "999",
+			mockBehavior: func(repo *MockTagRepository) {
+				repo.On("FindByID", "999").Return(nil, nil)
+			},
+			expectedError: appErrors.NewNotFoundError("ID 999 のタグが見つかりません"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockTagRepo := new(MockTagRepository)
+			tc.mockBehavior(mockTagRepo)
+
+			uc := NewTagUseCase(new(MockTodoRepository), mockTagRepo)
+
+			err := uc.DeleteTagByID(tc.id)
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockTagRepo.AssertExpectations(t)
+		})
+	}
+}
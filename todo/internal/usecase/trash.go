@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+)
+
+// DefaultTrashRetention はゴミ箱のTODOを完全に削除するまでの既定の保持期間
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+// WithTrashRetention はゴミ箱のTODOを完全に削除するまでの保持期間を設定する
+// 0以下を指定した場合は完全に削除しない
+func WithTrashRetention(retention time.Duration) Option {
+	return func(uc *TodoUseCase) {
+		uc.trashRetention = retention
+	}
+}
+
+// GetTrash はゴミ箱のTODOを、ゴミ箱に移動した日時の新しい順に取得するメソッド
+func (uc *TodoUseCase) GetTrash() ([]domain.Todo, error) {
+	todos, err := uc.repo.FindTrashed()
+	if err != nil {
+		return nil, errors.NewInternalError("ゴミ箱のTodoの取得に失敗しました", err)
+	}
+	return todos, nil
+}
+
+// RestoreTodo はゴミ箱にある指定されたIDのTODOを元に戻すメソッド
+// 一緒にゴミ箱に移動したサブタスクも元に戻す。親タスクがゴミ箱にある場合は先に親タスクを元に戻す必要がある
+func (uc *TodoUseCase) RestoreTodo(id string, version uint) (domain.Todo, error) {
+	todo, err := uc.repo.FindTrashedByID(id)
+	if err != nil {
+		return domain.Todo{}, errors.NewInternalError(fmt.Sprintf("ID %s のTodoの検索に失敗しました", id), err)
+	}
+	if todo == nil {
+		return domain.Todo{}, errors.NewNotFoundError(fmt.Sprintf("ID %s のTodoはゴミ箱にありません", id))
+	}
+	if version != 0 && todo.Version != version {
+		return domain.Todo{}, errors.NewConflictError(fmt.Sprintf("ID %s のTodoは他の操作で更新されています（現在のバージョンは %d です）", id, todo.Version))
+	}
+
+	if todo.ParentID != nil {
+		parent, err := uc.repo.FindByID(fmt.Sprint(*todo.ParentID))
+		if err != nil {
+			return domain.Todo{}, errors.NewInternalError(fmt.Sprintf("ID %d のTodoの検索に失敗しました", *todo.ParentID), err)
+		}
+		if parent == nil {
+			return domain.Todo{}, errors.NewInvalidInputError(fmt.Sprintf("親タスク（ID %d）がゴミ箱にあるため元に戻せません。先に親タスクを元に戻してください", *todo.ParentID))
+		}
+	}
+
+	if err := uc.repo.Restore(todo, uc.audit(domain.AuditRestore, nil)); err != nil {
+		return domain.Todo{}, persistError(id, "復元", err)
+	}
+	return *todo, nil
+}
+
+// PurgeTrash は保持期間を過ぎたゴミ箱のTODOを完全に削除し、削除した件数を返すメソッド
+// 保持期間が設定されていない場合は何もしない
+func (uc *TodoUseCase) PurgeTrash() (int64, error) {
+	if uc.trashRetention <= 0 {
+		return 0, nil
+	}
+	// 利用者の操作ではないため、アプリケーションによる変更として記録する
+	audit := &domain.AuditEntry{Action: domain.AuditPurge, Actor: domain.SystemActor}
+	purged, err := uc.repo.Purge(uc.now().Add(-uc.trashRetention), audit)
+	if err != nil {
+		return 0, errors.NewInternalError("ゴミ箱のTodoの完全な削除に失敗しました", err)
+	}
+	uc.log.Debugw("保持期間を過ぎたゴミ箱のTodoを完全に削除しました", "purged", purged, "retention", uc.trashRetention)
+	return purged, nil
+}
@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/repository"
+	appErrors "github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetTrash(t *testing.T) {
+	deletedAt := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	trashed := []domain.Todo{{ID: 1, Title: "Todo 1", DeletedAt: &deletedAt}}
+
+	mockRepo := new(MockTodoRepository)
+	mockRepo.On("FindTrashed").Return(trashed, nil)
+	uc := NewTodoUseCase(mockRepo)
+
+	todos, err := uc.GetTrash()
+	assert.NoError(t, err)
+	assert.Equal(t, trashed, todos)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRestoreTodo(t *testing.T) {
+	deletedAt := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	parentID := uint(1)
+
+	testCases := []struct {
+		name          string
+		id            string
+		version       uint
+		mockBehavior  func(*MockTodoRepository)
+		expectedError error
+	}{
+		{
+			name:    "正常系: ゴミ箱のTodoを元に戻す",
+			id:      "2",
+			version: 3,
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindTrashedByID", "2").Return(&domain.Todo{ID: 2, ParentID: &parentID, Version: 3, DeletedAt: &deletedAt}, nil)
+				repo.On("FindByID", "1").Return(&domain.Todo{ID: 1}, nil)
+				repo.On("Restore", mock.MatchedBy(func(todo *domain.Todo) bool { return todo.ID == 2 }), mock.Anything).Return(nil)
+			},
+		},
+		{
+			name: "異常系: ゴミ箱にない",
+			id:   "2",
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindTrashedByID", "2").Return(nil, nil)
+			},
+			expectedError: appErrors.NewNotFoundError("ID 2 のTodoはゴミ箱にありません"),
+		},
+		{
+			name:    "異常系: 指定したバージョンが古い",
+			id:      "2",
+			version: 2,
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindTrashedByID", "2").Return(&domain.Todo{ID: 2, Version: 3, DeletedAt: &deletedAt}, nil)
+			},
+			expectedError: appErrors.NewConflictError("ID 2 のTodoは他の操作で更新されています（現在のバージョンは 3 です）"),
+		},
+		{
+			name: "異常系: 親タスクがゴミ箱にある",
+			id:   "2",
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindTrashedByID", "2").Return(&domain.Todo{ID: 2, ParentID: &parentID, Version: 3, DeletedAt: &deletedAt}, nil)
+				repo.On("FindByID", "1").Return(nil, nil)
+			},
+			expectedError: appErrors.NewInvalidInputError("親タスク（ID 1）がゴミ箱にあるため元に戻せません。先に親タスクを元に戻してください"),
+		},
+		{
+			name: "異常系: 取得してから元に戻すまでに更新された",
+			id:   "2",
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindTrashedByID", "2").Return(&domain.Todo{ID: 2, Version: 3, DeletedAt: &deletedAt}, nil)
+				repo.On("Restore", mock.Anything, mock.Anything).Return(repository.ErrVersionConflict)
+			},
+			expectedError: appErrors.NewConflictError("ID 2 のTodoは他の操作で更新されています", repository.ErrVersionConflict),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockTodoRepository)
+			tc.mockBehavior(mockRepo)
+			uc := NewTodoUseCase(mockRepo)
+
+			todo, err := uc.RestoreTodo(tc.id, tc.version)
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, uint(2), todo.ID)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	now := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		retention     time.Duration
+		mockBehavior  func(*MockTodoRepository)
+		expected      int64
+		expectedError error
+	}{
+		{
+			name:      "正常系: 保持期間より前にゴミ箱に移動したTodoを削除",
+			retention: 24 * time.Hour,
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("Purge", now.Add(-24*time.Hour), mock.Anything).Return(int64(2), nil)
+			},
+			expected: 2,
+		},
+		{
+			name:         "正常系: 保持期間が0の場合は削除しない",
+			retention:    0,
+			mockBehavior: func(repo *MockTodoRepository) {},
+		},
+		{
+			name:      "異常系: 削除に失敗",
+			retention: time.Hour,
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("Purge", now.Add(-time.Hour), mock.Anything).Return(int64(0), errors.New("database error"))
+			},
+			expectedError: appErrors.NewInternalError("ゴミ箱のTodoの完全な削除に失敗しました", errors.New("database error")),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockTodoRepository)
+			tc.mockBehavior(mockRepo)
+			uc := &TodoUseCase{repo: mockRepo, log: logger.GetLogger(), now: func() time.Time { return now }, trashRetention: tc.retention}
+
+			purged, err := uc.PurgeTrash()
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, purged)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -1,98 +1,347 @@
 package usecase
 
 import (
+	"context"
+	stderrors "errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
 	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/repository"
 	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/logger"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/utils"
 )
 
 // TodoUseCaseInterface はTodoのビジネスロジックを定義するインターフェース
 type TodoUseCaseInterface interface {
-    GetTodos() ([]domain.Todo, error)
-    CreateTodo(title string) (domain.Todo, error)
-    UpdateTodo(id string, done bool) (domain.Todo, error)
-    DeleteTodoByID(id string) error
+    GetTodos(query domain.TodoQuery) ([]domain.Todo, error)
+    GetTodoPage(query domain.TodoQuery) (TodoPage, error)
+    CreateTodo(input CreateTodoInput) (domain.Todo, error)
+    UpdateTodo(id string, done bool, version uint) (domain.Todo, error)
+    PatchTodo(id string, patch TodoPatch, version uint) (domain.Todo, error)
+    UpdateSchedule(id string, startAt, dueAt *time.Time, version uint) (domain.Todo, error)
+    UpdatePriority(id string, priority string, version uint) (domain.Todo, error)
+    DeleteTodoByID(id string, version uint) error
+    BulkTodos(ops []BulkOperation, mode BulkMode) (BulkResult, error)
+    GetHistory(id string) ([]domain.AuditEntry, error)
+    GetAuditLog(query domain.AuditQuery) ([]domain.AuditEntry, error)
+    WithActor(actor string) TodoUseCaseInterface
+    WithContext(ctx context.Context) TodoUseCaseInterface
+    GetSubtasks(id string) ([]domain.Todo, error)
+    SearchTodos(text string, limit int) ([]domain.SearchResult, error)
+    GetTrash() ([]domain.Todo, error)
+    RestoreTodo(id string, version uint) (domain.Todo, error)
+    PurgeTrash() (int64, error)
+}
+
+// CreateTodoInput はTODO作成時の入力値
+type CreateTodoInput struct {
+    Title       string     // タスクのタイトル（必須）
+    Description string     // タスクの詳細な説明（任意）
+    ParentID    *uint      // 親タスクのID（サブタスクとして作成する場合のみ）
+    Priority    string     // 優先度（none, low, medium, high, urgent。省略時はnone）
+    StartAt     *time.Time // 開始日時（任意）
+    DueAt       *time.Time // 期限日時（任意）
+    Recurrence  string     // 繰り返しルール（RFC 5545 の RRULE。任意）
+}
+
+// TodoPage はページ単位で取得したTODOの一覧
+type TodoPage struct {
+    Todos      []domain.Todo // このページのTODO
+    Total      int64         // 絞り込み条件に一致するTODOの総数（ページングに関係しない）
+    NextCursor string        // 次のページを取得するためのカーソル（最後のページの場合は空）
 }
 
 // TodoUseCase は TodoUseCaseInterface を実装する構造体
 type TodoUseCase struct {
-    repo repository.TodoRepositoryInterface // インターフェースを使う
+    repo           repository.TodoRepositoryInterface // インターフェースを使う
+    now            func() time.Time                   // 現在時刻の取得（テスト時に差し替え可能）
+    completionRule CompletionRule                     // 親タスクを完了にする際のサブタスクの扱い
+    location       *time.Location                     // 繰り返しルールを展開する際のタイムゾーン
+    trashRetention time.Duration                      // ゴミ箱のTODOを完全に削除するまでの保持期間（0以下の場合は削除しない）
+    actor          string                             // 監査ログに記録する操作者（WithActor で設定する）
+    log            *logger.Logger                     // コンポーネント usecase のLogger（WithContext でリクエストIDを付ける）
 }
 
+// Option はTodoUseCaseの任意設定
+type Option func(*TodoUseCase)
+
 // NewTodoUseCase は新しいTodoUseCaseインスタンスを作成する関数
-func NewTodoUseCase(repo repository.TodoRepositoryInterface) TodoUseCaseInterface {
-    return &TodoUseCase{repo: repo}
+func NewTodoUseCase(repo repository.TodoRepositoryInterface, opts ...Option) TodoUseCaseInterface {
+    uc := &TodoUseCase{repo: repo, now: time.Now, completionRule: CompletionRuleNone, location: time.Local, trashRetention: DefaultTrashRetention, log: logger.GetLogger().Named(logger.ComponentUseCase)}
+    for _, opt := range opts {
+        opt(uc)
+    }
+    return uc
 }
 
-// GetTodos はすべてのTODOを取得するメソッド
-func (uc *TodoUseCase) GetTodos() ([]domain.Todo, error) {
-	todos, err := uc.repo.FindAll()
+// GetTodos は絞り込み条件に一致するTODOを取得するメソッド
+func (uc *TodoUseCase) GetTodos(query domain.TodoQuery) ([]domain.Todo, error) {
+	if query.Overdue && query.Now.IsZero() {
+		query.Now = uc.overdueBase(query)
+	}
+	todos, err := uc.repo.FindAll(query)
 	if err != nil {
-		return nil, errors.NewInternalError("Todoの取得に失敗しました", err)
+		return nil, findError("Todoの取得に失敗しました", err)
 	}
     return todos, nil
 }
 
+// GetTodoPage は絞り込み条件に一致するTODOをページ単位で取得するメソッド
+// query.Limit が0の場合は、カーソルの後ろのすべてのTODOを1ページとして返す
+func (uc *TodoUseCase) GetTodoPage(query domain.TodoQuery) (TodoPage, error) {
+	if query.After != nil && (query.After.Sort != query.Sort || query.After.Desc != query.Desc) {
+		return TodoPage{}, errors.NewInvalidInputError("カーソルは同じ並び順で取得したものを指定してください")
+	}
+	if query.Overdue && query.Now.IsZero() {
+		query.Now = uc.overdueBase(query)
+	}
+
+	limit := query.Limit
+	if limit > 0 {
+		query.Limit = limit + 1 // 次のページの有無を判定するため1件多く取得する
+	}
+	todos, err := uc.repo.FindAll(query)
+	if err != nil {
+		return TodoPage{}, findError("Todoの取得に失敗しました", err)
+	}
+	total, err := uc.repo.Count(query)
+	if err != nil {
+		return TodoPage{}, findError("Todoの件数の取得に失敗しました", err)
+	}
+
+	page := TodoPage{Todos: todos, Total: total}
+	if limit > 0 && len(todos) > limit {
+		page.Todos = todos[:limit]
+		page.NextCursor = domain.NewCursor(todos[limit-1], query.Sort, query.Desc).Encode()
+	}
+	return page, nil
+}
+
 // CreateTodo は新しいTODOを作成するメソッド
-func (uc *TodoUseCase) CreateTodo(title string) (domain.Todo, error) {
-    // タイトルの検証
-    if title == "" {
-        return domain.Todo{}, errors.NewInvalidInputError("タイトルは必須です")
+func (uc *TodoUseCase) CreateTodo(input CreateTodoInput) (domain.Todo, error) {
+    // タイトルの検証（前後の空白を削除してから PatchTodo と同じ規則で検証する）
+    title := strings.TrimSpace(input.Title)
+    if valid, msg := utils.ValidateTitle(title); !valid {
+        return domain.Todo{}, errors.NewInvalidInputError(msg)
     }
 
-    // タイトルの長さ制限
-    if len(title) > 100 {
-        return domain.Todo{}, errors.NewInvalidInputError("タイトルは100文字以内にしてください")
+    if valid, msg := utils.ValidateDescription(input.Description); !valid {
+        return domain.Todo{}, errors.NewInvalidInputError(msg)
     }
-    
-    // タイトルのトリミング（前後の空白を削除）
-    title = strings.TrimSpace(title)
-    // トリミング後に空になった場合もエラー
-    if title == "" {
-        return domain.Todo{}, errors.NewInvalidInputError("タイトルに有効な文字を入力してください")
+
+    priority, err := parsePriority(input.Priority)
+    if err != nil {
+        return domain.Todo{}, err
     }
 
-    todo := domain.Todo{Title: title, Done: false}
-    if err := uc.repo.Create(&todo); err != nil {
+    if err := validateSchedule(input.StartAt, input.DueAt); err != nil {
+        return domain.Todo{}, err
+    }
+
+    recurrence, err := normalizeRecurrence(input.Recurrence, input.StartAt, input.DueAt)
+    if err != nil {
+        return domain.Todo{}, err
+    }
+
+    if input.ParentID != nil {
+        parent, err := uc.repo.FindByID(fmt.Sprint(*input.ParentID))
+        if err != nil {
+            return domain.Todo{}, errors.NewInternalError(fmt.Sprintf("ID %d のTodoの検索に失敗しました", *input.ParentID), err)
+        }
+        if parent == nil {
+            return domain.Todo{}, errors.NewNotFoundError(fmt.Sprintf("親タスク ID %d のTodoが見つかりません", *input.ParentID))
+        }
+    }
+
+    todo := domain.Todo{ParentID: input.ParentID, Title: title, Description: input.Description, Done: false, Priority: priority, StartAt: input.StartAt, DueAt: input.DueAt, Recurrence: recurrence}
+    todo.NormalizeSchedule()
+    if err := uc.repo.Create(&todo, uc.audit(domain.AuditCreate, domain.TodoChanges(nil, todo))); err != nil {
         return domain.Todo{}, errors.NewInternalError("Todoの作成に失敗しました", err)
     }
+    uc.log.Debugw("Todoを作成しました", "id", todo.ID, "parent_id", todo.ParentID, "priority", todo.Priority, "recurrence", todo.Recurrence)
     return todo, nil
 }
 
 // UpdateTodo は指定されたIDのTODOを更新するメソッド
-func (uc *TodoUseCase) UpdateTodo(id string, done bool) (domain.Todo, error) {
-	todo, err := uc.repo.FindByID(id)
+// version が0以外の場合は、TODOのバージョンと一致する場合のみ更新する（以下の更新・削除のメソッドも同様）
+func (uc *TodoUseCase) UpdateTodo(id string, done bool, version uint) (domain.Todo, error) {
+	return uc.updateInBatch(id, func(uc *TodoUseCase) (domain.Todo, error) {
+		todo, err := uc.findTodoForWrite(id, version)
+		if err != nil {
+			return domain.Todo{}, err
+		}
+		before := *todo
+
+		next, err := uc.changeDone(todo, done)
+		if err != nil {
+			return domain.Todo{}, err
+		}
+
+		if err := uc.repo.Update(todo, uc.audit(domain.AuditUpdate, domain.TodoChanges(&before, *todo))); err != nil {
+			return domain.Todo{}, persistError(id, "更新", err)
+		}
+
+		if err := uc.createNextOccurrence(id, next); err != nil {
+			return domain.Todo{}, err
+		}
+		return *todo, nil
+	})
+}
+
+// updateInBatch は完了状態の変更に伴うサブタスクの完了・TODOの更新・次回分の作成を1つのトランザクション（Batch）で実行する
+// いずれかが失敗した場合（TODOの更新が競合した場合など）はすべて取り消し、サブタスクだけが完了した状態を残さない
+func (uc *TodoUseCase) updateInBatch(id string, fn func(uc *TodoUseCase) (domain.Todo, error)) (domain.Todo, error) {
+	var todo domain.Todo
+	err := uc.repo.Batch(func(repo repository.TodoRepositoryInterface) error {
+		var err error
+		todo, err = fn(uc.withRepo(repo))
+		return err
+	})
 	if err != nil {
-		return domain.Todo{}, errors.NewInternalError(fmt.Sprintf("ID %s のTodoの検索に失敗しました", id), err)
-	}
-	
-	if todo == nil {
-		return domain.Todo{}, errors.NewNotFoundError(fmt.Sprintf("ID %s のTodoが見つかりません", id))
+		var appErr *errors.AppError
+		if stderrors.As(err, &appErr) {
+			return domain.Todo{}, err
+		}
+		return domain.Todo{}, persistError(id, "更新", err) // トランザクションの開始・確定に失敗した場合
 	}
+	return todo, nil
+}
 
-    todo.Done = done
-    if err := uc.repo.Update(todo); err != nil {
-		return domain.Todo{}, errors.NewInternalError(fmt.Sprintf("ID %s のTodoの更新に失敗しました", id), err)
+// changeDone はTODOの完了状態を変更する（保存は呼び出し側で行う）
+// 未完了から完了にする場合は完了ルールを適用し、繰り返しTODOであれば次回分を返す
+func (uc *TodoUseCase) changeDone(todo *domain.Todo, done bool) (*domain.Todo, error) {
+    var next *domain.Todo
+    if done && !todo.Done {
+        if err := uc.applyCompletionRule(todo); err != nil {
+            return nil, err
+        }
+        if todo.Recurrence != "" {
+            var err error
+            if next, err = uc.nextOccurrence(todo); err != nil {
+                return nil, err
+            }
+            // 繰り返し設定は次回のTodoに引き継ぎ、完了したTodoを再度完了にしても重複して作成しない
+            todo.Recurrence = ""
+        }
     }
-    return *todo, nil
+    todo.Done = done
+    return next, nil
 }
 
-// DeleteTodoByID は指定されたIDのTODOを削除するメソッド
-func (uc *TodoUseCase) DeleteTodoByID(id string) error {
-	todo, err := uc.repo.FindByID(id)
+// UpdateSchedule は指定されたIDのTODOの開始日時と期限日時を更新するメソッド
+// nilを渡した項目は未設定の状態になる
+func (uc *TodoUseCase) UpdateSchedule(id string, startAt, dueAt *time.Time, version uint) (domain.Todo, error) {
+	if err := validateSchedule(startAt, dueAt); err != nil {
+		return domain.Todo{}, err
+	}
+
+	todo, err := uc.findTodoForWrite(id, version)
 	if err != nil {
-		return errors.NewInternalError(fmt.Sprintf("ID %s のTodoの検索に失敗しました", id), err)
+		return domain.Todo{}, err
 	}
-	
-	if todo == nil {
-		return errors.NewNotFoundError(fmt.Sprintf("ID %s のTodoが見つかりません", id))
+	before := *todo
+
+	todo.StartAt = startAt
+	todo.DueAt = dueAt
+	todo.NormalizeSchedule()
+	if err := uc.repo.Update(todo, uc.audit(domain.AuditUpdate, domain.TodoChanges(&before, *todo))); err != nil {
+		return domain.Todo{}, persistError(id, "更新", err)
+	}
+	return *todo, nil
+}
+
+// UpdatePriority は指定されたIDのTODOの優先度を更新するメソッド
+func (uc *TodoUseCase) UpdatePriority(id string, priority string, version uint) (domain.Todo, error) {
+	p, err := parsePriority(priority)
+	if err != nil {
+		return domain.Todo{}, err
+	}
+
+	todo, err := uc.findTodoForWrite(id, version)
+	if err != nil {
+		return domain.Todo{}, err
+	}
+	before := *todo
+
+	todo.Priority = p
+	if err := uc.repo.Update(todo, uc.audit(domain.AuditUpdate, domain.TodoChanges(&before, *todo))); err != nil {
+		return domain.Todo{}, persistError(id, "更新", err)
+	}
+	return *todo, nil
+}
+
+// DeleteTodoByID は指定されたIDのTODOをゴミ箱に移動するメソッド（サブタスクも一緒に移動する）
+func (uc *TodoUseCase) DeleteTodoByID(id string, version uint) error {
+	todo, err := uc.findTodoForWrite(id, version)
+	if err != nil {
+		return err
 	}
 	
-	if err := uc.repo.Delete(todo); err != nil {
-		return errors.NewInternalError(fmt.Sprintf("ID %s のTodoの削除に失敗しました", id), err)
+	if err := uc.repo.Delete(todo, uc.audit(domain.AuditDelete, nil)); err != nil {
+		return persistError(id, "削除", err)
 	}
     return nil
-}
\ No newline at end of file
+}
+
+// findTodoForWrite は更新・削除するTODOを取得する
+// version が0以外で、取得したTODOのバージョンと一致しない場合は競合エラーを返す
+func (uc *TodoUseCase) findTodoForWrite(id string, version uint) (*domain.Todo, error) {
+	todo, err := uc.repo.FindByID(id)
+	if err != nil {
+		return nil, errors.NewInternalError(fmt.Sprintf("ID %s のTodoの検索に失敗しました", id), err)
+	}
+	if todo == nil {
+		return nil, errors.NewNotFoundError(fmt.Sprintf("ID %s のTodoが見つかりません", id))
+	}
+	if version != 0 && todo.Version != version {
+		uc.log.Debugw("Todoのバージョンが一致しません", "id", id, "expected", version, "actual", todo.Version)
+		return nil, errors.NewConflictError(fmt.Sprintf("ID %s のTodoは他の操作で更新されています（現在のバージョンは %d です）", id, todo.Version))
+	}
+	return todo, nil
+}
+
+// overdueBase は期限切れを判定する基準時刻を返す（過去の時点を指定した場合はその時点）
+func (uc *TodoUseCase) overdueBase(query domain.TodoQuery) time.Time {
+	if query.AsOf != nil {
+		return *query.AsOf
+	}
+	return uc.now()
+}
+
+// findError はTODOの一覧の取得に失敗した際のエラーを返す
+// 保存先が過去の時点の状態に対応していない場合は入力エラーとする
+func findError(msg string, err error) error {
+	if stderrors.Is(err, repository.ErrAsOfNotSupported) {
+		return errors.NewInvalidInputError("as_of はイベントストア（events://）を使用している場合のみ指定できます", err)
+	}
+	return errors.NewInternalError(msg, err)
+}
+
+// persistError はTODOの更新・削除に失敗したエラーを変換する
+// 取得してから保存するまでの間に他の操作で更新された場合は競合エラーにする
+func persistError(id string, action string, err error) error {
+	if stderrors.Is(err, repository.ErrVersionConflict) {
+		return errors.NewConflictError(fmt.Sprintf("ID %s のTodoは他の操作で更新されています", id), err)
+	}
+	return errors.NewInternalError(fmt.Sprintf("ID %s のTodoの%sに失敗しました", id, action), err)
+}
+
+// validateSchedule は開始日時と期限日時の前後関係を検証する
+func validateSchedule(startAt, dueAt *time.Time) error {
+	if startAt != nil && dueAt != nil && dueAt.Before(*startAt) {
+		return errors.NewInvalidInputError("期限日時は開始日時より後にしてください")
+	}
+	return nil
+}
+
+// parsePriority は優先度の文字列を検証して変換する
+func parsePriority(s string) (domain.Priority, error) {
+	p, err := domain.ParsePriority(s)
+	if err != nil {
+		return domain.PriorityNone, errors.NewInvalidInputError("優先度は none, low, medium, high, urgent のいずれかを指定してください", err)
+	}
+	return p, nil
+}
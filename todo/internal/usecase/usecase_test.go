@@ -1,30 +1,40 @@
 package usecase
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/domain"
 	"github.com/ko-taka-dev/golang_dev_journey/todo/internal/repository"
 	appErrors "github.com/ko-taka-dev/golang_dev_journey/todo/pkg/errors"
+	"github.com/ko-taka-dev/golang_dev_journey/todo/pkg/logger"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 type MockTodoRepository struct {
 	mock.Mock
+	ctx context.Context // WithContext で設定された ctx
 }
 
 var _ repository.TodoRepositoryInterface = (*MockTodoRepository)(nil) // インターフェース適合を保証
 
-func (m *MockTodoRepository) FindAll() ([]domain.Todo, error) {
-	args := m.Called()
+func (m *MockTodoRepository) FindAll(query domain.TodoQuery) ([]domain.Todo, error) {
+	args := m.Called(query)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]domain.Todo), args.Error(1)
 }
 
+func (m *MockTodoRepository) Count(query domain.TodoQuery) (int64, error) {
+	args := m.Called(query)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockTodoRepository) FindByID(id string) (*domain.Todo, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
@@ -33,25 +43,85 @@ func (m *MockTodoRepository) FindByID(id string) (*domain.Todo, error) {
 	return args.Get(0).(*domain.Todo), args.Error(1)
 }
 
-func (m *MockTodoRepository) Create(todo *domain.Todo) error {
-	args := m.Called(todo)
+func (m *MockTodoRepository) Create(todo *domain.Todo, audit *domain.AuditEntry) error {
+	args := m.Called(todo, audit)
 	return args.Error(0)
 }
 
-func (m *MockTodoRepository) Update(todo *domain.Todo) error {
-	args := m.Called(todo)
+func (m *MockTodoRepository) Update(todo *domain.Todo, audit *domain.AuditEntry) error {
+	args := m.Called(todo, audit)
 	return args.Error(0)
 }
 
-func (m *MockTodoRepository) Delete(todo *domain.Todo) error {
-	args := m.Called(todo)
+func (m *MockTodoRepository) Delete(todo *domain.Todo, audit *domain.AuditEntry) error {
+	args := m.Called(todo, audit)
+	return args.Error(0)
+}
+
+func (m *MockTodoRepository) Search(text string, limit int) ([]domain.SearchResult, error) {
+	args := m.Called(text, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.SearchResult), args.Error(1)
+}
+
+func (m *MockTodoRepository) FindTrashed() ([]domain.Todo, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Todo), args.Error(1)
+}
+
+func (m *MockTodoRepository) FindTrashedByID(id string) (*domain.Todo, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Todo), args.Error(1)
+}
+
+func (m *MockTodoRepository) Restore(todo *domain.Todo, audit *domain.AuditEntry) error {
+	args := m.Called(todo, audit)
 	return args.Error(0)
 }
 
+func (m *MockTodoRepository) Purge(before time.Time, audit *domain.AuditEntry) (int64, error) {
+	args := m.Called(before, audit)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTodoRepository) FindAuditLog(query domain.AuditQuery) ([]domain.AuditEntry, error) {
+	args := m.Called(query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.AuditEntry), args.Error(1)
+}
+
+// Batch は呼び出しを記録し、エラーを設定していなければ同じモックを渡して fn を実行する
+func (m *MockTodoRepository) Batch(fn func(repo repository.TodoRepositoryInterface) error) error {
+	args := m.Called()
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return fn(m)
+}
+
+// WithContext は ctx を記録して自身を返します（呼び出しの期待値は設定不要です）
+func (m *MockTodoRepository) WithContext(ctx context.Context) repository.TodoRepositoryInterface {
+	m.ctx = ctx
+	return m
+}
+
 func TestGetTodos(t *testing.T) {
 	// 様々なテストケースを実行
+	now := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+
 	testCases := []struct {
 		name          string
+		query         domain.TodoQuery
 		mockBehavior  func(*MockTodoRepository)
 		expectedTodos []domain.Todo
 		expectedError error
@@ -59,15 +129,24 @@ func TestGetTodos(t *testing.T) {
 		{
 			name: "正常系: すべてのTodoを取得",
 			mockBehavior: func(repo *MockTodoRepository) {
-				repo.On("FindAll").Return([]domain.Todo{{ID: 1, Title: "Todo 1", Done: false}, {ID: 2, Title: "Todo 2", Done: true}}, nil)
+				repo.On("FindAll", domain.TodoQuery{}).Return([]domain.Todo{{ID: 1, Title: "Todo 1", Done: false}, {ID: 2, Title: "Todo 2", Done: true}}, nil)
 			},
 			expectedTodos: []domain.Todo{{ID: 1, Title: "Todo 1", Done: false}, {ID: 2, Title: "Todo 2", Done: true}},
 			expectedError: nil,
 		},
+		{
+			name:  "正常系: 期限切れのTodoを現在時刻で絞り込む",
+			query: domain.TodoQuery{Overdue: true},
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindAll", domain.TodoQuery{Overdue: true, Now: now}).Return([]domain.Todo{{ID: 1, Title: "Todo 1", Done: false}}, nil)
+			},
+			expectedTodos: []domain.Todo{{ID: 1, Title: "Todo 1", Done: false}},
+			expectedError: nil,
+		},
 		{
 			name: "異常系: エラーが発生",
 			mockBehavior: func(repo *MockTodoRepository) {
-				repo.On("FindAll").Return(nil, errors.New("データベースエラー"))
+				repo.On("FindAll", domain.TodoQuery{}).Return(nil, errors.New("データベースエラー"))
 			},
 			expectedTodos: nil,
 			expectedError: appErrors.NewInternalError("Todoの取得に失敗しました", errors.New("データベースエラー")),
@@ -79,9 +158,9 @@ func TestGetTodos(t *testing.T) {
 			mockRepo := new(MockTodoRepository)
 			tc.mockBehavior(mockRepo)
 
-			uc := NewTodoUseCase(mockRepo)
+			uc := &TodoUseCase{repo: mockRepo, log: logger.GetLogger(), now: func() time.Time { return now }}
 
-			todos, err := uc.GetTodos()
+			todos, err := uc.GetTodos(tc.query)
 			if tc.expectedError != nil {
 				assert.Error(t, err)
 				assert.Equal(t, tc.expectedError.Error(), err.Error())
@@ -96,11 +175,114 @@ func TestGetTodos(t *testing.T) {
 	}
 }
 
+func TestGetTodoPage(t *testing.T) {
+	todos := []domain.Todo{{ID: 1, Title: "Todo 1"}, {ID: 2, Title: "Todo 2"}, {ID: 3, Title: "Todo 3"}}
+	after := domain.Cursor{ID: 1}
+	asOf := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		query         domain.TodoQuery
+		mockBehavior  func(*MockTodoRepository)
+		expectedPage  TodoPage
+		expectedError error
+	}{
+		{
+			name:  "正常系: 次のページがある",
+			query: domain.TodoQuery{Limit: 2},
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindAll", domain.TodoQuery{Limit: 3}).Return(todos, nil)
+				repo.On("Count", domain.TodoQuery{Limit: 3}).Return(int64(3), nil)
+			},
+			expectedPage:  TodoPage{Todos: todos[:2], Total: 3, NextCursor: domain.NewCursor(todos[1], domain.SortByDefault, false).Encode()},
+			expectedError: nil,
+		},
+		{
+			name:  "正常系: 最後のページ",
+			query: domain.TodoQuery{Limit: 2, After: &after},
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindAll", domain.TodoQuery{Limit: 3, After: &after}).Return(todos[1:], nil)
+				repo.On("Count", domain.TodoQuery{Limit: 3, After: &after}).Return(int64(3), nil)
+			},
+			expectedPage:  TodoPage{Todos: todos[1:], Total: 3},
+			expectedError: nil,
+		},
+		{
+			name:  "正常系: 件数を指定しない場合はすべて取得",
+			query: domain.TodoQuery{},
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindAll", domain.TodoQuery{}).Return(todos, nil)
+				repo.On("Count", domain.TodoQuery{}).Return(int64(3), nil)
+			},
+			expectedPage:  TodoPage{Todos: todos, Total: 3},
+			expectedError: nil,
+		},
+		{
+			name:          "異常系: 並び順の異なるカーソル",
+			query:         domain.TodoQuery{Sort: domain.SortByTitle, Limit: 2, After: &after},
+			mockBehavior:  func(repo *MockTodoRepository) {},
+			expectedPage:  TodoPage{},
+			expectedError: appErrors.NewInvalidInputError("カーソルは同じ並び順で取得したものを指定してください"),
+		},
+		{
+			name:  "異常系: 件数の取得に失敗",
+			query: domain.TodoQuery{},
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindAll", domain.TodoQuery{}).Return(todos, nil)
+				repo.On("Count", domain.TodoQuery{}).Return(int64(0), errors.New("データベースエラー"))
+			},
+			expectedPage:  TodoPage{},
+			expectedError: appErrors.NewInternalError("Todoの件数の取得に失敗しました", errors.New("データベースエラー")),
+		},
+		{
+			name:  "正常系: 過去の時点で期限切れのタスク",
+			query: domain.TodoQuery{Overdue: true, AsOf: &asOf},
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindAll", domain.TodoQuery{Overdue: true, Now: asOf, AsOf: &asOf}).Return(todos[:1], nil)
+				repo.On("Count", domain.TodoQuery{Overdue: true, Now: asOf, AsOf: &asOf}).Return(int64(1), nil)
+			},
+			expectedPage:  TodoPage{Todos: todos[:1], Total: 1},
+			expectedError: nil,
+		},
+		{
+			name:  "異常系: 過去の時点を取得できない保存先",
+			query: domain.TodoQuery{AsOf: &asOf},
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindAll", domain.TodoQuery{AsOf: &asOf}).Return(nil, repository.ErrAsOfNotSupported)
+			},
+			expectedPage:  TodoPage{},
+			expectedError: appErrors.NewInvalidInputError("as_of はイベントストア（events://）を使用している場合のみ指定できます", repository.ErrAsOfNotSupported),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockTodoRepository)
+			tc.mockBehavior(mockRepo)
+
+			uc := NewTodoUseCase(mockRepo)
+
+			page, err := uc.GetTodoPage(tc.query)
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedPage, page)
+
+			// モックの検証
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestCreateTodo(t *testing.T) {
 	// テストケースの定義
 	testCases := []struct {
 		name          string
 		inputTitle    string
+		inputPriority string
 		mockBehavior  func(*MockTodoRepository)
 		expectedTodo  domain.Todo
 		expectedError error
@@ -111,7 +293,7 @@ func TestCreateTodo(t *testing.T) {
 			mockBehavior: func(repo *MockTodoRepository) {
 				repo.On("Create", mock.MatchedBy(func(todo *domain.Todo) bool {
 				return todo.Title == "買い物に行く" && !todo.Done
-			})).Return(nil)},
+			}), mock.Anything).Return(nil)},
 			expectedTodo: domain.Todo{
 				Title: "買い物に行く",
 				Done:  false,
@@ -125,7 +307,7 @@ func TestCreateTodo(t *testing.T) {
 				// Create は呼ばれない想定
 			},
 			expectedTodo:  domain.Todo{},
-			expectedError: appErrors.NewInvalidInputError("タイトルは必須です"),
+			expectedError: appErrors.NewInvalidInputError("タイトルは空にできません"),
 		},
 		{
 			name:       "異常系: 空白のみのタイトル",
@@ -134,7 +316,7 @@ func TestCreateTodo(t *testing.T) {
 				// Create は呼ばれない想定
 			},
 			expectedTodo:  domain.Todo{},
-			expectedError: appErrors.NewInvalidInputError("タイトルに有効な文字を入力してください"),
+			expectedError: appErrors.NewInvalidInputError("タイトルは空にできません"),
 		},
 		{
 			name:       "異常系: タイトルが長すぎる",
@@ -143,7 +325,25 @@ func TestCreateTodo(t *testing.T) {
 				// Create は呼ばれない想定
 			},
 			expectedTodo:  domain.Todo{},
-			expectedError: appErrors.NewInvalidInputError("タイトルは100文字以内にしてください"),
+			expectedError: appErrors.NewInvalidInputError("タイトルが長すぎます"),
+		},
+		{
+			name:       "正常系: 100文字の日本語のタイトル",
+			inputTitle: strings.Repeat("あ", 100),
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedTodo:  domain.Todo{Title: strings.Repeat("あ", 100)},
+			expectedError: nil,
+		},
+		{
+			name:       "異常系: 不正なスクリプトを含むタイトル",
+			inputTitle: "<script>alert(1)</script>",
+			mockBehavior: func(repo *MockTodoRepository) {
+				// Create は呼ばれない想定
+			},
+			expectedTodo:  domain.Todo{},
+			expectedError: appErrors.NewInvalidInputError("タイトルに不正なスクリプトが含まれています"),
 		},
 		{
 			name:       "異常系: 空白のみのタイトル",
@@ -152,13 +352,35 @@ func TestCreateTodo(t *testing.T) {
 				// Create は呼ばれない想定
 			},
 			expectedTodo:  domain.Todo{},
-			expectedError: appErrors.NewInvalidInputError("タイトルに有効な文字を入力してください"),
+			expectedError: appErrors.NewInvalidInputError("タイトルは空にできません"),
+		},
+		{
+			name:          "異常系: 不明な優先度",
+			inputTitle:    "有効なタイトル",
+			inputPriority: "critical",
+			mockBehavior: func(repo *MockTodoRepository) {
+				// Create は呼ばれない想定
+			},
+			expectedTodo:  domain.Todo{},
+			expectedError: appErrors.NewInvalidInputError("優先度は none, low, medium, high, urgent のいずれかを指定してください", errors.New("不明な優先度です: critical")),
+		},
+		{
+			name:          "正常系: 優先度を指定して作成",
+			inputTitle:    "請求書を送る",
+			inputPriority: "urgent",
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("Create", mock.MatchedBy(func(todo *domain.Todo) bool {
+					return todo.Priority == domain.PriorityUrgent
+				}), mock.Anything).Return(nil)
+			},
+			expectedTodo:  domain.Todo{Title: "請求書を送る", Priority: domain.PriorityUrgent},
+			expectedError: nil,
 		},
 		{
 			name:       "異常系: リポジトリエラー",
 			inputTitle: "有効なタイトル",
 			mockBehavior: func(repo *MockTodoRepository) {
-				repo.On("Create", mock.Anything).Return(errors.New("データベースエラー"))
+				repo.On("Create", mock.Anything, mock.Anything).Return(errors.New("データベースエラー"))
 			},
 			expectedTodo:  domain.Todo{},
 			expectedError: appErrors.NewInternalError("Todoの作成に失敗しました", errors.New("データベースエラー")),
@@ -175,7 +397,7 @@ func TestCreateTodo(t *testing.T) {
 			uc := NewTodoUseCase(mockRepo)
 
 			// テスト実行
-			todo, err := uc.CreateTodo(tc.inputTitle)
+			todo, err := uc.CreateTodo(CreateTodoInput{Title: tc.inputTitle, Priority: tc.inputPriority})
 
 			// アサーション
 			if tc.expectedError != nil {
@@ -185,6 +407,7 @@ func TestCreateTodo(t *testing.T) {
 				assert.NoError(t, err)
 				assert.Equal(t, tc.expectedTodo.Title, todo.Title)
 				assert.Equal(t, tc.expectedTodo.Done, todo.Done)
+				assert.Equal(t, tc.expectedTodo.Priority, todo.Priority)
 			}
 
 			// モックの検証
@@ -199,6 +422,7 @@ func TestUpdateTodoByID(t *testing.T) {
 		name     string
 		id       string
 		done bool
+		version  uint
 		mockBehavior  func(*MockTodoRepository)
 		expectedTodo  *domain.Todo
 		expectedError error
@@ -211,7 +435,7 @@ func TestUpdateTodoByID(t *testing.T) {
 				repo.On("FindByID", "1").Return(&domain.Todo{ID: 1, Title: "Todo 1", Done: false}, nil)
 				repo.On("Update", mock.MatchedBy(func(todo *domain.Todo) bool {
 					return todo.ID == 1 && todo.Done == true
-				})).Return(nil)
+				}), mock.Anything).Return(nil)
 			},
 			expectedTodo:  &domain.Todo{ID: 1, Title: "Todo 1", Done: true},
 			expectedError: nil,
@@ -234,25 +458,60 @@ func TestUpdateTodoByID(t *testing.T) {
 				repo.On("FindByID", "1").Return(&domain.Todo{ID: 1, Title: "Todo 1", Done: false}, nil)
 				repo.On("Update", mock.MatchedBy(func(todo *domain.Todo) bool {
 					return todo.ID == 1 && todo.Done == false
-				})).Return(errors.New("database error"))
+				}), mock.Anything).Return(errors.New("database error"))
 			},
 			expectedTodo:  nil,
 			expectedError: appErrors.NewInternalError("ID 1 のTodoの更新に失敗しました", errors.New("database error")),
 		},
+		{
+			name:     "正常系: 指定したバージョンと一致",
+			id:       "1",
+			done:     true,
+			version:  2,
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindByID", "1").Return(&domain.Todo{ID: 1, Title: "Todo 1", Version: 2}, nil)
+				repo.On("Update", mock.MatchedBy(func(todo *domain.Todo) bool {
+					return todo.Version == 2 && todo.Done
+				}), mock.Anything).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:     "異常系: 指定したバージョンが古い",
+			id:       "1",
+			done:     true,
+			version:  1,
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindByID", "1").Return(&domain.Todo{ID: 1, Title: "Todo 1", Version: 2}, nil)
+			},
+			expectedError: appErrors.NewConflictError("ID 1 のTodoは他の操作で更新されています（現在のバージョンは 2 です）"),
+		},
+		{
+			name:     "異常系: 取得後に他の操作で更新された",
+			id:       "1",
+			done:     true,
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindByID", "1").Return(&domain.Todo{ID: 1, Title: "Todo 1", Version: 2}, nil)
+				repo.On("Update", mock.Anything, mock.Anything).Return(repository.ErrVersionConflict)
+			},
+			expectedError: appErrors.NewConflictError("ID 1 のTodoは他の操作で更新されています", repository.ErrVersionConflict),
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			mockRepo := new(MockTodoRepository)
+			mockRepo.On("Batch").Return(nil)
 			tc.mockBehavior(mockRepo)
 
 			uc := NewTodoUseCase(mockRepo)
 
-			todo, err := uc.UpdateTodo(tc.id, tc.done)
+			todo, err := uc.UpdateTodo(tc.id, tc.done, tc.version)
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
 				assert.Equal(t, tc.expectedError.Error(), err.Error())
+				assert.Equal(t, appErrors.IsConflict(tc.expectedError), appErrors.IsConflict(err))
 			} else {
 				assert.NoError(t, err)
 				assert.True(t, todo.Done)
@@ -263,11 +522,203 @@ func TestUpdateTodoByID(t *testing.T) {
 	}
 }
 
+func TestCreateTodoWithSchedule(t *testing.T) {
+	start := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	due := start.Add(48 * time.Hour)
+	jstDue := due.In(time.FixedZone("JST", 9*60*60))
+
+	testCases := []struct {
+		name          string
+		input         CreateTodoInput
+		mockBehavior  func(*MockTodoRepository)
+		expectedError error
+	}{
+		{
+			name:  "正常系: 開始日時と期限日時を指定して作成",
+			input: CreateTodoInput{Title: "レポート提出", StartAt: &start, DueAt: &due},
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("Create", mock.MatchedBy(func(todo *domain.Todo) bool {
+					return todo.StartAt.Equal(start) && todo.DueAt.Equal(due)
+				}), mock.Anything).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:  "正常系: 時差付きの日時はUTCにして保存する",
+			input: CreateTodoInput{Title: "レポート提出", DueAt: &jstDue},
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("Create", mock.MatchedBy(func(todo *domain.Todo) bool {
+					return todo.DueAt.Equal(due) && todo.DueAt.Location() == time.UTC
+				}), mock.Anything).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:  "異常系: 期限日時が開始日時より前",
+			input: CreateTodoInput{Title: "レポート提出", StartAt: &due, DueAt: &start},
+			mockBehavior: func(repo *MockTodoRepository) {
+				// Create は呼ばれない想定
+			},
+			expectedError: appErrors.NewInvalidInputError("期限日時は開始日時より後にしてください"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockTodoRepository)
+			tc.mockBehavior(mockRepo)
+
+			uc := NewTodoUseCase(mockRepo)
+
+			todo, err := uc.CreateTodo(tc.input)
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, tc.input.DueAt.Equal(*todo.DueAt))
+				assert.Equal(t, time.UTC, todo.DueAt.Location())
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateSchedule(t *testing.T) {
+	start := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	due := start.Add(24 * time.Hour)
+
+	testCases := []struct {
+		name          string
+		id            string
+		startAt       *time.Time
+		dueAt         *time.Time
+		mockBehavior  func(*MockTodoRepository)
+		expectedError error
+	}{
+		{
+			name:    "正常系: 期限日時を設定",
+			id:      "1",
+			startAt: &start,
+			dueAt:   &due,
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindByID", "1").Return(&domain.Todo{ID: 1, Title: "Todo 1"}, nil)
+				repo.On("Update", mock.MatchedBy(func(todo *domain.Todo) bool {
+					return todo.ID == 1 && todo.DueAt.Equal(due)
+				}), mock.Anything).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:    "異常系: 期限日時が開始日時より前",
+			id:      "1",
+			startAt: &due,
+			dueAt:   &start,
+			mockBehavior: func(repo *MockTodoRepository) {
+				// FindByID は呼ばれない想定
+			},
+			expectedError: appErrors.NewInvalidInputError("期限日時は開始日時より後にしてください"),
+		},
+		{
+			name:  "異常系: 存在しないID",
+			id:    "999",
+			dueAt: &due,
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindByID", "999").Return(nil, nil)
+			},
+			expectedError: appErrors.NewNotFoundError("ID 999 のTodoが見つかりません"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockTodoRepository)
+			tc.mockBehavior(mockRepo)
+
+			uc := NewTodoUseCase(mockRepo)
+
+			todo, err := uc.UpdateSchedule(tc.id, tc.startAt, tc.dueAt, 0)
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.dueAt, todo.DueAt)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdatePriority(t *testing.T) {
+	testCases := []struct {
+		name          string
+		id            string
+		priority      string
+		mockBehavior  func(*MockTodoRepository)
+		expectedError error
+	}{
+		{
+			name:     "正常系: 優先度を変更",
+			id:       "1",
+			priority: "high",
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindByID", "1").Return(&domain.Todo{ID: 1, Title: "Todo 1"}, nil)
+				repo.On("Update", mock.MatchedBy(func(todo *domain.Todo) bool {
+					return todo.ID == 1 && todo.Priority == domain.PriorityHigh
+				}), mock.Anything).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:     "異常系: 不明な優先度",
+			id:       "1",
+			priority: "highest",
+			mockBehavior: func(repo *MockTodoRepository) {
+				// FindByID は呼ばれない想定
+			},
+			expectedError: appErrors.NewInvalidInputError("優先度は none, low, medium, high, urgent のいずれかを指定してください", errors.New("不明な優先度です: highest")),
+		},
+		{
+			name:     "異常系: 存在しないID",
+			id:       "999",
+			priority: "low",
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindByID", "999").Return(nil, nil)
+			},
+			expectedError: appErrors.NewNotFoundError("ID 999 のTodoが見つかりません"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockTodoRepository)
+			tc.mockBehavior(mockRepo)
+
+			uc := NewTodoUseCase(mockRepo)
+
+			todo, err := uc.UpdatePriority(tc.id, tc.priority, 0)
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.priority, todo.Priority.String())
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestDeleteTodoByID(t *testing.T) {
 	// 様々なテストケースを実行	
 	testCases := []struct {
 		name     string
 		id       string
+		version  uint
 		mockBehavior  func(*MockTodoRepository)
 		expectedError error
 	}{
@@ -278,7 +729,7 @@ func TestDeleteTodoByID(t *testing.T) {
 				repo.On("FindByID", "1").Return(&domain.Todo{ID: 1, Title: "Todo 1", Done: false}, nil)
 				repo.On("Delete", mock.MatchedBy(func(todo *domain.Todo) bool {
 					return todo.ID == 1
-				})).Return(nil)
+				}), mock.Anything).Return(nil)
 			},
 			expectedError: nil,
 		},
@@ -297,10 +748,28 @@ func TestDeleteTodoByID(t *testing.T) {
 				repo.On("FindByID", "1").Return(&domain.Todo{ID: 1, Title: "Todo 1", Done: false}, nil)
 				repo.On("Delete", mock.MatchedBy(func(todo *domain.Todo) bool {
 					return todo.ID == 1
-				})).Return(errors.New("database error"))
+				}), mock.Anything).Return(errors.New("database error"))
 			},
 			expectedError: appErrors.NewInternalError("ID 1 のTodoの削除に失敗しました", errors.New("database error")),
 		},
+		{
+			name:     "異常系: 指定したバージョンが古い",
+			id:       "1",
+			version:  1,
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindByID", "1").Return(&domain.Todo{ID: 1, Title: "Todo 1", Version: 3}, nil)
+			},
+			expectedError: appErrors.NewConflictError("ID 1 のTodoは他の操作で更新されています（現在のバージョンは 3 です）"),
+		},
+		{
+			name:     "異常系: 取得後に他の操作で更新された",
+			id:       "1",
+			mockBehavior: func(repo *MockTodoRepository) {
+				repo.On("FindByID", "1").Return(&domain.Todo{ID: 1, Title: "Todo 1", Version: 3}, nil)
+				repo.On("Delete", mock.Anything, mock.Anything).Return(repository.ErrVersionConflict)
+			},
+			expectedError: appErrors.NewConflictError("ID 1 のTodoは他の操作で更新されています", repository.ErrVersionConflict),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -310,7 +779,7 @@ func TestDeleteTodoByID(t *testing.T) {
 
 			uc := NewTodoUseCase(mockRepo)
 
-			err := uc.DeleteTodoByID(tc.id)
+			err := uc.DeleteTodoByID(tc.id, tc.version)
 			if tc.expectedError != nil {
 				assert.Error(t, err)
 				assert.Equal(t, tc.expectedError.Error(), err.Error())
@@ -9,6 +9,7 @@ const (
     NotFound      = "NOT_FOUND"
     InvalidInput  = "INVALID_INPUT"
     InternalError = "INTERNAL_ERROR"
+    Conflict      = "CONFLICT"
 )
 
 // AppError はアプリケーション固有のエラー情報を保持
@@ -70,6 +71,19 @@ func NewInternalError(message string, err ...error) *AppError {
     }
 }
 
+// NewConflictError は「他の更新と競合した」エラーを作成
+func NewConflictError(message string, err ...error) *AppError {
+    var originalErr error
+    if len(err) > 0 {
+        originalErr = err[0]
+    }
+    return &AppError{
+        Type:    Conflict,
+        Message: message,
+        Err:     originalErr,
+    }
+}
+
 // IsNotFound はエラーが「リソースが見つからない」エラーかどうかを判定
 func IsNotFound(err error) bool {
     var appErr *AppError
@@ -107,4 +121,17 @@ func IsInternalError(err error) bool {
         return appErr.Type == InternalError
     }
     return false
+}
+
+// IsConflict はエラーが「他の更新と競合した」エラーかどうかを判定
+func IsConflict(err error) bool {
+    var appErr *AppError
+    if err == nil {
+        return false
+    }
+    if as, ok := err.(*AppError); ok {
+        appErr = as
+        return appErr.Type == Conflict
+    }
+    return false
 }
\ No newline at end of file
@@ -0,0 +1,269 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Format はログの出力形式
+type Format string
+
+// ログの出力形式
+const (
+	FormatText Format = "text" // key=value 形式
+	FormatJSON Format = "json" // 1行に1つのJSONオブジェクト
+)
+
+// ログの設定を読み込む環境変数
+const (
+	EnvLevel  = "TODO_LOG_LEVEL"  // debug, info, warn, error のいずれか
+	EnvFormat = "TODO_LOG_FORMAT" // text または json
+	EnvRedact = "TODO_LOG_REDACT" // 伏せ字にするキーのカンマ区切り（空の場合は伏せ字にしない）
+)
+
+// DefaultRedactKeys は既定で値を伏せ字にするキー（TODOの内容は個人的な情報を含む場合があるため）
+var DefaultRedactKeys = []string{"title", "description"}
+
+// redacted は伏せ字にした値の代わりに出力する文字列
+const redacted = "[REDACTED]"
+
+// levelFatal は FATAL に対応する slog のレベル
+const levelFatal = slog.LevelError + 4
+
+// Option はLoggerの任意設定
+type Option func(*config)
+
+// config はLoggerの設定
+type config struct {
+	format     Format
+	level      int
+	redactKeys map[string]bool // 値を伏せ字にするキー（小文字）
+	file       io.Writer       // 標準出力・標準エラー出力に加えて、すべてのレベルのログを書き込む先
+}
+
+// defaultConfig は設定を指定しなかった場合の値を返す
+func defaultConfig() config {
+	cfg := config{format: FormatText, level: INFO}
+	WithRedactKeys(DefaultRedactKeys...)(&cfg)
+	return cfg
+}
+
+// WithFormat はログの出力形式を設定する
+func WithFormat(format Format) Option {
+	return func(c *config) {
+		c.format = format
+	}
+}
+
+// WithLevel は出力するログの最低レベルを設定する
+func WithLevel(level int) Option {
+	return func(c *config) {
+		c.level = level
+	}
+}
+
+// WithRedactKeys は値を伏せ字にするキーを設定する（大文字と小文字は区別しない）
+// Infow や With で指定した値のうち、キーが一致するものを [REDACTED] に置き換える。何も指定しない場合は伏せ字にしない
+func WithRedactKeys(keys ...string) Option {
+	return func(c *config) {
+		c.redactKeys = make(map[string]bool, len(keys))
+		for _, key := range keys {
+			if key = strings.TrimSpace(key); key != "" {
+				c.redactKeys[strings.ToLower(key)] = true
+			}
+		}
+	}
+}
+
+// WithFile はすべてのレベルのログを、標準出力・標準エラー出力に加えて w にも書き込む（RotatingFile を指定する）
+func WithFile(w io.Writer) Option {
+	return func(c *config) {
+		c.file = w
+	}
+}
+
+// ParseLevel はログレベルの名前（debug, info, warn, error）をレベルに変換する
+func ParseLevel(name string) (int, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return DEBUG, nil
+	case "info", "":
+		return INFO, nil
+	case "warn", "warning":
+		return WARN, nil
+	case "error":
+		return ERROR, nil
+	}
+	return 0, fmt.Errorf("ログレベルには debug, info, warn, error のいずれかを指定してください: %s", name)
+}
+
+// ParseFormat は出力形式の名前（text, json）を Format に変換する
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(name)) {
+	case FormatText, "":
+		return FormatText, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	}
+	return "", fmt.Errorf("ログの出力形式には text または json を指定してください: %s", name)
+}
+
+// ParseRedactKeys はカンマ区切りのキーを WithRedactKeys に指定できる形に分割する
+func ParseRedactKeys(list string) []string {
+	return strings.Split(list, ",")
+}
+
+// OptionsFromEnv は環境変数からLoggerの設定を読み込む
+// 値が正しくない環境変数は無視し、既定の設定を使う
+func OptionsFromEnv() []Option {
+	var opts []Option
+	if level, err := ParseLevel(os.Getenv(EnvLevel)); err == nil {
+		opts = append(opts, WithLevel(level))
+	}
+	if format, err := ParseFormat(os.Getenv(EnvFormat)); err == nil {
+		opts = append(opts, WithFormat(format))
+	}
+	if list, ok := os.LookupEnv(EnvRedact); ok {
+		opts = append(opts, WithRedactKeys(ParseRedactKeys(list)...))
+	}
+	return opts
+}
+
+// slogLevel はログレベルを slog のレベルに変換する
+func slogLevel(level int) slog.Level {
+	switch level {
+	case DEBUG:
+		return slog.LevelDebug
+	case INFO:
+		return slog.LevelInfo
+	case WARN:
+		return slog.LevelWarn
+	case ERROR:
+		return slog.LevelError
+	}
+	return levelFatal
+}
+
+// levelFromSlog は slog のレベルをログレベルに変換する
+func levelFromSlog(level slog.Level) int {
+	switch {
+	case level < slog.LevelInfo:
+		return DEBUG
+	case level < slog.LevelWarn:
+		return INFO
+	case level < slog.LevelError:
+		return WARN
+	case level < levelFatal:
+		return ERROR
+	}
+	return FATAL
+}
+
+// LevelName はログレベルの名前（debug, info, warn, error, fatal）を返す
+func LevelName(level int) string {
+	switch level {
+	case DEBUG:
+		return "debug"
+	case INFO:
+		return "info"
+	case WARN:
+		return "warn"
+	case ERROR:
+		return "error"
+	}
+	return "fatal"
+}
+
+// newHandler は設定に従って slog のハンドラーを作成する
+// out と errOut が異なる場合は、ERROR 以上のログだけを errOut に出力する
+// コンポーネントごとにレベルが異なるため、レベルでの絞り込みは Logger（または levelHandler）で行う
+func newHandler(out, errOut io.Writer, cfg config) slog.Handler {
+	if cfg.file != nil {
+		// 標準出力に書き込めない場合（GUIから起動した場合など）もファイルには書き込めるよう、ファイルを先にする
+		if out == errOut {
+			out = io.MultiWriter(cfg.file, out)
+			errOut = out
+		} else {
+			out, errOut = io.MultiWriter(cfg.file, out), io.MultiWriter(cfg.file, errOut)
+		}
+	}
+	opts := &slog.HandlerOptions{Level: slog.LevelDebug, ReplaceAttr: cfg.replaceAttr}
+	build := func(w io.Writer) slog.Handler {
+		if cfg.format == FormatJSON {
+			return slog.NewJSONHandler(w, opts)
+		}
+		return slog.NewTextHandler(w, opts)
+	}
+	if out == errOut {
+		return build(out)
+	}
+	return &splitHandler{out: build(out), errOut: build(errOut)}
+}
+
+// replaceAttr は伏せ字にするキーの値を置き換え、FATAL のレベル名を出力できるようにする
+func (c config) replaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 && a.Key == slog.LevelKey {
+		if level, ok := a.Value.Any().(slog.Level); ok && level >= levelFatal {
+			return slog.String(slog.LevelKey, "FATAL")
+		}
+		return a
+	}
+	if c.redactKeys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, redacted)
+	}
+	return a
+}
+
+// levelHandler は level 未満のログを出力しないハンドラー（標準の log パッケージと slog の出力に使う）
+type levelHandler struct {
+	slog.Handler
+	level slog.Leveler
+}
+
+// Enabled はレベルのログを出力するかを返す
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level() && h.Handler.Enabled(ctx, level)
+}
+
+// WithAttrs は属性を追加したハンドラーを返す（レベルは共有する）
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithAttrs(attrs), level: h.level}
+}
+
+// WithGroup はグループを追加したハンドラーを返す（レベルは共有する）
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithGroup(name), level: h.level}
+}
+
+// splitHandler はレベルによって出力先のハンドラーを切り替える
+type splitHandler struct {
+	out    slog.Handler // ERROR 未満
+	errOut slog.Handler // ERROR 以上
+}
+
+// Enabled はレベルのログを出力するかを返す（どちらのハンドラーも同じレベルを共有する）
+func (h *splitHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.out.Enabled(ctx, level)
+}
+
+// Handle はレベルに応じたハンドラーでログを出力する
+func (h *splitHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelError {
+		return h.errOut.Handle(ctx, r)
+	}
+	return h.out.Handle(ctx, r)
+}
+
+// WithAttrs は両方のハンドラーに属性を追加する
+func (h *splitHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &splitHandler{out: h.out.WithAttrs(attrs), errOut: h.errOut.WithAttrs(attrs)}
+}
+
+// WithGroup は両方のハンドラーにグループを追加する
+func (h *splitHandler) WithGroup(name string) slog.Handler {
+	return &splitHandler{out: h.out.WithGroup(name), errOut: h.errOut.WithGroup(name)}
+}
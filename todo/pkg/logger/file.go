@@ -0,0 +1,384 @@
+package logger
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ログファイルのローテーションと保持の既定値
+const (
+	DefaultMaxSize    = 10 << 20            // 1つのファイルの最大サイズ（10MB）
+	DefaultMaxAge     = 30 * 24 * time.Hour // ローテーションしたファイルを残す期間
+	DefaultMaxBackups = 10                  // ローテーションしたファイルを残す数
+)
+
+// LogFileName はログディレクトリに作成するログファイルの名前
+const LogFileName = "todo.log"
+
+// appName はログディレクトリの名前に使うアプリケーション名
+const appName = "todo"
+
+// backupTimeFormat はローテーションしたファイルの名前に付ける日時の形式（Windowsでも使える文字だけを使う）
+const backupTimeFormat = "20060102T150405.000"
+
+// compressedExt は圧縮したファイルの拡張子
+const compressedExt = ".gz"
+
+// rotateRetryInterval はローテーションに失敗した後、やり直すまでの間隔
+const rotateRetryInterval = time.Minute
+
+// DefaultDir は利用者ごとのログディレクトリを返す
+// macOS は ~/Library/Logs/todo、Windows は %LocalAppData%\todo\logs、それ以外は $XDG_STATE_HOME/todo/logs（未設定の場合は ~/.local/state/todo/logs）
+func DefaultDir() (string, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, "Library", "Logs", appName), nil
+	case "windows":
+		dir, err := os.UserCacheDir() // %LocalAppData%
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, appName, "logs"), nil
+	}
+	if dir := os.Getenv("XDG_STATE_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, appName, "logs"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "state", appName, "logs"), nil
+}
+
+// RotateOption はログファイルのローテーションの任意設定
+type RotateOption func(*RotatingFile)
+
+// WithMaxSize は1つのファイルの最大サイズ（バイト）を設定する（0以下の場合はサイズでローテーションしない）
+func WithMaxSize(size int64) RotateOption {
+	return func(f *RotatingFile) {
+		f.maxSize = size
+	}
+}
+
+// WithDailyRotation は日付が変わったときにローテーションするかを設定する
+func WithDailyRotation(daily bool) RotateOption {
+	return func(f *RotatingFile) {
+		f.daily = daily
+	}
+}
+
+// WithMaxAge はローテーションしたファイルを残す期間を設定する（0以下の場合は期間で削除しない）
+func WithMaxAge(age time.Duration) RotateOption {
+	return func(f *RotatingFile) {
+		f.maxAge = age
+	}
+}
+
+// WithMaxBackups はローテーションしたファイルを残す数を設定する（0以下の場合は数で削除しない）
+func WithMaxBackups(n int) RotateOption {
+	return func(f *RotatingFile) {
+		f.maxBackups = n
+	}
+}
+
+// WithCompression はローテーションしたファイルをgzipで圧縮するかを設定する
+func WithCompression(compress bool) RotateOption {
+	return func(f *RotatingFile) {
+		f.compress = compress
+	}
+}
+
+// RotatingFile はサイズと日付でローテーションするログファイル（io.Writer）
+// ローテーションしたファイルは todo-20250401T090000.000.log のように日時を付けた名前に変更し、圧縮と古いファイルの削除はバックグラウンドで行う
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	daily      bool
+	maxAge     time.Duration
+	maxBackups int
+	compress   bool
+	now        func() time.Time                    // 現在時刻の取得（テスト時に差し替え可能）
+	rename     func(oldpath, newpath string) error // ファイル名の変更（テスト時に差し替え可能）
+
+	mu      sync.Mutex
+	file    *os.File  // 開けなかった場合は nil（次の書き込みで開き直す）
+	closed  bool      // Close したか
+	size    int64     // 書き込み中のファイルのサイズ
+	day     time.Time // 書き込み中のファイルに最後に書き込んだ日（日付でのローテーションの判定に使う）
+	retryAt time.Time // ローテーションに失敗した場合に、次にやり直す日時
+
+	cleanupMu sync.Mutex     // 圧縮と削除を同時に実行しないようにする
+	cleanups  sync.WaitGroup // 実行中の圧縮と削除（Close で待つ）
+}
+
+// OpenRotatingFile はログファイルを追記用に開く（ディレクトリがなければ作成する）
+// 既定では DefaultMaxSize を超えたときと日付が変わったときにローテーションし、圧縮して DefaultMaxAge の間・DefaultMaxBackups 個まで残す
+func OpenRotatingFile(path string, opts ...RotateOption) (*RotatingFile, error) {
+	f := &RotatingFile{
+		path:       path,
+		maxSize:    DefaultMaxSize,
+		daily:      true,
+		maxAge:     DefaultMaxAge,
+		maxBackups: DefaultMaxBackups,
+		compress:   true,
+		now:        time.Now,
+		rename:     os.Rename,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("ログディレクトリを作成できません: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	// 前回までに残ったファイルも整理する
+	f.startCleanup(f.now())
+	return f, nil
+}
+
+// Path はログファイルのパスを返す
+func (f *RotatingFile) Path() string {
+	return f.path
+}
+
+// Write はログファイルに追記する
+// 書き込むとサイズを超える場合や、前回の書き込みから日付が変わった場合は、先にローテーションする
+// ローテーションに失敗した場合（Windowsで他のプロセスがファイルを開いている場合など）は元のファイルに追記を続け、rotateRetryInterval の後にやり直す
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+	now := f.now()
+	if f.file != nil && f.needsRotation(now, len(p)) {
+		if err := f.rotate(now); err != nil {
+			// ログファイル自体の問題のため、ログには出力しない
+			fmt.Fprintf(os.Stderr, "ログファイルのローテーションに失敗しました: %v\n", err)
+			f.retryAt = now.Add(rotateRetryInterval)
+		}
+	}
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	f.day = startOfDay(now)
+	return n, err
+}
+
+// Close はログファイルを閉じ、実行中の圧縮と削除が終わるのを待つ
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	f.closed = true
+	f.mu.Unlock()
+
+	f.cleanups.Wait()
+	return err
+}
+
+// open はログファイルを追記用に開き、サイズと最後に書き込んだ日を読み込む
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("ログファイルを開けません: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("ログファイルの情報を取得できません: %w", err)
+	}
+	f.file, f.size = file, info.Size()
+	f.day = startOfDay(info.ModTime())
+	return nil
+}
+
+// needsRotation は len バイトを書き込む前にローテーションするかを判定する（空のファイルはローテーションしない）
+func (f *RotatingFile) needsRotation(now time.Time, n int) bool {
+	if f.size == 0 || now.Before(f.retryAt) {
+		return false
+	}
+	if f.maxSize > 0 && f.size+int64(n) > f.maxSize {
+		return true
+	}
+	return f.daily && !startOfDay(now).Equal(f.day)
+}
+
+// rotate は書き込み中のファイルを日時を付けた名前に変更し、新しいファイルを開く
+// 失敗した場合は f.file を nil にして返す（呼び出し側で元のファイルを開き直す）
+func (f *RotatingFile) rotate(now time.Time) error {
+	err := f.file.Close()
+	f.file = nil
+	if err != nil {
+		return fmt.Errorf("ログファイルを閉じられません: %w", err)
+	}
+
+	// 同じ時刻に複数回ローテーションした場合は、名前が重ならないようにずらす
+	backup := f.backupPath(now)
+	for {
+		if _, err := os.Stat(backup); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		now = now.Add(time.Millisecond)
+		backup = f.backupPath(now)
+	}
+	if err := f.rename(f.path, backup); err != nil {
+		return fmt.Errorf("ログファイルの名前を変更できません: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+	f.startCleanup(now)
+	return nil
+}
+
+// backupPath はローテーションしたファイルの名前を返す
+func (f *RotatingFile) backupPath(t time.Time) string {
+	prefix, ext := f.nameParts()
+	return filepath.Join(filepath.Dir(f.path), prefix+t.Format(backupTimeFormat)+ext)
+}
+
+// nameParts はローテーションしたファイルの名前の前半（todo-）と拡張子（.log）を返す
+func (f *RotatingFile) nameParts() (prefix, ext string) {
+	base := filepath.Base(f.path)
+	ext = filepath.Ext(base)
+	return strings.TrimSuffix(base, ext) + "-", ext
+}
+
+// startCleanup は now の時点で保持する期間を過ぎたファイルの削除と圧縮をバックグラウンドで始める
+func (f *RotatingFile) startCleanup(now time.Time) {
+	f.cleanups.Add(1)
+	go func() {
+		defer f.cleanups.Done()
+		f.cleanupMu.Lock()
+		defer f.cleanupMu.Unlock()
+		if err := f.cleanup(now); err != nil {
+			// ログファイル自体の問題のため、ログには出力しない
+			fmt.Fprintf(os.Stderr, "ログファイルの整理に失敗しました: %v\n", err)
+		}
+	}()
+}
+
+// backupFile はローテーションしたファイル
+type backupFile struct {
+	path       string
+	rotatedAt  time.Time
+	compressed bool
+}
+
+// cleanup は保持する期間・数を超えたファイルを削除し、残すファイルのうち圧縮していないものを圧縮する
+func (f *RotatingFile) cleanup(now time.Time) error {
+	backups, err := f.backups()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	cutoff := now.Add(-f.maxAge)
+	for i, b := range backups {
+		if (f.maxBackups > 0 && i >= f.maxBackups) || (f.maxAge > 0 && b.rotatedAt.Before(cutoff)) {
+			if err := os.Remove(b.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if f.compress && !b.compressed {
+			if err := compressFile(b.path); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// backups はローテーションしたファイルを新しい順に返す
+func (f *RotatingFile) backups() ([]backupFile, error) {
+	entries, err := os.ReadDir(filepath.Dir(f.path))
+	if err != nil {
+		return nil, fmt.Errorf("ログディレクトリを読み込めません: %w", err)
+	}
+	prefix, ext := f.nameParts()
+	var backups []backupFile
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp, compressed := strings.TrimPrefix(name, prefix), strings.HasSuffix(name, ext+compressedExt)
+		stamp = strings.TrimSuffix(strings.TrimSuffix(stamp, compressedExt), ext)
+		rotatedAt, err := time.ParseInLocation(backupTimeFormat, stamp, time.Local)
+		if err != nil {
+			continue // ローテーションしたファイルではない
+		}
+		backups = append(backups, backupFile{path: filepath.Join(filepath.Dir(f.path), name), rotatedAt: rotatedAt, compressed: compressed})
+	}
+	slices.SortFunc(backups, func(a, b backupFile) int { return b.rotatedAt.Compare(a.rotatedAt) })
+	return backups, nil
+}
+
+// compressFile はファイルをgzipで圧縮し、元のファイルを削除する
+// 途中で失敗した場合に壊れた圧縮ファイルが残らないよう、一時ファイルに書き込んでから名前を変更する
+func compressFile(path string) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp := path + compressedExt + ".tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(tmp)
+		}
+	}()
+
+	zw := gzip.NewWriter(dst)
+	if _, err := io.Copy(zw, src); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path+compressedExt); err != nil {
+		return err
+	}
+	src.Close() // Windows では開いたままのファイルを削除できない
+	return os.Remove(path)
+}
+
+// startOfDay は t と同じ日の0時（ローカル時刻）を返す
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.In(time.Local).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
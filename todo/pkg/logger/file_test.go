@@ -0,0 +1,207 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openTestFile はテスト用の一時ディレクトリにログファイルを開き、現在時刻を *now に差し替える
+func openTestFile(t *testing.T, now *time.Time, opts ...RotateOption) (*RotatingFile, string) {
+	t.Helper()
+	dir := t.TempDir()
+	f, err := OpenRotatingFile(filepath.Join(dir, LogFileName), append([]RotateOption{func(f *RotatingFile) {
+		f.now = func() time.Time { return *now }
+	}}, opts...)...)
+	require.NoError(t, err)
+	return f, dir
+}
+
+// listFiles はディレクトリ内のファイル名を並べて返す
+func listFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	slices.Sort(names)
+	return names
+}
+
+// readFile はファイルの内容を返す（.gz の場合は展開する）
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	if filepath.Ext(path) != compressedExt {
+		return string(data)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	data, err = io.ReadAll(zr)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestRotatingFile(t *testing.T) {
+	t.Run("サイズを超える場合はローテーションする", func(t *testing.T) {
+		now := time.Date(2025, 4, 1, 9, 0, 0, 0, time.Local)
+		f, dir := openTestFile(t, &now, WithMaxSize(10), WithCompression(false))
+
+		_, err := f.Write([]byte("12345678\n"))
+		require.NoError(t, err)
+		now = now.Add(time.Second)
+		_, err = f.Write([]byte("abc\n"))
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		assert.Equal(t, []string{"todo-20250401T090001.000.log", "todo.log"}, listFiles(t, dir))
+		assert.Equal(t, "12345678\n", readFile(t, filepath.Join(dir, "todo-20250401T090001.000.log")))
+		assert.Equal(t, "abc\n", readFile(t, filepath.Join(dir, "todo.log")))
+	})
+
+	t.Run("日付が変わった場合はローテーションして圧縮する", func(t *testing.T) {
+		now := time.Date(2025, 4, 1, 23, 59, 0, 0, time.Local)
+		f, dir := openTestFile(t, &now)
+
+		_, err := f.Write([]byte("1日目\n"))
+		require.NoError(t, err)
+		_, err = f.Write([]byte("1日目の続き\n"))
+		require.NoError(t, err)
+		now = now.Add(2 * time.Minute)
+		_, err = f.Write([]byte("2日目\n"))
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		assert.Equal(t, []string{"todo-20250402T000100.000.log.gz", "todo.log"}, listFiles(t, dir))
+		assert.Equal(t, "1日目\n1日目の続き\n", readFile(t, filepath.Join(dir, "todo-20250402T000100.000.log.gz")))
+		assert.Equal(t, "2日目\n", readFile(t, filepath.Join(dir, "todo.log")))
+	})
+
+	t.Run("日付でのローテーションを無効にする", func(t *testing.T) {
+		now := time.Date(2025, 4, 1, 23, 59, 0, 0, time.Local)
+		f, dir := openTestFile(t, &now, WithDailyRotation(false))
+
+		_, err := f.Write([]byte("1日目\n"))
+		require.NoError(t, err)
+		now = now.Add(2 * time.Minute)
+		_, err = f.Write([]byte("2日目\n"))
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		assert.Equal(t, []string{"todo.log"}, listFiles(t, dir))
+	})
+
+	t.Run("保持する数と期間を超えたファイルを削除する", func(t *testing.T) {
+		now := time.Date(2025, 4, 10, 9, 0, 0, 0, time.Local)
+		dir := t.TempDir()
+		for _, name := range []string{
+			"todo-20250409T090000.000.log.gz", // 残す
+			"todo-20250408T090000.000.log",    // 残す（圧縮する）
+			"todo-20250407T090000.000.log.gz", // 数を超える
+			"todo-20250301T090000.000.log.gz", // 期間と数を超える
+			"other-20250301T090000.000.log",   // ローテーションしたファイルではない
+		} {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o644))
+		}
+
+		f, err := OpenRotatingFile(filepath.Join(dir, LogFileName), WithMaxBackups(2), WithMaxAge(7*24*time.Hour), func(f *RotatingFile) {
+			f.now = func() time.Time { return now }
+		})
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		assert.Equal(t, []string{"other-20250301T090000.000.log", "todo-20250408T090000.000.log.gz", "todo-20250409T090000.000.log.gz", "todo.log"}, listFiles(t, dir))
+
+		// 期間を超えたファイルを削除する
+		now = time.Date(2025, 4, 15, 12, 0, 0, 0, time.Local)
+		f, err = OpenRotatingFile(filepath.Join(dir, LogFileName), WithMaxBackups(2), WithMaxAge(7*24*time.Hour), func(f *RotatingFile) {
+			f.now = func() time.Time { return now }
+		})
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		assert.Equal(t, []string{"other-20250301T090000.000.log", "todo-20250409T090000.000.log.gz", "todo.log"}, listFiles(t, dir))
+	})
+
+	t.Run("既存のファイルに追記する", func(t *testing.T) {
+		now := time.Now()
+		f, dir := openTestFile(t, &now)
+		_, err := f.Write([]byte("1回目\n"))
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		_, err = f.Write([]byte("閉じた後\n"))
+		assert.ErrorIs(t, err, os.ErrClosed)
+
+		f, err = OpenRotatingFile(filepath.Join(dir, LogFileName))
+		require.NoError(t, err)
+		_, err = f.Write([]byte("2回目\n"))
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		assert.Equal(t, "1回目\n2回目\n", readFile(t, filepath.Join(dir, LogFileName)))
+	})
+
+	t.Run("ローテーションに失敗した場合は元のファイルに追記を続け、後でやり直す", func(t *testing.T) {
+		now := time.Date(2025, 4, 1, 9, 0, 0, 0, time.Local)
+		renames := 0
+		f, dir := openTestFile(t, &now, WithMaxSize(10), WithCompression(false), func(f *RotatingFile) {
+			f.rename = func(oldpath, newpath string) error {
+				renames++
+				if renames == 1 {
+					return errors.New("別のプロセスが使用中です")
+				}
+				return os.Rename(oldpath, newpath)
+			}
+		})
+
+		_, err := f.Write([]byte("12345678\n"))
+		require.NoError(t, err)
+		now = now.Add(time.Second)
+		_, err = f.Write([]byte("abc\n"))
+		require.NoError(t, err)
+		assert.Equal(t, 1, renames)
+
+		// rotateRetryInterval が経つまではやり直さない
+		now = now.Add(rotateRetryInterval / 2)
+		_, err = f.Write([]byte("def\n"))
+		require.NoError(t, err)
+		assert.Equal(t, 1, renames)
+		assert.Equal(t, []string{"todo.log"}, listFiles(t, dir))
+
+		now = now.Add(rotateRetryInterval)
+		_, err = f.Write([]byte("ghi\n"))
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		assert.Equal(t, 2, renames)
+		assert.Equal(t, []string{"todo-20250401T090131.000.log", "todo.log"}, listFiles(t, dir))
+		assert.Equal(t, "12345678\nabc\ndef\n", readFile(t, filepath.Join(dir, "todo-20250401T090131.000.log")))
+		assert.Equal(t, "ghi\n", readFile(t, filepath.Join(dir, LogFileName)))
+	})
+}
+
+func TestWithFile(t *testing.T) {
+	now := time.Now()
+	f, dir := openTestFile(t, &now)
+	var out, errOut bytes.Buffer
+	l := New(&out, &errOut, WithFile(f), WithFormat(FormatJSON))
+	l.Info("情報")
+	l.Error("エラー")
+	require.NoError(t, f.Close())
+
+	// ファイルにはすべてのレベルのログを書き込む
+	content := readFile(t, filepath.Join(dir, LogFileName))
+	assert.Contains(t, content, `"msg":"情報"`)
+	assert.Contains(t, content, `"msg":"エラー"`)
+	assert.Contains(t, out.String(), `"msg":"情報"`)
+	assert.Contains(t, errOut.String(), `"msg":"エラー"`)
+}
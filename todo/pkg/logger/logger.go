@@ -1,9 +1,17 @@
 package logger
 
 import (
-    "log"
+    "context"
+    "fmt"
+    "io"
+    "log/slog"
     "os"
+    "path/filepath"
+    "runtime"
+    "strings"
     "sync"
+    "sync/atomic"
+    "time"
 )
 
 // ログレベルを定義
@@ -15,109 +23,287 @@ const (
     FATAL
 )
 
+// コンポーネント名（Named に指定すると、コンポーネントごとにログレベルを変更できる）
+const (
+    ComponentServer     = "server"
+    ComponentUseCase    = "usecase"
+    ComponentRepository = "repository"
+    ComponentGUI        = "gui"
+    ComponentClient     = "client"
+)
+
+// Components はログレベルを変更できるコンポーネントの一覧
+var Components = []string{ComponentServer, ComponentUseCase, ComponentRepository, ComponentGUI, ComponentClient}
+
 var (
     once   sync.Once
     logger *Logger
 )
 
 // Logger はアプリケーションのロギングを担当
+// log/slog の上に構築し、Info/Infof などの従来のメソッドと、Infow などのキーと値を指定するメソッドを提供する
 type Logger struct {
-    debugLogger *log.Logger
-    infoLogger  *log.Logger
-    warnLogger  *log.Logger
-    errorLogger *log.Logger
-    fatalLogger *log.Logger
-    level       int
+    handler   slog.Handler // レベルで絞り込む前のハンドラー（With で付けた値を含む）
+    level     slog.Leveler // 出力する最低のレベル（ルートは levels.root、コンポーネントは levels.components の値）
+    levels    *levels      // With と Named で作成した子のLoggerと共有する
+    component string       // Named で作成した場合のコンポーネント名
+}
+
+// levels はルートとコンポーネントごとのログレベル（実行中にほかのゴルーチンから変更できる）
+type levels struct {
+    root       slog.LevelVar
+    mu         sync.Mutex
+    components map[string]*componentLevel
+}
+
+// newLevels は Components のログレベルを登録した levels を作成する（コンポーネントはルートのレベルに従う）
+func newLevels(level slog.Level) *levels {
+    lv := &levels{components: make(map[string]*componentLevel, len(Components))}
+    lv.root.Set(level)
+    for _, name := range Components {
+        lv.components[name] = &componentLevel{root: &lv.root}
+    }
+    return lv
+}
+
+// component はコンポーネントのログレベルを返す（登録していない場合は登録する）
+func (lv *levels) component(name string) *componentLevel {
+    lv.mu.Lock()
+    defer lv.mu.Unlock()
+    c, ok := lv.components[name]
+    if !ok {
+        c = &componentLevel{root: &lv.root}
+        lv.components[name] = c
+    }
+    return c
+}
+
+// componentLevel はコンポーネントのログレベル（設定していない場合はルートのレベルに従う）
+type componentLevel struct {
+    root  *slog.LevelVar
+    level atomic.Pointer[slog.Level] // nil の場合はルートのレベル
+}
+
+// Level は出力する最低のレベルを返す（slog.Leveler）
+func (c *componentLevel) Level() slog.Level {
+    if level := c.level.Load(); level != nil {
+        return *level
+    }
+    return c.root.Level()
+}
+
+// set はコンポーネントのログレベルを設定する（以降はルートのレベルを変更しても変わらない）
+func (c *componentLevel) set(level slog.Level) {
+    c.level.Store(&level)
+}
+
+// requestIDKey はリクエストIDを context に保存するキー
+type requestIDKey struct{}
+
+// ContextWithRequestID はリクエストIDを保存した context を返す
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+    return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext は context に保存したリクエストIDを返す（保存していない場合は空文字列）
+func RequestIDFromContext(ctx context.Context) string {
+    id, _ := ctx.Value(requestIDKey{}).(string)
+    return id
 }
 
 // GetLogger はシングルトンのLoggerインスタンスを返す
+// 出力形式・ログレベル・伏せ字にする項目は環境変数（TODO_LOG_FORMAT, TODO_LOG_LEVEL, TODO_LOG_REDACT）から読み込む
 func GetLogger() *Logger {
     once.Do(func() {
-        logger = &Logger{
-            debugLogger: log.New(os.Stdout, "DEBUG: ", log.Ldate|log.Ltime|log.Lshortfile),
-            infoLogger:  log.New(os.Stdout, "INFO: ", log.Ldate|log.Ltime),
-            warnLogger:  log.New(os.Stdout, "WARN: ", log.Ldate|log.Ltime),
-            errorLogger: log.New(os.Stderr, "ERROR: ", log.Ldate|log.Ltime|log.Lshortfile),
-            fatalLogger: log.New(os.Stderr, "FATAL: ", log.Ldate|log.Ltime|log.Lshortfile),
-            level:       INFO, // デフォルトはINFOレベル
-        }
+        logger = New(os.Stdout, os.Stderr, OptionsFromEnv()...)
     })
     return logger
 }
 
+// Configure はシングルトンのLoggerの設定を変更する
+// 標準の log パッケージと slog の出力も同じ形式にする。起動時、ほかのゴルーチンでログを出力する前に呼び出す
+func Configure(opts ...Option) {
+    l := GetLogger()
+    *l = *New(os.Stdout, os.Stderr, opts...)
+    slog.SetDefault(slog.New(&levelHandler{Handler: l.handler, level: l.level}))
+}
+
+// New は出力先を指定してLoggerを作成する（DEBUG〜WARNは out、ERROR以上は errOut に出力する）
+// アプリケーションでは GetLogger を使い、出力を確認するテストなどで使用する
+func New(out, errOut io.Writer, opts ...Option) *Logger {
+    cfg := defaultConfig()
+    for _, opt := range opts {
+        opt(&cfg)
+    }
+    lv := newLevels(slogLevel(cfg.level))
+    return &Logger{handler: newHandler(out, errOut, cfg), level: &lv.root, levels: lv}
+}
+
+// With はキーと値を、出力するすべてのログに付けるLoggerを返す
+// ログレベルは元のLoggerと共有する
+func (l *Logger) With(keysAndValues ...interface{}) *Logger {
+    if len(keysAndValues) == 0 {
+        return l
+    }
+    child := *l
+    child.handler = slog.New(l.handler).With(keysAndValues...).Handler()
+    return &child
+}
+
+// Named はコンポーネント名を component として付け、コンポーネントごとのログレベルで出力するLoggerを返す
+// ルートのLogger（GetLogger）から作成する。コンポーネントのレベルを設定するまではルートのレベルに従う
+func (l *Logger) Named(name string) *Logger {
+    child := *l
+    child.handler = l.handler.WithAttrs([]slog.Attr{slog.String("component", name)})
+    child.level = l.levels.component(name)
+    child.component = name
+    return &child
+}
+
+// WithContext は context に保存したリクエストIDを request_id として付けるLoggerを返す
+// 同じリクエストの処理中に出力したログを request_id で関連付けられるようにする（リクエストIDがない場合はそのまま返す）
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+    id := RequestIDFromContext(ctx)
+    if id == "" {
+        return l
+    }
+    return l.With("request_id", id)
+}
+
 // SetLevel はロガーのログレベルを設定
+// Named で作成したLoggerの場合はそのコンポーネントのレベルを、それ以外の場合はルートのレベルを設定する
 func (l *Logger) SetLevel(level int) {
-    l.level = level
+    if l.component != "" {
+        l.levels.component(l.component).set(slogLevel(level))
+        return
+    }
+    l.levels.root.Set(slogLevel(level))
+}
+
+// SetComponentLevel はコンポーネントのログレベルを設定する（name が空の場合はルートのレベルを設定する）
+// 実行中にほかのゴルーチンから呼び出してもよい。Components にも Named にもないコンポーネントの場合はエラーを返す
+func (l *Logger) SetComponentLevel(name string, level int) error {
+    if name == "" {
+        l.levels.root.Set(slogLevel(level))
+        return nil
+    }
+    l.levels.mu.Lock()
+    c, ok := l.levels.components[name]
+    l.levels.mu.Unlock()
+    if !ok {
+        return fmt.Errorf("存在しないコンポーネントです: %s", name)
+    }
+    c.set(slogLevel(level))
+    return nil
+}
+
+// Levels はルートのログレベルと、コンポーネントごとの現在のログレベルを返す
+func (l *Logger) Levels() (root int, components map[string]int) {
+    l.levels.mu.Lock()
+    defer l.levels.mu.Unlock()
+    components = make(map[string]int, len(l.levels.components))
+    for name, c := range l.levels.components {
+        components[name] = levelFromSlog(c.Level())
+    }
+    return levelFromSlog(l.levels.root.Level()), components
+}
+
+// Enabled は指定したレベルのログを出力するかを返す（ログの内容を作るのに時間がかかる場合に確認する）
+func (l *Logger) Enabled(level int) bool {
+    return slogLevel(level) >= l.level.Level()
 }
 
 // Debug はデバッグレベルのログを出力
 func (l *Logger) Debug(v ...interface{}) {
-    if l.level <= DEBUG {
-        l.debugLogger.Println(v...)
-    }
+    l.log(DEBUG, sprintln(v...))
 }
 
 // Debugf はフォーマット付きのデバッグレベルのログを出力
 func (l *Logger) Debugf(format string, v ...interface{}) {
-    if l.level <= DEBUG {
-        l.debugLogger.Printf(format, v...)
-    }
+    l.log(DEBUG, fmt.Sprintf(format, v...))
+}
+
+// Debugw はキーと値を付けたデバッグレベルのログを出力
+func (l *Logger) Debugw(msg string, keysAndValues ...interface{}) {
+    l.log(DEBUG, msg, keysAndValues...)
 }
 
 // Info は情報レベルのログを出力
 func (l *Logger) Info(v ...interface{}) {
-    if l.level <= INFO {
-        l.infoLogger.Println(v...)
-    }
+    l.log(INFO, sprintln(v...))
 }
 
 // Infof はフォーマット付きの情報レベルのログを出力
 func (l *Logger) Infof(format string, v ...interface{}) {
-    if l.level <= INFO {
-        l.infoLogger.Printf(format, v...)
-    }
+    l.log(INFO, fmt.Sprintf(format, v...))
+}
+
+// Infow はキーと値を付けた情報レベルのログを出力
+func (l *Logger) Infow(msg string, keysAndValues ...interface{}) {
+    l.log(INFO, msg, keysAndValues...)
 }
 
 // Warn は警告レベルのログを出力
 func (l *Logger) Warn(v ...interface{}) {
-    if l.level <= WARN {
-        l.warnLogger.Println(v...)
-    }
+    l.log(WARN, sprintln(v...))
 }
 
 // Warnf はフォーマット付きの警告レベルのログを出力
 func (l *Logger) Warnf(format string, v ...interface{}) {
-    if l.level <= WARN {
-        l.warnLogger.Printf(format, v...)
-    }
+    l.log(WARN, fmt.Sprintf(format, v...))
+}
+
+// Warnw はキーと値を付けた警告レベルのログを出力
+func (l *Logger) Warnw(msg string, keysAndValues ...interface{}) {
+    l.log(WARN, msg, keysAndValues...)
 }
 
 // Error はエラーレベルのログを出力
 func (l *Logger) Error(v ...interface{}) {
-    if l.level <= ERROR {
-        l.errorLogger.Println(v...)
-    }
+    l.log(ERROR, sprintln(v...))
 }
 
 // Errorf はフォーマット付きのエラーレベルのログを出力
 func (l *Logger) Errorf(format string, v ...interface{}) {
-    if l.level <= ERROR {
-        l.errorLogger.Printf(format, v...)
-    }
+    l.log(ERROR, fmt.Sprintf(format, v...))
+}
+
+// Errorw はキーと値を付けたエラーレベルのログを出力
+func (l *Logger) Errorw(msg string, keysAndValues ...interface{}) {
+    l.log(ERROR, msg, keysAndValues...)
 }
 
 // Fatal は致命的なエラーレベルのログを出力し、プログラムを終了する
 func (l *Logger) Fatal(v ...interface{}) {
-    if l.level <= FATAL {
-        l.fatalLogger.Println(v...)
-        os.Exit(1)
-    }
+    l.log(FATAL, sprintln(v...))
+    os.Exit(1)
 }
 
 // Fatalf はフォーマット付きの致命的なエラーレベルのログを出力し、プログラムを終了する
 func (l *Logger) Fatalf(format string, v ...interface{}) {
-    if l.level <= FATAL {
-        l.fatalLogger.Printf(format, v...)
-        os.Exit(1)
+    l.log(FATAL, fmt.Sprintf(format, v...))
+    os.Exit(1)
+}
+
+// log はレベルが有効な場合にログを出力する
+// DEBUG と ERROR 以上では、従来どおり呼び出し元のファイル名と行番号を source として付ける
+func (l *Logger) log(level int, msg string, keysAndValues ...interface{}) {
+    ctx := context.Background()
+    if slogLevel(level) < l.level.Level() || !l.handler.Enabled(ctx, slogLevel(level)) {
+        return
     }
-}
\ No newline at end of file
+    r := slog.NewRecord(time.Now(), slogLevel(level), msg, 0)
+    if level == DEBUG || level >= ERROR {
+        // log を呼び出した Debugf などのメソッドの、さらに呼び出し元
+        if _, file, line, ok := runtime.Caller(2); ok {
+            r.AddAttrs(slog.String("source", fmt.Sprintf("%s:%d", filepath.Base(file), line)))
+        }
+    }
+    r.Add(keysAndValues...)
+    _ = l.handler.Handle(ctx, r)
+}
+
+// sprintln は fmt.Println と同じ形式で値をつなげる（末尾の改行は付けない）
+func sprintln(v ...interface{}) string {
+    return strings.TrimSuffix(fmt.Sprintln(v...), "\n")
+}
@@ -0,0 +1,259 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decodeLines はJSON形式で出力したログを1行ずつ読み込む（時刻は比較しないため削除する）
+func decodeLines(t *testing.T, out *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry), line)
+		delete(entry, "time")
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestLogger(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     []Option
+		log      func(l *Logger)
+		expected []map[string]interface{}
+	}{
+		{
+			name: "正常系: 従来のメソッドはメッセージとして出力する",
+			log: func(l *Logger) {
+				l.Info("Todoを", 3, "件返却しました")
+				l.Infof("Todoを作成しました: id=%d", 1)
+				l.Warnf("タグ %q は存在しません", "仕事")
+			},
+			expected: []map[string]interface{}{
+				{"level": "INFO", "msg": "Todoを 3 件返却しました"},
+				{"level": "INFO", "msg": "Todoを作成しました: id=1"},
+				{"level": "WARN", "msg": `タグ "仕事" は存在しません`},
+			},
+		},
+		{
+			name: "正常系: キーと値を付けて出力する",
+			log: func(l *Logger) {
+				l.Infow("Todoを更新しました", "id", 1, "done", true)
+			},
+			expected: []map[string]interface{}{
+				{"level": "INFO", "msg": "Todoを更新しました", "id": 1.0, "done": true},
+			},
+		},
+		{
+			name: "正常系: With で付けた値を子のLoggerのすべてのログに付ける",
+			log: func(l *Logger) {
+				child := l.With("component", "server")
+				child.Info("起動しました")
+				child.Infow("停止しました", "reason", "signal")
+				l.Info("親のLogger")
+			},
+			expected: []map[string]interface{}{
+				{"level": "INFO", "msg": "起動しました", "component": "server"},
+				{"level": "INFO", "msg": "停止しました", "component": "server", "reason": "signal"},
+				{"level": "INFO", "msg": "親のLogger"},
+			},
+		},
+		{
+			name: "正常系: WithContext でリクエストIDを付ける",
+			log: func(l *Logger) {
+				l.WithContext(ContextWithRequestID(context.Background(), "req-1")).Info("リクエストを受信しました")
+				l.WithContext(context.Background()).Info("リクエストIDなし")
+			},
+			expected: []map[string]interface{}{
+				{"level": "INFO", "msg": "リクエストを受信しました", "request_id": "req-1"},
+				{"level": "INFO", "msg": "リクエストIDなし"},
+			},
+		},
+		{
+			name: "正常系: 既定ではタイトルと説明を伏せ字にする",
+			log: func(l *Logger) {
+				l.With("title", "病院の予約").Infow("Todoを作成しました", "id", 1, "Description", "10時に電話")
+			},
+			expected: []map[string]interface{}{
+				{"level": "INFO", "msg": "Todoを作成しました", "title": "[REDACTED]", "id": 1.0, "Description": "[REDACTED]"},
+			},
+		},
+		{
+			name: "正常系: 伏せ字にするキーを変更する",
+			opts: []Option{WithRedactKeys("name")},
+			log: func(l *Logger) {
+				l.Infow("タグを作成しました", "name", "家族", "title", "買い物")
+			},
+			expected: []map[string]interface{}{
+				{"level": "INFO", "msg": "タグを作成しました", "name": "[REDACTED]", "title": "買い物"},
+			},
+		},
+		{
+			name: "正常系: 設定したレベル未満のログは出力しない",
+			opts: []Option{WithLevel(WARN)},
+			log: func(l *Logger) {
+				l.Debug("デバッグ")
+				l.Info("情報")
+				l.Warn("警告")
+			},
+			expected: []map[string]interface{}{
+				{"level": "WARN", "msg": "警告"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			l := New(&out, &out, append([]Option{WithFormat(FormatJSON)}, tc.opts...)...)
+			tc.log(l)
+			assert.Equal(t, tc.expected, decodeLines(t, &out))
+		})
+	}
+}
+
+func TestLoggerOutput(t *testing.T) {
+	t.Run("ERROR以上は errOut に出力する", func(t *testing.T) {
+		var out, errOut bytes.Buffer
+		l := New(&out, &errOut)
+		l.Info("情報")
+		l.Errorw("保存に失敗しました", "id", 1)
+
+		assert.Contains(t, out.String(), `level=INFO msg=情報`)
+		assert.NotContains(t, out.String(), "保存に失敗しました")
+		assert.Contains(t, errOut.String(), `level=ERROR msg=保存に失敗しました source=logger_test.go:`)
+		assert.Contains(t, errOut.String(), " id=1\n")
+	})
+
+	t.Run("SetLevel は子のLoggerにも反映する", func(t *testing.T) {
+		var out bytes.Buffer
+		l := New(&out, &out, WithFormat(FormatJSON))
+		child := l.With("component", "server")
+		l.SetLevel(ERROR)
+		child.Info("出力しない")
+		l.SetLevel(DEBUG)
+		child.Debug("出力する")
+
+		entries := decodeLines(t, &out)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "出力する", entries[0]["msg"])
+	})
+
+	t.Run("DEBUG とエラーには呼び出し元を付ける", func(t *testing.T) {
+		var out bytes.Buffer
+		l := New(&out, &out, WithFormat(FormatJSON), WithLevel(DEBUG))
+		_, _, line, _ := runtime.Caller(0)
+		l.Debugw("デバッグ")
+		l.Info("情報")
+		l.Errorf("エラー: %d", 1)
+
+		assert.Equal(t, []map[string]interface{}{
+			{"level": "DEBUG", "msg": "デバッグ", "source": fmt.Sprintf("logger_test.go:%d", line+1)},
+			{"level": "INFO", "msg": "情報"},
+			{"level": "ERROR", "msg": "エラー: 1", "source": fmt.Sprintf("logger_test.go:%d", line+3)},
+		}, decodeLines(t, &out))
+	})
+}
+
+func TestNamed(t *testing.T) {
+	t.Run("コンポーネントごとにログレベルを変更する", func(t *testing.T) {
+		var out bytes.Buffer
+		l := New(&out, &out, WithFormat(FormatJSON))
+		server, client := l.Named(ComponentServer), l.Named(ComponentClient)
+		require.NoError(t, l.SetComponentLevel(ComponentServer, DEBUG))
+		server.With("id", 1).Debug("サーバーのデバッグ")
+		client.Debug("出力しない")
+		l.Debug("出力しない")
+
+		// 設定していないコンポーネントはルートのレベルに従い、設定したコンポーネントは変わらない
+		require.NoError(t, l.SetComponentLevel("", WARN))
+		server.Info("サーバーの情報")
+		client.Info("出力しない")
+		client.SetLevel(ERROR)
+		client.Error("クライアントのエラー")
+
+		entries := decodeLines(t, &out)
+		for _, entry := range entries {
+			delete(entry, "source")
+		}
+		assert.Equal(t, []map[string]interface{}{
+			{"level": "DEBUG", "msg": "サーバーのデバッグ", "component": "server", "id": 1.0},
+			{"level": "INFO", "msg": "サーバーの情報", "component": "server"},
+			{"level": "ERROR", "msg": "クライアントのエラー", "component": "client"},
+		}, entries)
+
+		root, components := l.Levels()
+		assert.Equal(t, WARN, root)
+		assert.Equal(t, map[string]int{
+			ComponentServer: DEBUG, ComponentUseCase: WARN, ComponentRepository: WARN, ComponentGUI: WARN, ComponentClient: ERROR,
+		}, components)
+	})
+
+	t.Run("存在しないコンポーネントはエラーにする", func(t *testing.T) {
+		l := New(io.Discard, io.Discard)
+		assert.EqualError(t, l.SetComponentLevel("unknown", DEBUG), "存在しないコンポーネントです: unknown")
+
+		l.Named("scheduler")
+		assert.NoError(t, l.SetComponentLevel("scheduler", DEBUG))
+	})
+
+	t.Run("実行中にレベルを変更できる", func(t *testing.T) {
+		l := New(io.Discard, io.Discard).Named(ComponentRepository)
+		var wg sync.WaitGroup
+		for i := range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range 100 {
+					l.Debug("デバッグ")
+					l.SetLevel(i % 2)
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func TestOptionsFromEnv(t *testing.T) {
+	t.Setenv(EnvLevel, "debug")
+	t.Setenv(EnvFormat, "json")
+	t.Setenv(EnvRedact, "")
+
+	var out bytes.Buffer
+	l := New(&out, &out, OptionsFromEnv()...)
+	l.Debugw("デバッグ", "title", "買い物")
+
+	entries := decodeLines(t, &out)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "DEBUG", entries[0]["level"])
+	assert.Equal(t, "買い物", entries[0]["title"])
+}
+
+func TestParseLevel(t *testing.T) {
+	for name, expected := range map[string]int{"debug": DEBUG, "INFO": INFO, "": INFO, "warn": WARN, "error": ERROR} {
+		level, err := ParseLevel(name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, expected, level, name)
+	}
+	_, err := ParseLevel("verbose")
+	assert.EqualError(t, err, "ログレベルには debug, info, warn, error のいずれかを指定してください: verbose")
+
+	_, err = ParseFormat("xml")
+	assert.EqualError(t, err, "ログの出力形式には text または json を指定してください: xml")
+}
@@ -0,0 +1,258 @@
+package rrule
+
+import (
+	"time"
+)
+
+// maxEmptyPeriods は該当日のない期間がこの回数続いたら展開を打ち切る上限
+// （BYMONTH=2;BYMONTHDAY=30 のように発生しないルールで無限ループしないため）
+const maxEmptyPeriods = 10000
+
+// Iterator は dtstart 以降の発生日時を時系列順に返す
+type Iterator struct {
+	rule    *Rule
+	dtstart time.Time
+	until   time.Time   // タイムゾーンを解決した UNTIL（ゼロ値は無制限）
+	period  int         // 次に展開する期間（dtstart を含む期間が0）
+	pending []time.Time // 展開済みで未返却の日時
+	emitted int         // 返却した件数
+	empty   int         // 該当日のない期間が続いた回数
+	done    bool
+}
+
+// Iter は dtstart を起点とする発生日時のイテレータを返す
+// 発生日時は dtstart のタイムゾーンで dtstart と同じ壁時計時刻になる
+func (r *Rule) Iter(dtstart time.Time) *Iterator {
+	it := &Iterator{rule: r, dtstart: dtstart}
+	if !r.Until.IsZero() {
+		it.until = r.Until
+		if r.untilLayout != "" && r.untilLayout != untilUTC {
+			u := r.Until
+			it.until = time.Date(u.Year(), u.Month(), u.Day(), u.Hour(), u.Minute(), u.Second(), u.Nanosecond(), dtstart.Location())
+		}
+	}
+	return it
+}
+
+// Next は次の発生日時を返す。発生日時が残っていない場合は false を返す
+func (it *Iterator) Next() (time.Time, bool) {
+	for !it.done {
+		if len(it.pending) == 0 {
+			if it.empty >= maxEmptyPeriods {
+				it.done = true
+				break
+			}
+			it.pending = it.expand(it.period)
+			it.period++
+			if len(it.pending) == 0 {
+				it.empty++
+			} else {
+				it.empty = 0
+			}
+			continue
+		}
+
+		t := it.pending[0]
+		it.pending = it.pending[1:]
+		if t.Before(it.dtstart) {
+			continue
+		}
+		if !it.until.IsZero() && t.After(it.until) {
+			it.done = true
+			break
+		}
+		it.emitted++
+		if it.rule.Count > 0 && it.emitted >= it.rule.Count {
+			it.done = true
+		}
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// All は dtstart を起点とする発生日時を最大 limit 件返す
+func (r *Rule) All(dtstart time.Time, limit int) []time.Time {
+	var out []time.Time
+	it := r.Iter(dtstart)
+	for len(out) < limit {
+		t, ok := it.Next()
+		if !ok {
+			break
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+// After は dtstart を起点とする系列のうち t より後の最初の発生日時を返す
+func (r *Rule) After(dtstart, t time.Time) (time.Time, bool) {
+	it := r.Iter(dtstart)
+	for {
+		next, ok := it.Next()
+		if !ok || next.After(t) {
+			return next, ok
+		}
+	}
+}
+
+// Advance は dtstart より後の最初の発生日時と、その日時を起点として残りの系列を表すルールを返す
+// COUNT を指定したルールでは、dtstart 以前に消費した回数だけ COUNT を減らす
+func (r *Rule) Advance(dtstart time.Time) (time.Time, *Rule, bool) {
+	it := r.Iter(dtstart)
+	for consumed := 0; ; consumed++ {
+		t, ok := it.Next()
+		if !ok {
+			return time.Time{}, nil, false
+		}
+		if t.After(dtstart) {
+			rest := r.clone()
+			if r.Count > 0 {
+				rest.Count = r.Count - consumed
+			}
+			return t, rest, true
+		}
+	}
+}
+
+// clone はスライスを含めてルールを複製する
+func (r *Rule) clone() *Rule {
+	c := *r
+	c.ByMonth = append([]time.Month(nil), r.ByMonth...)
+	c.ByMonthDay = append([]int(nil), r.ByMonthDay...)
+	c.ByDay = append([]Weekday(nil), r.ByDay...)
+	return &c
+}
+
+// expand は p 番目の期間に含まれる発生日時を昇順で返す
+func (it *Iterator) expand(p int) []time.Time {
+	r := it.rule
+	y, m, d := it.dtstart.Date()
+	step := p * r.Interval
+
+	switch r.Freq {
+	case Daily:
+		day := civil(y, m, d+step)
+		return it.collect(day, day, func(time.Time) bool { return true })
+	case Weekly:
+		start := civil(y, m, d)
+		offset := (int(start.Weekday()) - int(r.WeekStart) + 7) % 7
+		first := start.AddDate(0, 0, 7*step-offset)
+		weekday := start.Weekday()
+		return it.collect(first, first.AddDate(0, 0, 6), func(c time.Time) bool { return c.Weekday() == weekday })
+	case Monthly:
+		first := civil(y, m+time.Month(step), 1)
+		return it.collect(first, first.AddDate(0, 1, -1), func(c time.Time) bool { return c.Day() == d })
+	case Yearly:
+		year := y + step
+		if len(r.ByMonth) > 0 {
+			// BYMONTH がある場合、BYDAY の序数は月内で数える
+			var out []time.Time
+			for _, month := range r.sortedMonths() {
+				first := civil(year, month, 1)
+				out = append(out, it.collect(first, first.AddDate(0, 1, -1), func(c time.Time) bool { return c.Day() == d })...)
+			}
+			return out
+		}
+		return it.collect(civil(year, time.January, 1), civil(year, time.December, 31), func(c time.Time) bool {
+			return c.Month() == m && c.Day() == d
+		})
+	}
+	return nil
+}
+
+// collect は [first, last] の日付のうちルールに一致するものを発生日時に変換して返す
+// BYMONTHDAY と BYDAY のどちらも指定がない場合は isDefault で dtstart 由来の日付かを判定する
+func (it *Iterator) collect(first, last time.Time, isDefault func(time.Time) bool) []time.Time {
+	r := it.rule
+	span := int(last.Sub(first)/(24*time.Hour)) + 1
+
+	var out []time.Time
+	for i := 0; i < span; i++ {
+		c := first.AddDate(0, 0, i)
+		if len(r.ByMonth) > 0 && !containsMonth(r.ByMonth, c.Month()) {
+			continue
+		}
+		if len(r.ByMonthDay) > 0 && !r.matchesMonthDay(c) {
+			continue
+		}
+		if len(r.ByDay) > 0 && !r.matchesDay(c, i, span-1-i) {
+			continue
+		}
+		if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 && !isDefault(c) {
+			continue
+		}
+		out = append(out, it.at(c))
+	}
+	return out
+}
+
+// matchesMonthDay は日付 c が BYMONTHDAY に一致するかを判定する
+func (r *Rule) matchesMonthDay(c time.Time) bool {
+	daysInMonth := civil(c.Year(), c.Month()+1, 0).Day()
+	for _, d := range r.ByMonthDay {
+		if d == c.Day() || d < 0 && daysInMonth+d+1 == c.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesDay は日付 c が BYDAY に一致するかを判定する
+// index と rest は期間の先頭・末尾までの日数で、序数付きの曜日の判定に使う
+func (r *Rule) matchesDay(c time.Time, index, rest int) bool {
+	for _, w := range r.ByDay {
+		if w.Day != c.Weekday() {
+			continue
+		}
+		if w.N == 0 || w.N > 0 && index/7+1 == w.N || w.N < 0 && -(rest/7+1) == w.N {
+			return true
+		}
+	}
+	return false
+}
+
+// at は日付 c に dtstart の壁時計時刻を組み合わせた日時を返す
+// 夏時間の終了で2回現れる時刻は早い方を、夏時間の開始で存在しない時刻は
+// 切り替え前のUTCオフセットで解釈した日時を返す（RFC 5545 3.3.5）
+func (it *Iterator) at(c time.Time) time.Time {
+	loc := it.dtstart.Location()
+	h, mi, s := it.dtstart.Clock()
+	wall := time.Date(c.Year(), c.Month(), c.Day(), h, mi, s, it.dtstart.Nanosecond(), time.UTC)
+	guess := time.Date(c.Year(), c.Month(), c.Day(), h, mi, s, it.dtstart.Nanosecond(), loc)
+
+	var found time.Time
+	for _, probe := range []time.Time{guess.Add(-24 * time.Hour), guess, guess.Add(24 * time.Hour)} {
+		_, offset := probe.Zone()
+		t := wall.Add(-time.Duration(offset) * time.Second).In(loc)
+		if sameWallClock(t, wall) && (found.IsZero() || t.Before(found)) {
+			found = t
+		}
+	}
+	if !found.IsZero() {
+		return found
+	}
+
+	_, offset := guess.Add(-24 * time.Hour).Zone()
+	return wall.Add(-time.Duration(offset) * time.Second).In(loc)
+}
+
+// sameWallClock は2つの日時の壁時計時刻が等しいかを判定する
+func sameWallClock(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd && a.Hour() == b.Hour() && a.Minute() == b.Minute() && a.Second() == b.Second()
+}
+
+// civil は日付計算用にUTCの0時を返す（夏時間の影響を受けない）
+func civil(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func containsMonth(months []time.Month, m time.Month) bool {
+	for _, month := range months {
+		if month == m {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,307 @@
+// Package rrule は RFC 5545 の RRULE のサブセットを解析・展開する
+//
+// 対応している項目は FREQ（DAILY, WEEKLY, MONTHLY, YEARLY）, INTERVAL, COUNT,
+// UNTIL, BYMONTH, BYMONTHDAY, BYDAY, WKST のみ
+package rrule
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency は繰り返しの単位
+type Frequency int
+
+// 繰り返しの単位を定義
+const (
+	Daily Frequency = iota + 1
+	Weekly
+	Monthly
+	Yearly
+)
+
+var frequencyNames = map[Frequency]string{
+	Daily:   "DAILY",
+	Weekly:  "WEEKLY",
+	Monthly: "MONTHLY",
+	Yearly:  "YEARLY",
+}
+
+// String は RRULE 上の表記を返す
+func (f Frequency) String() string {
+	return frequencyNames[f]
+}
+
+var weekdayNames = map[time.Weekday]string{
+	time.Sunday:    "SU",
+	time.Monday:    "MO",
+	time.Tuesday:   "TU",
+	time.Wednesday: "WE",
+	time.Thursday:  "TH",
+	time.Friday:    "FR",
+	time.Saturday:  "SA",
+}
+
+// Weekday は BYDAY の1要素
+// N が0以外の場合は期間内の第N曜日（負の値は末尾から数える）を表す
+type Weekday struct {
+	N   int
+	Day time.Weekday
+}
+
+// String は RRULE 上の表記（例: MO, 1MO, -1FR）を返す
+func (w Weekday) String() string {
+	if w.N == 0 {
+		return weekdayNames[w.Day]
+	}
+	return strconv.Itoa(w.N) + weekdayNames[w.Day]
+}
+
+// UNTIL の表記形式
+const (
+	untilUTC      = "20060102T150405Z"
+	untilFloating = "20060102T150405"
+	untilDate     = "20060102"
+)
+
+// Rule は解析済みの繰り返しルール
+type Rule struct {
+	Freq       Frequency
+	Interval   int          // 繰り返しの間隔（1以上）
+	Count      int          // 発生回数の上限（0は無制限）
+	Until      time.Time    // 最終日時（ゼロ値は無制限）
+	ByMonth    []time.Month // 対象の月
+	ByMonthDay []int        // 対象の日（負の値は月末から数える）
+	ByDay      []Weekday    // 対象の曜日
+	WeekStart  time.Weekday // 週の開始曜日（既定は月曜日）
+
+	// untilLayout は UNTIL の表記形式
+	// UTC 以外の形式では Until の日時を展開時のタイムゾーンの壁時計時刻として扱う
+	untilLayout string
+}
+
+// Parse は RRULE 文字列（先頭の "RRULE:" は省略可）を解析する
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(s)), "RRULE:")
+	if s == "" {
+		return nil, fmt.Errorf("繰り返しルールが空です")
+	}
+
+	r := &Rule{Interval: 1, WeekStart: time.Monday}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("項目の形式が不正です: %q", part)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("%s が重複しています", key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			err = r.parseFreq(value)
+		case "INTERVAL":
+			r.Interval, err = parsePositive(key, value)
+		case "COUNT":
+			r.Count, err = parsePositive(key, value)
+		case "UNTIL":
+			err = r.parseUntil(value)
+		case "BYMONTH":
+			err = r.parseByMonth(value)
+		case "BYMONTHDAY":
+			err = r.parseByMonthDay(value)
+		case "BYDAY":
+			err = r.parseByDay(value)
+		case "WKST":
+			r.WeekStart, err = parseWeekday(value)
+		default:
+			err = fmt.Errorf("未対応の項目です: %s", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Rule) parseFreq(value string) error {
+	for f, name := range frequencyNames {
+		if name == value {
+			r.Freq = f
+			return nil
+		}
+	}
+	return fmt.Errorf("未対応の FREQ です: %s", value)
+}
+
+func (r *Rule) parseUntil(value string) error {
+	for _, layout := range []string{untilUTC, untilFloating, untilDate} {
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+		if layout == untilDate {
+			// 日付のみの場合はその日の終わりまでを含める
+			t = t.Add(24*time.Hour - time.Nanosecond)
+		}
+		r.Until = t
+		r.untilLayout = layout
+		return nil
+	}
+	return fmt.Errorf("UNTIL の形式が不正です: %s", value)
+}
+
+func (r *Rule) parseByMonth(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 12 {
+			return fmt.Errorf("BYMONTH は1から12で指定してください: %s", v)
+		}
+		r.ByMonth = append(r.ByMonth, time.Month(n))
+	}
+	return nil
+}
+
+func (r *Rule) parseByMonthDay(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(v)
+		if err != nil || n == 0 || n < -31 || n > 31 {
+			return fmt.Errorf("BYMONTHDAY は1から31または-31から-1で指定してください: %s", v)
+		}
+		r.ByMonthDay = append(r.ByMonthDay, n)
+	}
+	return nil
+}
+
+func (r *Rule) parseByDay(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if len(v) < 2 {
+			return fmt.Errorf("BYDAY の形式が不正です: %s", v)
+		}
+		day, err := parseWeekday(v[len(v)-2:])
+		if err != nil {
+			return err
+		}
+		w := Weekday{Day: day}
+		if prefix := v[:len(v)-2]; prefix != "" {
+			n, err := strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -53 || n > 53 {
+				return fmt.Errorf("BYDAY の序数が不正です: %s", v)
+			}
+			w.N = n
+		}
+		r.ByDay = append(r.ByDay, w)
+	}
+	return nil
+}
+
+// validate は項目同士の組み合わせを検証する
+func (r *Rule) validate() error {
+	if r.Freq == 0 {
+		return fmt.Errorf("FREQ は必須です")
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return fmt.Errorf("COUNT と UNTIL は同時に指定できません")
+	}
+	if r.Freq == Weekly && len(r.ByMonthDay) > 0 {
+		return fmt.Errorf("FREQ=WEEKLY では BYMONTHDAY を指定できません")
+	}
+	for _, w := range r.ByDay {
+		if w.N == 0 {
+			continue
+		}
+		switch {
+		case r.Freq != Monthly && r.Freq != Yearly:
+			return fmt.Errorf("序数付きの BYDAY は FREQ=MONTHLY または YEARLY でのみ指定できます")
+		case (r.Freq == Monthly || len(r.ByMonth) > 0) && (w.N < -5 || w.N > 5):
+			return fmt.Errorf("月内の BYDAY の序数は1から5または-5から-1で指定してください: %s", w)
+		}
+	}
+	return nil
+}
+
+func parsePositive(key, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%s は1以上の整数で指定してください: %s", key, value)
+	}
+	return n, nil
+}
+
+func parseWeekday(value string) (time.Weekday, error) {
+	for day, name := range weekdayNames {
+		if name == value {
+			return day, nil
+		}
+	}
+	return time.Sunday, fmt.Errorf("曜日の形式が不正です: %s", value)
+}
+
+// String は正規化した RRULE 文字列（"RRULE:" は含まない）を返す
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq.String()}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		layout := r.untilLayout
+		if layout == "" {
+			layout = untilUTC
+		}
+		until := r.Until
+		if layout == untilUTC {
+			until = until.UTC()
+		}
+		parts = append(parts, "UNTIL="+until.Format(layout))
+	}
+	if len(r.ByMonth) > 0 {
+		months := make([]string, len(r.ByMonth))
+		for i, m := range r.ByMonth {
+			months[i] = strconv.Itoa(int(m))
+		}
+		parts = append(parts, "BYMONTH="+strings.Join(months, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, w := range r.ByDay {
+			days[i] = w.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayNames[r.WeekStart])
+	}
+	return strings.Join(parts, ";")
+}
+
+// sortedMonths は BYMONTH を重複なく昇順に並べて返す
+func (r *Rule) sortedMonths() []time.Month {
+	var months []time.Month
+	for _, m := range r.ByMonth {
+		if !containsMonth(months, m) {
+			months = append(months, m)
+		}
+	}
+	sort.Slice(months, func(i, j int) bool { return months[i] < months[j] })
+	return months
+}
@@ -0,0 +1,378 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("タイムゾーン %s を読み込めません: %v", name, err)
+	}
+	return loc
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       string
+		expected    string
+		expectError bool
+	}{
+		{name: "毎日", input: "FREQ=DAILY", expected: "FREQ=DAILY"},
+		{name: "RRULE:接頭辞と小文字", input: "rrule:freq=weekly;byday=mo,we", expected: "FREQ=WEEKLY;BYDAY=MO,WE"},
+		{name: "平日", input: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", expected: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"},
+		{name: "第1月曜日", input: "FREQ=MONTHLY;BYDAY=1MO", expected: "FREQ=MONTHLY;BYDAY=1MO"},
+		{name: "最終金曜日", input: "FREQ=MONTHLY;BYDAY=-1FR;COUNT=3", expected: "FREQ=MONTHLY;COUNT=3;BYDAY=-1FR"},
+		{name: "INTERVALとWKST", input: "FREQ=WEEKLY;INTERVAL=2;WKST=SU", expected: "FREQ=WEEKLY;INTERVAL=2;WKST=SU"},
+		{name: "UNTIL(UTC)", input: "FREQ=DAILY;UNTIL=20250131T150000Z", expected: "FREQ=DAILY;UNTIL=20250131T150000Z"},
+		{name: "UNTIL(日付のみ)", input: "FREQ=DAILY;UNTIL=20250131", expected: "FREQ=DAILY;UNTIL=20250131"},
+		{name: "年次", input: "FREQ=YEARLY;BYMONTH=3;BYMONTHDAY=-1", expected: "FREQ=YEARLY;BYMONTH=3;BYMONTHDAY=-1"},
+		{name: "異常系: 空文字", input: "", expectError: true},
+		{name: "異常系: FREQなし", input: "INTERVAL=2", expectError: true},
+		{name: "異常系: 未対応のFREQ", input: "FREQ=HOURLY", expectError: true},
+		{name: "異常系: 未対応の項目", input: "FREQ=MONTHLY;BYSETPOS=-1", expectError: true},
+		{name: "異常系: 項目の重複", input: "FREQ=DAILY;FREQ=WEEKLY", expectError: true},
+		{name: "異常系: INTERVALが0", input: "FREQ=DAILY;INTERVAL=0", expectError: true},
+		{name: "異常系: COUNTとUNTIL", input: "FREQ=DAILY;COUNT=2;UNTIL=20250101", expectError: true},
+		{name: "異常系: 不正な曜日", input: "FREQ=WEEKLY;BYDAY=XX", expectError: true},
+		{name: "異常系: 週次で序数付きBYDAY", input: "FREQ=WEEKLY;BYDAY=1MO", expectError: true},
+		{name: "異常系: 月内で第6曜日", input: "FREQ=MONTHLY;BYDAY=6MO", expectError: true},
+		{name: "異常系: 週次でBYMONTHDAY", input: "FREQ=WEEKLY;BYMONTHDAY=1", expectError: true},
+		{name: "異常系: BYMONTHDAYが0", input: "FREQ=MONTHLY;BYMONTHDAY=0", expectError: true},
+		{name: "異常系: BYMONTHが13", input: "FREQ=YEARLY;BYMONTH=13", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := Parse(tc.input)
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, rule.String())
+		})
+	}
+}
+
+func TestAll(t *testing.T) {
+	tokyo := mustLoad(t, "Asia/Tokyo")
+	date := func(y int, m time.Month, d, h, mi int) time.Time {
+		return time.Date(y, m, d, h, mi, 0, 0, tokyo)
+	}
+
+	testCases := []struct {
+		name     string
+		rule     string
+		dtstart  time.Time
+		limit    int
+		expected []time.Time
+	}{
+		{
+			name:     "毎日",
+			rule:     "FREQ=DAILY",
+			dtstart:  date(2025, 1, 30, 9, 0),
+			limit:    3,
+			expected: []time.Time{date(2025, 1, 30, 9, 0), date(2025, 1, 31, 9, 0), date(2025, 2, 1, 9, 0)},
+		},
+		{
+			name:     "3日おき",
+			rule:     "FREQ=DAILY;INTERVAL=3",
+			dtstart:  date(2025, 2, 27, 9, 0),
+			limit:    3,
+			expected: []time.Time{date(2025, 2, 27, 9, 0), date(2025, 3, 2, 9, 0), date(2025, 3, 5, 9, 0)},
+		},
+		{
+			name:    "平日（金曜日起点）",
+			rule:    "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR",
+			dtstart: date(2025, 4, 4, 7, 30),
+			limit:   4,
+			expected: []time.Time{
+				date(2025, 4, 4, 7, 30), date(2025, 4, 7, 7, 30), date(2025, 4, 8, 7, 30), date(2025, 4, 9, 7, 30),
+			},
+		},
+		{
+			name:     "隔週（BYDAYなしはdtstartの曜日）",
+			rule:     "FREQ=WEEKLY;INTERVAL=2",
+			dtstart:  date(2025, 4, 2, 10, 0),
+			limit:    3,
+			expected: []time.Time{date(2025, 4, 2, 10, 0), date(2025, 4, 16, 10, 0), date(2025, 4, 30, 10, 0)},
+		},
+		{
+			name:    "隔週の月・土（WKST=SU）",
+			rule:    "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,SA;WKST=SU",
+			dtstart: date(2025, 4, 5, 10, 0),
+			limit:   3,
+			expected: []time.Time{
+				date(2025, 4, 5, 10, 0), date(2025, 4, 14, 10, 0), date(2025, 4, 19, 10, 0),
+			},
+		},
+		{
+			name:    "毎月第1月曜日",
+			rule:    "FREQ=MONTHLY;BYDAY=1MO",
+			dtstart: date(2025, 1, 6, 9, 0),
+			limit:   4,
+			expected: []time.Time{
+				date(2025, 1, 6, 9, 0), date(2025, 2, 3, 9, 0), date(2025, 3, 3, 9, 0), date(2025, 4, 7, 9, 0),
+			},
+		},
+		{
+			name:    "毎月最終金曜日",
+			rule:    "FREQ=MONTHLY;BYDAY=-1FR",
+			dtstart: date(2025, 1, 1, 18, 0),
+			limit:   3,
+			expected: []time.Time{
+				date(2025, 1, 31, 18, 0), date(2025, 2, 28, 18, 0), date(2025, 3, 28, 18, 0),
+			},
+		},
+		{
+			name:    "31日は存在しない月を飛ばす",
+			rule:    "FREQ=MONTHLY",
+			dtstart: date(2025, 1, 31, 9, 0),
+			limit:   3,
+			expected: []time.Time{
+				date(2025, 1, 31, 9, 0), date(2025, 3, 31, 9, 0), date(2025, 5, 31, 9, 0),
+			},
+		},
+		{
+			name:    "毎月末日",
+			rule:    "FREQ=MONTHLY;BYMONTHDAY=-1",
+			dtstart: date(2024, 1, 15, 9, 0),
+			limit:   3,
+			expected: []time.Time{
+				date(2024, 1, 31, 9, 0), date(2024, 2, 29, 9, 0), date(2024, 3, 31, 9, 0),
+			},
+		},
+		{
+			name:    "13日の金曜日",
+			rule:    "FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13",
+			dtstart: date(2025, 1, 1, 0, 0),
+			limit:   2,
+			expected: []time.Time{
+				date(2025, 6, 13, 0, 0), date(2026, 2, 13, 0, 0),
+			},
+		},
+		{
+			name:    "うるう日は4年ごと",
+			rule:    "FREQ=YEARLY",
+			dtstart: date(2024, 2, 29, 9, 0),
+			limit:   2,
+			expected: []time.Time{
+				date(2024, 2, 29, 9, 0), date(2028, 2, 29, 9, 0),
+			},
+		},
+		{
+			name:    "毎年11月の第4木曜日",
+			rule:    "FREQ=YEARLY;BYMONTH=11;BYDAY=4TH",
+			dtstart: date(2025, 1, 1, 12, 0),
+			limit:   2,
+			expected: []time.Time{
+				date(2025, 11, 27, 12, 0), date(2026, 11, 26, 12, 0),
+			},
+		},
+		{
+			name:    "年内の第20月曜日",
+			rule:    "FREQ=YEARLY;BYDAY=20MO",
+			dtstart: date(2025, 1, 1, 8, 0),
+			limit:   2,
+			expected: []time.Time{
+				date(2025, 5, 19, 8, 0), date(2026, 5, 18, 8, 0),
+			},
+		},
+		{
+			name:     "COUNTで打ち切り",
+			rule:     "FREQ=DAILY;COUNT=2",
+			dtstart:  date(2025, 1, 1, 9, 0),
+			limit:    10,
+			expected: []time.Time{date(2025, 1, 1, 9, 0), date(2025, 1, 2, 9, 0)},
+		},
+		{
+			name:     "UNTIL(UTC)を含む",
+			rule:     "FREQ=DAILY;UNTIL=20250103T000000Z",
+			dtstart:  date(2025, 1, 1, 9, 0),
+			limit:    10,
+			expected: []time.Time{date(2025, 1, 1, 9, 0), date(2025, 1, 2, 9, 0), date(2025, 1, 3, 9, 0)},
+		},
+		{
+			name:     "UNTIL(日付のみ)は現地時間のその日を含む",
+			rule:     "FREQ=DAILY;UNTIL=20250102",
+			dtstart:  date(2025, 1, 1, 23, 0),
+			limit:    10,
+			expected: []time.Time{date(2025, 1, 1, 23, 0), date(2025, 1, 2, 23, 0)},
+		},
+		{
+			name:     "発生しないルール",
+			rule:     "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30",
+			dtstart:  date(2025, 1, 1, 9, 0),
+			limit:    1,
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := Parse(tc.rule)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, rule.All(tc.dtstart, tc.limit))
+		})
+	}
+}
+
+func TestAllAcrossDST(t *testing.T) {
+	newYork := mustLoad(t, "America/New_York")
+	berlin := mustLoad(t, "Europe/Berlin")
+	lordHowe := mustLoad(t, "Australia/Lord_Howe")
+
+	testCases := []struct {
+		name     string
+		rule     string
+		dtstart  time.Time
+		limit    int
+		expected []string // RFC 3339 表記
+	}{
+		{
+			name:     "夏時間の開始をまたいでも壁時計時刻を保つ",
+			rule:     "FREQ=DAILY",
+			dtstart:  time.Date(2025, 3, 8, 9, 0, 0, 0, newYork),
+			limit:    3,
+			expected: []string{"2025-03-08T09:00:00-05:00", "2025-03-09T09:00:00-04:00", "2025-03-10T09:00:00-04:00"},
+		},
+		{
+			name:     "夏時間の終了をまたいでも壁時計時刻を保つ",
+			rule:     "FREQ=WEEKLY",
+			dtstart:  time.Date(2025, 10, 20, 9, 0, 0, 0, berlin),
+			limit:    2,
+			expected: []string{"2025-10-20T09:00:00+02:00", "2025-10-27T09:00:00+01:00"},
+		},
+		{
+			name:     "存在しない時刻は切り替え前のオフセットで解釈する",
+			rule:     "FREQ=DAILY",
+			dtstart:  time.Date(2025, 3, 8, 2, 30, 0, 0, newYork),
+			limit:    3,
+			expected: []string{"2025-03-08T02:30:00-05:00", "2025-03-09T03:30:00-04:00", "2025-03-10T02:30:00-04:00"},
+		},
+		{
+			name:     "30分ずれる夏時間でも切り替え前のオフセットで解釈する",
+			rule:     "FREQ=DAILY",
+			dtstart:  time.Date(2025, 10, 4, 2, 15, 0, 0, lordHowe),
+			limit:    2,
+			expected: []string{"2025-10-04T02:15:00+10:30", "2025-10-05T02:45:00+11:00"},
+		},
+		{
+			name:     "2回現れる時刻は早い方（西半球）",
+			rule:     "FREQ=DAILY",
+			dtstart:  time.Date(2025, 11, 1, 1, 30, 0, 0, newYork),
+			limit:    2,
+			expected: []string{"2025-11-01T01:30:00-04:00", "2025-11-02T01:30:00-04:00"},
+		},
+		{
+			name:     "2回現れる時刻は早い方（東半球）",
+			rule:     "FREQ=DAILY",
+			dtstart:  time.Date(2025, 10, 25, 2, 30, 0, 0, berlin),
+			limit:    2,
+			expected: []string{"2025-10-25T02:30:00+02:00", "2025-10-26T02:30:00+02:00"},
+		},
+		{
+			name:     "UNTIL(UTC)は絶対時刻で比較する",
+			rule:     "FREQ=DAILY;UNTIL=20250309T130000Z",
+			dtstart:  time.Date(2025, 3, 8, 9, 0, 0, 0, newYork),
+			limit:    10,
+			expected: []string{"2025-03-08T09:00:00-05:00", "2025-03-09T09:00:00-04:00"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := Parse(tc.rule)
+			assert.NoError(t, err)
+
+			var actual []string
+			for _, occurrence := range rule.All(tc.dtstart, tc.limit) {
+				actual = append(actual, occurrence.Format(time.RFC3339))
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestAfter(t *testing.T) {
+	tokyo := mustLoad(t, "Asia/Tokyo")
+	rule, err := Parse("FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR")
+	assert.NoError(t, err)
+
+	dtstart := time.Date(2025, 4, 1, 9, 0, 0, 0, tokyo)
+	next, ok := rule.After(dtstart, time.Date(2025, 4, 4, 12, 0, 0, 0, tokyo))
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2025, 4, 7, 9, 0, 0, 0, tokyo), next)
+}
+
+func TestAdvance(t *testing.T) {
+	tokyo := mustLoad(t, "Asia/Tokyo")
+
+	testCases := []struct {
+		name          string
+		rule          string
+		dtstart       time.Time
+		expectedNext  time.Time
+		expectedRule  string
+		expectedFound bool
+	}{
+		{
+			name:          "次の平日",
+			rule:          "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR",
+			dtstart:       time.Date(2025, 4, 4, 9, 0, 0, 0, tokyo),
+			expectedNext:  time.Date(2025, 4, 7, 9, 0, 0, 0, tokyo),
+			expectedRule:  "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR",
+			expectedFound: true,
+		},
+		{
+			name:          "COUNTを1減らす",
+			rule:          "FREQ=DAILY;COUNT=3",
+			dtstart:       time.Date(2025, 4, 1, 9, 0, 0, 0, tokyo),
+			expectedNext:  time.Date(2025, 4, 2, 9, 0, 0, 0, tokyo),
+			expectedRule:  "FREQ=DAILY;COUNT=2",
+			expectedFound: true,
+		},
+		{
+			name:          "dtstartがルールに一致しない場合はCOUNTを減らさない",
+			rule:          "FREQ=MONTHLY;COUNT=2;BYDAY=1MO",
+			dtstart:       time.Date(2025, 4, 2, 9, 0, 0, 0, tokyo),
+			expectedNext:  time.Date(2025, 4, 7, 9, 0, 0, 0, tokyo),
+			expectedRule:  "FREQ=MONTHLY;COUNT=2;BYDAY=1MO",
+			expectedFound: true,
+		},
+		{
+			name:          "COUNTを使い切った",
+			rule:          "FREQ=DAILY;COUNT=1",
+			dtstart:       time.Date(2025, 4, 1, 9, 0, 0, 0, tokyo),
+			expectedFound: false,
+		},
+		{
+			name:          "UNTILを過ぎた",
+			rule:          "FREQ=DAILY;UNTIL=20250401",
+			dtstart:       time.Date(2025, 4, 1, 9, 0, 0, 0, tokyo),
+			expectedFound: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := Parse(tc.rule)
+			assert.NoError(t, err)
+
+			next, rest, ok := rule.Advance(tc.dtstart)
+			assert.Equal(t, tc.expectedFound, ok)
+			if !tc.expectedFound {
+				return
+			}
+			assert.Equal(t, tc.expectedNext, next)
+			assert.Equal(t, tc.expectedRule, rest.String())
+			assert.Equal(t, tc.rule, rule.String(), "元のルールは変更しない")
+		})
+	}
+}
@@ -10,6 +10,9 @@ import (
 // タイトルの最大文字数
 const MaxTitleLength = 100
 
+// 説明の最大文字数
+const MaxDescriptionLength = 2000
+
 // SQLインジェクションを防ぐための禁止ワード
 var dangerousSQLPatterns = []*regexp.Regexp{
     regexp.MustCompile(`(?i)\b(INSERT\s+INTO|DELETE\s+FROM|UPDATE\s+\w+\s+SET|DROP\s+TABLE|ALTER\s+TABLE)\b`),
@@ -50,6 +53,23 @@ func ValidateTitle(title string) (bool, string) {
 	return true, ""
 }
 
+// 説明のバリデーションを行う（空の説明は許可する）
+// 長文の自由記述のため、SQL構文のチェックは行わない
+func ValidateDescription(description string) (bool, string) {
+	// 長さチェック
+	if utf8.RuneCountInString(description) > MaxDescriptionLength {
+		return false, "説明が長すぎます"
+	}
+
+	// XSSの禁止ワードチェック
+	for _, pattern := range dangerousXSSPatterns {
+		if pattern.MatchString(description) {
+			return false, "説明に不正なスクリプトが含まれています"
+		}
+	}
+	return true, ""
+}
+
 // HTMLエスケープ（XSS対策）
 func SanitizeInput(input string) string {
     return html.EscapeString(input)